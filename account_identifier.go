@@ -0,0 +1,51 @@
+package gosnowflake
+
+import (
+	"regexp"
+	"strings"
+)
+
+// accountIdentifierSegment is a dot-separated part of an account identifier.
+var accountIdentifierSegment = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// maxAccountIdentifierSegments is the number of segments of the longest form, account.region.cloud.privatelink.
+const maxAccountIdentifierSegments = 4
+
+// AccountInfo is an account identifier split into the account and the region and the host derived from them.
+type AccountInfo struct {
+	Account string // account name, e.g. myorg-myaccount or the account locator
+	Region  string // part following the account, e.g. us-east-2.aws or privatelink, empty for the default region
+	Host    string // host the driver connects to, e.g. myorg-myaccount.snowflakecomputing.com
+}
+
+// ParseAccountIdentifier parses an account identifier in one of the forms
+//
+//	org-account
+//	account.region
+//	account.region.cloud
+//
+// optionally followed by .privatelink, and returns the account, the region and the derived host.
+// The default region us-west-2 is dropped, as it is not part of the host.
+func ParseAccountIdentifier(s string) (AccountInfo, error) {
+	segments := strings.Split(s, ".")
+	if len(segments) > maxAccountIdentifierSegments {
+		return AccountInfo{}, errInvalidAccountIdentifier(s)
+	}
+	for _, segment := range segments {
+		if !accountIdentifierSegment.MatchString(segment) {
+			return AccountInfo{}, errInvalidAccountIdentifier(s)
+		}
+	}
+	info := AccountInfo{Account: segments[0]}
+	if region := strings.Join(segments[1:], "."); !strings.EqualFold(region, "us-west-2") {
+		info.Region = region
+	} else {
+		logger.Info("Ignoring default region .us-west-2 from Account configuration.")
+	}
+	if info.Region != "" {
+		info.Host = buildHostFromAccountAndRegion(info.Account, info.Region)
+	} else {
+		info.Host = info.Account + defaultDomain
+	}
+	return info, nil
+}
@@ -0,0 +1,45 @@
+package gosnowflake
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseAccountIdentifier(t *testing.T) {
+	testcases := map[string]AccountInfo{
+		"myorg-myaccount":                     {Account: "myorg-myaccount", Host: "myorg-myaccount.snowflakecomputing.com"},
+		"xy12345":                             {Account: "xy12345", Host: "xy12345.snowflakecomputing.com"},
+		"xy12345.eu-central-1":                {Account: "xy12345", Region: "eu-central-1", Host: "xy12345.eu-central-1.snowflakecomputing.com"},
+		"xy12345.us-west-2":                   {Account: "xy12345", Host: "xy12345.snowflakecomputing.com"},
+		"xy12345.us-east-2.aws":               {Account: "xy12345", Region: "us-east-2.aws", Host: "xy12345.us-east-2.aws.snowflakecomputing.com"},
+		"xy12345.cn-north-1.aws":              {Account: "xy12345", Region: "cn-north-1.aws", Host: "xy12345.cn-north-1.aws.snowflakecomputing.cn"},
+		"myorg-myaccount.privatelink":         {Account: "myorg-myaccount", Region: "privatelink", Host: "myorg-myaccount.privatelink.snowflakecomputing.com"},
+		"xy12345.eu-central-1.privatelink":    {Account: "xy12345", Region: "eu-central-1.privatelink", Host: "xy12345.eu-central-1.privatelink.snowflakecomputing.com"},
+		"xy12345.east-us-2.azure.privatelink": {Account: "xy12345", Region: "east-us-2.azure.privatelink", Host: "xy12345.east-us-2.azure.privatelink.snowflakecomputing.com"},
+	}
+	for accountIdentifier, expected := range testcases {
+		t.Run(accountIdentifier, func(t *testing.T) {
+			info, err := ParseAccountIdentifier(accountIdentifier)
+			assertNilF(t, err)
+			assertEqualE(t, info, expected)
+		})
+	}
+
+	for _, accountIdentifier := range []string{"", "xy12345.", ".eu-central-1", "my account", "myorg-myaccount/db", "a.b.c.d.e"} {
+		t.Run("invalid "+accountIdentifier, func(t *testing.T) {
+			_, err := ParseAccountIdentifier(accountIdentifier)
+			var se *SnowflakeError
+			assertTrueF(t, errors.As(err, &se))
+			assertEqualE(t, se.Number, ErrCodeInvalidAccountIdentifier)
+		})
+	}
+}
+
+func TestParseDSNWithAccountRejectedByParseAccountIdentifier(t *testing.T) {
+	cfg, err := ParseDSN("u:p@xy12345.east-us-2.azure.privatelink.extra/db")
+	assertNilF(t, err, "the accounts accepted by the earlier versions should be kept")
+	assertEqualE(t, cfg.Account, "xy12345")
+	assertEqualE(t, cfg.Region, "east-us-2.azure.privatelink.extra")
+	assertEqualE(t, cfg.Host, "xy12345.east-us-2.azure.privatelink.extra.snowflakecomputing.com")
+	assertEqualE(t, cfg.Database, "db")
+}
@@ -0,0 +1,83 @@
+package gosnowflake
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// activeRequests tracks the request IDs of the queries being waited for, along with the
+// client of their connection, so that they can be aborted together. The zero value is ready to use.
+type activeRequests struct {
+	mutex sync.Mutex
+	ids   map[UUID]*snowflakeRestful
+}
+
+// add tracks the request ID and returns a function that stops tracking it.
+func (ar *activeRequests) add(requestID UUID, rest *snowflakeRestful) func() {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+	if ar.ids == nil {
+		ar.ids = make(map[UUID]*snowflakeRestful)
+	}
+	ar.ids[requestID] = rest
+	return func() {
+		ar.mutex.Lock()
+		defer ar.mutex.Unlock()
+		delete(ar.ids, requestID)
+	}
+}
+
+func (ar *activeRequests) snapshot() map[UUID]*snowflakeRestful {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+	ids := make(map[UUID]*snowflakeRestful, len(ar.ids))
+	for id, rest := range ar.ids {
+		ids[id] = rest
+	}
+	return ids
+}
+
+// QueryCanceller aborts together the queries run with a context returned by WithQueryCanceller,
+// e.g. during shutdown. It does not hold any connection, so it can be used while database/sql
+// keeps the connections locked for the running queries. The zero value is ready to use.
+type QueryCanceller struct {
+	requests activeRequests
+}
+
+// CancelAll aborts all queries run with the canceller that are still being waited for.
+// An abort request is sent for each of them in parallel, and the queries return an error once
+// Snowflake has aborted them. Queries started after the call are not affected. Queries finishing
+// before their abort request arrives complete normally, and the errors of their abort requests are returned.
+func (qc *QueryCanceller) CancelAll(ctx context.Context) error {
+	requests := qc.requests.snapshot()
+	logger.WithContext(ctx).Infof("aborting %v active requests", len(requests))
+	errs := make([]error, 0, len(requests))
+	var errsMutex sync.Mutex
+	var wg sync.WaitGroup
+	for requestID, rest := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cancelCtx, cancel := context.WithTimeout(ctx, cancelQueryTimeout)
+			defer cancel()
+			if err := rest.FuncCancelQuery(cancelCtx, rest, requestID, cancelQueryTimeout); err != nil {
+				errsMutex.Lock()
+				errs = append(errs, err)
+				errsMutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// trackActiveRequest tracks the request with the QueryCanceller of the context, if any,
+// and returns a function that stops tracking it.
+func trackActiveRequest(ctx context.Context, requestID UUID, rest *snowflakeRestful) func() {
+	qc, ok := ctx.Value(queryCanceller).(*QueryCanceller)
+	if !ok || qc == nil {
+		return func() {}
+	}
+	return qc.requests.add(requestID, rest)
+}
@@ -0,0 +1,90 @@
+package gosnowflake
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQueryCanceller(t *testing.T) {
+	var mutex sync.Mutex
+	aborts := make(map[UUID]chan struct{})
+	started := make(chan UUID, 2)
+	postQueryMock := func(ctx context.Context, _ *snowflakeRestful, _ *url.Values, _ map[string]string, _ []byte, _ time.Duration, requestID UUID, _ *Config) (*execResponse, error) {
+		aborted := make(chan struct{})
+		mutex.Lock()
+		aborts[requestID] = aborted
+		mutex.Unlock()
+		started <- requestID
+		select {
+		case <-aborted:
+			return &execResponse{Success: false, Code: "604", Message: "SQL execution canceled"}, nil
+		case <-time.After(10 * time.Second):
+			return nil, errors.New("the query was not aborted")
+		}
+	}
+	var cancelled []UUID
+	cancelQueryMock := func(_ context.Context, _ *snowflakeRestful, requestID UUID, _ time.Duration) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		cancelled = append(cancelled, requestID)
+		close(aborts[requestID])
+		return nil
+	}
+	sc := &snowflakeConn{
+		cfg: &Config{Params: map[string]*string{}, KeepSessionAlive: true},
+		rest: &snowflakeRestful{
+			FuncPostQuery:   postQueryMock,
+			FuncCancelQuery: cancelQueryMock,
+		},
+		telemetry:           testTelemetry,
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+	}
+	db := sql.OpenDB(mockSnowflakeConnector{sc: sc})
+	defer db.Close()
+	conn, err := db.Conn(context.Background())
+	assertNilF(t, err)
+	defer conn.Close()
+
+	var canceller QueryCanceller
+	ctx := WithQueryCanceller(context.Background(), &canceller)
+	errs := make(chan error, 2)
+	// database/sql keeps conn locked while its query runs, the other query runs on another connection of the pool
+	go func() {
+		_, err := conn.QueryContext(ctx, "SELECT SYSTEM$WAIT(60)")
+		errs <- err
+	}()
+	go func() {
+		_, err := db.QueryContext(ctx, "SELECT SYSTEM$WAIT(60)")
+		errs <- err
+	}()
+	requestIDs := []UUID{<-started, <-started}
+
+	assertNilF(t, canceller.CancelAll(context.Background()))
+	for range 2 {
+		select {
+		case err := <-errs:
+			var se *SnowflakeError
+			assertTrueE(t, errors.As(err, &se))
+			assertEqualE(t, se.Number, 604)
+		case <-time.After(5 * time.Second):
+			t.Fatal("the query was not aborted")
+		}
+	}
+	mutex.Lock()
+	assertEqualE(t, len(cancelled), 2)
+	assertTrueE(t, cancelled[0] != cancelled[1])
+	for _, id := range cancelled {
+		assertTrueE(t, id == requestIDs[0] || id == requestIDs[1])
+	}
+	mutex.Unlock()
+
+	// finished queries are no longer tracked
+	assertNilE(t, canceller.CancelAll(context.Background()))
+	assertEqualE(t, len(cancelled), 2)
+}
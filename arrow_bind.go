@@ -0,0 +1,167 @@
+package gosnowflake
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+// ArrowRecord wraps an arrow.Record so that its rows can be bound as the source of a bulk INSERT.
+// Each column of the record is bound to the placeholder at the same position, and the record
+// must be the only binding of the statement, e.g.
+//
+//	db.Exec("INSERT INTO t VALUES (?, ?)", sf.ArrowRecord(record))
+//
+// Large records are uploaded to the bind stage like other array bindings.
+func ArrowRecord(rec arrow.Record) interface{} {
+	return &arrowRecordBinding{rec: rec}
+}
+
+type arrowRecordBinding struct {
+	rec arrow.Record
+}
+
+// arrowColumnArray is a single column of an arrow.Record bound as an array.
+type arrowColumnArray struct {
+	col arrow.Array
+}
+
+func supportedArrowRecordBind(nv *driver.NamedValue) bool {
+	_, ok := nv.Value.(*arrowRecordBinding)
+	return ok
+}
+
+// expandArrowRecordBindings replaces an arrow record binding with array bindings of its columns.
+func expandArrowRecordBindings(query string, bindings []driver.NamedValue) ([]driver.NamedValue, error) {
+	if len(bindings) != 1 || !supportedArrowRecordBind(&bindings[0]) {
+		return bindings, nil
+	}
+	rec := bindings[0].Value.(*arrowRecordBinding).rec
+	if placeholders := countBindPlaceholders(query); int(rec.NumCols()) != placeholders {
+		return nil, &SnowflakeError{
+			Number:      ErrBindSerialization,
+			Message:     errMsgArrowRecordColumnMismatch,
+			MessageArgs: []interface{}{rec.NumCols(), placeholders},
+		}
+	}
+	columns := make([]driver.NamedValue, rec.NumCols())
+	for i, col := range rec.Columns() {
+		columns[i] = driver.NamedValue{Ordinal: i + 1, Value: &arrowColumnArray{col: col}}
+	}
+	return columns, nil
+}
+
+// countBindPlaceholders counts the positional bind placeholders of a query.
+// Both ? and :N placeholders are recognized outside of quoted strings and identifiers.
+func countBindPlaceholders(query string) int {
+	questionMarks := 0
+	numbered := make(map[string]bool)
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '?':
+			questionMarks++
+		case c == ':' && i+1 < len(query) && '0' <= query[i+1] && query[i+1] <= '9' && (i == 0 || query[i-1] != ':'):
+			j := i + 1
+			for j < len(query) && '0' <= query[j] && query[j] <= '9' {
+				j++
+			}
+			numbered[query[i+1:j]] = true
+			i = j - 1
+		}
+	}
+	return questionMarks + len(numbered)
+}
+
+func arrowColumnToString(col arrow.Array, stream bool) (snowflakeType, []*string, error) {
+	var t snowflakeType
+	var toString func(i int) (string, error)
+	switch c := col.(type) {
+	case *array.Int8:
+		t, toString = fixedType, func(i int) (string, error) { return strconv.FormatInt(int64(c.Value(i)), 10), nil }
+	case *array.Int16:
+		t, toString = fixedType, func(i int) (string, error) { return strconv.FormatInt(int64(c.Value(i)), 10), nil }
+	case *array.Int32:
+		t, toString = fixedType, func(i int) (string, error) { return strconv.FormatInt(int64(c.Value(i)), 10), nil }
+	case *array.Int64:
+		t, toString = fixedType, func(i int) (string, error) { return strconv.FormatInt(c.Value(i), 10), nil }
+	case *array.Uint8:
+		t, toString = fixedType, func(i int) (string, error) { return strconv.FormatUint(uint64(c.Value(i)), 10), nil }
+	case *array.Uint16:
+		t, toString = fixedType, func(i int) (string, error) { return strconv.FormatUint(uint64(c.Value(i)), 10), nil }
+	case *array.Uint32:
+		t, toString = fixedType, func(i int) (string, error) { return strconv.FormatUint(uint64(c.Value(i)), 10), nil }
+	case *array.Uint64:
+		t, toString = fixedType, func(i int) (string, error) { return strconv.FormatUint(c.Value(i), 10), nil }
+	case *array.Decimal128:
+		t, toString = fixedType, func(i int) (string, error) { return c.ValueStr(i), nil }
+	case *array.Float32:
+		t, toString = realType, func(i int) (string, error) { return strconv.FormatFloat(float64(c.Value(i)), 'g', -1, 32), nil }
+	case *array.Float64:
+		t, toString = realType, func(i int) (string, error) { return strconv.FormatFloat(c.Value(i), 'g', -1, 64), nil }
+	case *array.Boolean:
+		t, toString = booleanType, func(i int) (string, error) { return strconv.FormatBool(c.Value(i)), nil }
+	case *array.String:
+		t, toString = textType, func(i int) (string, error) { return c.Value(i), nil }
+	case *array.LargeString:
+		t, toString = textType, func(i int) (string, error) { return c.Value(i), nil }
+	case *array.Binary:
+		t, toString = binaryType, func(i int) (string, error) { return hex.EncodeToString(c.Value(i)), nil }
+	case *array.LargeBinary:
+		t, toString = binaryType, func(i int) (string, error) { return hex.EncodeToString(c.Value(i)), nil }
+	case *array.Date32:
+		t, toString = dateType, func(i int) (string, error) { return arrowDateBindValue(c.Value(i).ToTime(), stream), nil }
+	case *array.Date64:
+		t, toString = dateType, func(i int) (string, error) { return arrowDateBindValue(c.Value(i).ToTime(), stream), nil }
+	case *array.Timestamp:
+		tsType := c.DataType().(*arrow.TimestampType)
+		loc := time.UTC
+		t = timestampNtzType
+		if tsType.TimeZone != "" {
+			var err error
+			if loc, err = time.LoadLocation(tsType.TimeZone); err != nil {
+				return unSupportedType, nil, err
+			}
+			t = timestampTzType
+		}
+		toString = func(i int) (string, error) {
+			return getTimestampBindValue(c.Value(i).ToTime(tsType.Unit).In(loc), stream, t)
+		}
+	default:
+		return unSupportedType, nil, &SnowflakeError{
+			Number:      ErrBindSerialization,
+			Message:     errMsgUnsupportedArrowBindType,
+			MessageArgs: []interface{}{col.DataType()},
+		}
+	}
+	arr := make([]*string, col.Len())
+	for i := range arr {
+		if col.IsNull(i) {
+			continue
+		}
+		v, err := toString(i)
+		if err != nil {
+			return unSupportedType, nil, err
+		}
+		arr[i] = &v
+	}
+	return t, arr, nil
+}
+
+func arrowDateBindValue(x time.Time, stream bool) string {
+	if stream {
+		return x.Format("2006-01-02")
+	}
+	return strconv.FormatInt(x.Unix()*1000, 10)
+}
@@ -0,0 +1,119 @@
+package gosnowflake
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func buildArrowBindRecord(ids []int64, names []string, valid []bool) arrow.Record {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "ID", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "NAME", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+	builder := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer builder.Release()
+	builder.Field(0).(*array.Int64Builder).AppendValues(ids, nil)
+	builder.Field(1).(*array.StringBuilder).AppendValues(names, valid)
+	return builder.NewRecord()
+}
+
+func TestBindingArrowRecord(t *testing.T) {
+	rec := buildArrowBindRecord([]int64{1, 2, 3}, []string{"a", "", "c"}, []bool{true, false, true})
+	defer rec.Release()
+	runDBTest(t, func(dbt *DBTest) {
+		dbt.mustExec("create or replace table test_arrow_bind(id integer, name string)")
+		defer dbt.mustExec("drop table if exists test_arrow_bind")
+
+		res := dbt.mustExec("insert into test_arrow_bind values (?, ?)", ArrowRecord(rec))
+		affected, err := res.RowsAffected()
+		assertNilF(t, err)
+		assertEqualE(t, affected, int64(3))
+
+		rows := dbt.mustQuery("select id, name from test_arrow_bind order by id")
+		defer rows.Close()
+		var ids []int64
+		var names []sql.NullString
+		for rows.Next() {
+			var id int64
+			var name sql.NullString
+			assertNilF(t, rows.Scan(&id, &name))
+			ids = append(ids, id)
+			names = append(names, name)
+		}
+		assertDeepEqualE(t, ids, []int64{1, 2, 3})
+		assertDeepEqualE(t, names, []sql.NullString{{String: "a", Valid: true}, {}, {String: "c", Valid: true}})
+	})
+}
+
+func TestUnitBindingArrowRecord(t *testing.T) {
+	rec := buildArrowBindRecord([]int64{1, 2}, []string{"a", ""}, []bool{true, false})
+	defer rec.Release()
+	var sentBindings map[string]execBindParameter
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful, _ *url.Values, _ map[string]string, body []byte, _ time.Duration, _ UUID, _ *Config) (*execResponse, error) {
+		var req execRequest
+		assertNilF(t, json.Unmarshal(body, &req))
+		sentBindings = req.Bindings
+		return &execResponse{Success: true, Code: "0"}, nil
+	}
+	sc := &snowflakeConn{
+		cfg:               &Config{Params: map[string]*string{}},
+		rest:              &snowflakeRestful{FuncPostQuery: postQueryMock},
+		queryContextCache: (&queryContextCache{}).init(),
+	}
+	nv := driver.NamedValue{Ordinal: 1, Value: ArrowRecord(rec)}
+	assertNilF(t, sc.CheckNamedValue(&nv))
+
+	t.Run("columns are bound as arrays", func(t *testing.T) {
+		_, err := sc.exec(context.Background(), "INSERT INTO t VALUES (?, ?)", false, false, false, []driver.NamedValue{nv})
+		assertNilF(t, err)
+		assertEqualE(t, sentBindings["1"].Type, "FIXED")
+		assertDeepEqualE(t, sentBindings["1"].Value, []any{"1", "2"})
+		assertEqualE(t, sentBindings["2"].Type, "TEXT")
+		assertDeepEqualE(t, sentBindings["2"].Value, []any{"a", nil})
+	})
+
+	t.Run("columns are serialized for the bind stage", func(t *testing.T) {
+		columns, err := expandArrowRecordBindings("INSERT INTO t VALUES (:1, :2)", []driver.NamedValue{nv})
+		assertNilF(t, err)
+		bu := &bindUploader{ctx: context.Background(), sc: sc}
+		csvRows, err := bu.buildRowsAsBytes(columns)
+		assertNilF(t, err)
+		assertDeepEqualE(t, csvRows, [][]byte{[]byte("1,a\n"), []byte("2,\n")})
+	})
+
+	t.Run("column count must match placeholders", func(t *testing.T) {
+		_, err := sc.exec(context.Background(), "INSERT INTO t (id) VALUES (?)", false, false, false, []driver.NamedValue{nv})
+		var se *SnowflakeError
+		assertTrueF(t, errors.As(err, &se))
+		assertEqualE(t, se.Number, ErrBindSerialization)
+		assertStringContainsE(t, se.Error(), "arrow record has 2 columns, but the statement has 1 bind placeholders")
+	})
+}
+
+func TestCountBindPlaceholders(t *testing.T) {
+	testcases := []struct {
+		query    string
+		expected int
+	}{
+		{"INSERT INTO t VALUES (?, ?, ?)", 3},
+		{"INSERT INTO t VALUES (:1, :2, :1)", 2},
+		{"INSERT INTO t SELECT '?', \"a?\", ?", 1},
+		{"SELECT '2024-01-01 10:00:00'::timestamp, :1", 1},
+		{"SELECT a::number FROM t", 0},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.query, func(t *testing.T) {
+			assertEqualE(t, countBindPlaceholders(tc.query), tc.expected)
+		})
+	}
+}
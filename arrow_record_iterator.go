@@ -0,0 +1,109 @@
+package gosnowflake
+
+import (
+	"context"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// ArrowRecordIterator iterates over the arrow.Record batches of an Arrow based result set.
+// The chunks of the result are downloaded ahead of the consumer, using up to
+// MaxChunkDownloadWorkers goroutines at a time.
+// The records are owned by the caller and should be released when no longer needed.
+type ArrowRecordIterator struct {
+	cancel  context.CancelFunc
+	results []chan arrowBatchResult
+	idx     int
+	records []arrow.Record
+	recIdx  int
+	current arrow.Record
+	schema  *arrow.Schema
+	err     error
+}
+
+type arrowBatchResult struct {
+	records *[]arrow.Record
+	err     error
+}
+
+func newArrowRecordIterator(ctx context.Context, batches []*ArrowBatch) *ArrowRecordIterator {
+	ctx, cancel := context.WithCancel(ctx)
+	it := &ArrowRecordIterator{
+		cancel:  cancel,
+		results: make([]chan arrowBatchResult, len(batches)),
+	}
+	for i := range it.results {
+		it.results[i] = make(chan arrowBatchResult, 1)
+	}
+	go it.download(ctx, batches)
+	return it
+}
+
+func (it *ArrowRecordIterator) download(ctx context.Context, batches []*ArrowBatch) {
+	workers := make(chan struct{}, intMax(MaxChunkDownloadWorkers, 1))
+	for i, batch := range batches {
+		select {
+		case workers <- struct{}{}:
+		case <-ctx.Done():
+			for j := i; j < len(batches); j++ {
+				it.results[j] <- arrowBatchResult{err: ctx.Err()}
+			}
+			return
+		}
+		go func(i int, batch *ArrowBatch) {
+			defer func() { <-workers }()
+			records, err := batch.WithContext(ctx).Fetch()
+			it.results[i] <- arrowBatchResult{records, err}
+		}(i, batch)
+	}
+}
+
+// Next advances the iterator to the next record. It returns false when there
+// are no more records or an error occurred, which is then reported by Err.
+func (it *ArrowRecordIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	for it.recIdx >= len(it.records) {
+		if it.idx >= len(it.results) {
+			return false
+		}
+		res := <-it.results[it.idx]
+		it.idx++
+		if res.err != nil {
+			it.err = res.err
+			return false
+		}
+		it.records = nil
+		if res.records != nil {
+			it.records = *res.records
+		}
+		it.recIdx = 0
+	}
+	it.current = it.records[it.recIdx]
+	it.recIdx++
+	if it.schema == nil {
+		it.schema = it.current.Schema()
+	}
+	return true
+}
+
+// Record returns the current record.
+func (it *ArrowRecordIterator) Record() arrow.Record {
+	return it.current
+}
+
+// Schema returns the schema of the records. It is nil until the first call to Next returns true.
+func (it *ArrowRecordIterator) Schema() *arrow.Schema {
+	return it.schema
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *ArrowRecordIterator) Err() error {
+	return it.err
+}
+
+// Close stops downloading the remaining chunks.
+func (it *ArrowRecordIterator) Close() {
+	it.cancel()
+}
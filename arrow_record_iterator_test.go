@@ -0,0 +1,87 @@
+package gosnowflake
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func TestGetArrowRecords(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "ID", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "NAME", Type: arrow.BinaryTypes.String},
+	}, nil)
+	chunks := [][]string{{"a", "b"}, {"c"}, {"d", "e", "f"}}
+	var downloads atomic.Int32
+	scd := &snowflakeChunkDownloader{
+		ctx:               context.Background(),
+		QueryResultFormat: string(arrowFormat),
+		FuncDownloadHelper: func(_ context.Context, scd *snowflakeChunkDownloader, idx int) error {
+			downloads.Add(1)
+			builder := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+			defer builder.Release()
+			for i, name := range chunks[idx] {
+				builder.Field(0).(*array.Int64Builder).Append(int64(idx*10 + i))
+				builder.Field(1).(*array.StringBuilder).Append(name)
+			}
+			records := []arrow.Record{builder.NewRecord()}
+			scd.ArrowBatches[idx].rec = &records
+			return nil
+		},
+	}
+	for i := range chunks {
+		scd.ArrowBatches = append(scd.ArrowBatches, &ArrowBatch{idx: i, scd: scd, funcDownloadHelper: scd.FuncDownloadHelper})
+	}
+	rows := &snowflakeRows{
+		sc:              &snowflakeConn{cfg: &Config{}},
+		ChunkDownloader: scd,
+		ctx:             WithArrowBatches(context.Background()),
+	}
+
+	it, err := rows.GetArrowRecords()
+	assertNilF(t, err)
+	defer it.Close()
+	var ids []int64
+	var names []string
+	for it.Next() {
+		rec := it.Record()
+		for i := 0; i < int(rec.NumRows()); i++ {
+			ids = append(ids, rec.Column(0).(*array.Int64).Value(i))
+			names = append(names, rec.Column(1).(*array.String).Value(i))
+		}
+		rec.Release()
+	}
+	assertNilF(t, it.Err())
+	assertTrueE(t, it.Schema().Equal(schema))
+	assertDeepEqualE(t, ids, []int64{0, 1, 10, 20, 21, 22})
+	assertDeepEqualE(t, names, []string{"a", "b", "c", "d", "e", "f"})
+	assertEqualE(t, downloads.Load(), int32(len(chunks)))
+
+	t.Run("download error stops iteration", func(t *testing.T) {
+		for _, batch := range scd.ArrowBatches {
+			batch.rec = nil
+			batch.funcDownloadHelper = func(context.Context, *snowflakeChunkDownloader, int) error {
+				return errors.New("download failed")
+			}
+		}
+		it, err := rows.GetArrowRecords()
+		assertNilF(t, err)
+		defer it.Close()
+		assertFalseE(t, it.Next())
+		assertNotNilF(t, it.Err())
+		assertStringContainsE(t, it.Err().Error(), "download failed")
+	})
+
+	t.Run("requires arrow batches context", func(t *testing.T) {
+		rows.ctx = context.Background()
+		_, err := rows.GetArrowRecords()
+		var se *SnowflakeError
+		assertTrueF(t, errors.As(err, &se))
+		assertEqualE(t, se.Number, ErrArrowBatchesNotEnabled)
+	})
+}
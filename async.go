@@ -15,6 +15,9 @@ func (sr *snowflakeRestful) processAsync(
 	headers map[string]string,
 	timeout time.Duration,
 	cfg *Config) (*execResponse, error) {
+	// the query keeps running on the server after the submitting call returns, so cancelling
+	// the context of the submission must neither cancel the query nor stop retrieving its results
+	ctx = context.WithoutCancel(ctx)
 	// placeholder object to return to user while retrieving results
 	rows := new(snowflakeRows)
 	res := new(snowflakeResult)
@@ -99,6 +102,7 @@ func (sr *snowflakeRestful) getAsync(
 					res.errChannel <- err
 					return err
 				}
+				res.statementResults = r.(*snowflakeResult).statementResults
 			}
 			res.queryID = respd.Data.QueryID
 			res.errChannel <- nil // mark exec status complete
@@ -117,7 +121,6 @@ func (sr *snowflakeRestful) getAsync(
 				rows.errChannel <- err
 				return err
 			}
-			rows.format = resultFormat(respd.Data.QueryResultFormat)
 			rows.errChannel <- nil // mark query status complete
 		}
 	} else {
@@ -205,7 +208,9 @@ func getQueryResultWithRetriesForAsyncMode(
 			// Once 5 second backoff is reached it will keep retrying with this sleeptime.
 			sleepTime := time.Millisecond * time.Duration(500*retryPattern[retryPatternIndex])
 			logger.WithContext(ctx).Infof("Query execution still in progress. Response code: %v, message: %v Sleep for %v ms", respd.Code, respd.Message, sleepTime)
-			time.Sleep(sleepTime)
+			if err := sleepWithContext(ctx, sleepTime); err != nil {
+				return respd, err
+			}
 			retry++
 
 			if retryPatternIndex < len(retryPattern)-1 {
@@ -1,10 +1,17 @@
 package gosnowflake
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestAsyncMode(t *testing.T) {
@@ -225,3 +232,74 @@ func TestLongRunningAsyncQueryFetchResultByID(t *testing.T) {
 		assertFalseF(t, rows.NextResultSet())
 	})
 }
+
+func TestAsyncModeReturnsBeforeQueryCompletes(t *testing.T) {
+	queryID := "01b2c3d4-0000-1111-0000-000000000002"
+	jsonResponse := func(resp execResponse) *http.Response {
+		body, err := json.Marshal(resp)
+		assertNilF(t, err)
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(body))}
+	}
+	queryCompleted := make(chan struct{})
+	ta := getSimpleTokenAccessor()
+	ta.SetTokens("token", "master", 1)
+	inserted := "1"
+	var cancelled atomic.Bool
+	sr := &snowflakeRestful{
+		Protocol:            "https",
+		Host:                "example.snowflakecomputing.com",
+		Port:                443,
+		TokenAccessor:       ta,
+		FuncPostQuery:       postRestfulQuery,
+		FuncPostQueryHelper: postRestfulQueryHelper,
+		FuncPost: func(context.Context, *snowflakeRestful, *url.URL, map[string]string, []byte, time.Duration, currentTimeProvider, *Config) (*http.Response, error) {
+			return jsonResponse(execResponse{
+				Data:    execResponseData{QueryID: queryID, GetResultURL: "/queries/" + queryID + "/result"},
+				Code:    queryInProgressAsyncCode,
+				Success: true,
+			}), nil
+		},
+		FuncGet: func(ctx context.Context, _ *snowflakeRestful, _ *url.URL, _ map[string]string, _ time.Duration) (*http.Response, error) {
+			<-queryCompleted
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return jsonResponse(execResponse{
+				Data: execResponseData{
+					QueryID:         queryID,
+					StatementTypeID: statementTypeIDDml,
+					RowType:         []execResponseRowType{{Name: "number of rows inserted", Type: "fixed"}},
+					RowSet:          [][]*string{{&inserted}},
+				},
+				Code:    "0",
+				Success: true,
+			}), nil
+		},
+		FuncCancelQuery: func(context.Context, *snowflakeRestful, UUID, time.Duration) error {
+			cancelled.Store(true)
+			return nil
+		},
+	}
+	sc := &snowflakeConn{
+		cfg:                 &Config{Params: map[string]*string{}},
+		rest:                sr,
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+	}
+
+	ctx, cancel := context.WithCancel(WithAsyncMode(context.Background()))
+	res, err := sc.ExecContext(ctx, "INSERT INTO t SELECT * FROM big_table", nil)
+	assertNilF(t, err)
+	sfResult, ok := res.(SnowflakeResult)
+	assertTrueF(t, ok, "expected SnowflakeResult")
+	assertEqualE(t, sfResult.GetQueryID(), queryID)
+	assertEqualE(t, sfResult.GetStatus(), QueryStatusInProgress, "the call must return before the query completes")
+
+	// the submission context is no longer needed once the query was accepted
+	cancel()
+	close(queryCompleted)
+	affected, err := res.RowsAffected()
+	assertNilF(t, err)
+	assertEqualE(t, affected, int64(1))
+	assertFalseE(t, cancelled.Load(), "the query must not be cancelled on the server")
+}
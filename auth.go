@@ -1,6 +1,7 @@
 package gosnowflake
 
 import (
+	"cmp"
 	"context"
 	"crypto/sha256"
 	"crypto/x509"
@@ -9,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
@@ -29,6 +31,8 @@ const (
 	clientStoreTemporaryCredential = "CLIENT_STORE_TEMPORARY_CREDENTIAL"
 	clientRequestMfaToken          = "CLIENT_REQUEST_MFA_TOKEN"
 	idTokenAuthenticator           = "ID_TOKEN"
+	// nextActionPasswordChange is the next action of a login rejected because the password expired
+	nextActionPasswordChange = "PWD_CHANGE"
 )
 
 // AuthType indicates the type of authentication in Snowflake
@@ -243,6 +247,7 @@ type authResponseMain struct {
 	SessionInfo         authResponseSessionInfo `json:"sessionInfo"`
 	TokenURL            string                  `json:"tokenUrl,omitempty"`
 	SSOURL              string                  `json:"ssoUrl,omitempty"`
+	NextAction          string                  `json:"nextAction,omitempty"`
 	ProofKey            string                  `json:"proofKey,omitempty"`
 }
 
@@ -305,7 +310,7 @@ func postAuth(
 		return nil, err
 	}
 	logger.WithContext(ctx).Infof("HTTP: %v, URL: %v, Body: %v", resp.StatusCode, fullURL, b)
-	logger.WithContext(ctx).Infof("Header: %v", resp.Header)
+	logger.WithContext(ctx).Infof("Header: %v", redactHeaders(resp.Header))
 	return nil, &SnowflakeError{
 		Number:      ErrFailedToAuth,
 		SQLState:    SQLStateConnectionRejected,
@@ -358,6 +363,15 @@ func authenticate(
 		OCSPMode:    sc.cfg.ocspMode(),
 		GoVersion:   runtime.Version(),
 	}
+	if sc.cfg.ClientOS != "" {
+		clientEnvironment.Os = sc.cfg.ClientOS
+	}
+	if sc.cfg.ClientOSVersion != "" {
+		clientEnvironment.OsVersion = sc.cfg.ClientOSVersion
+	}
+	if sc.cfg.ClientGoVersion != "" {
+		clientEnvironment.GoVersion = sc.cfg.ClientGoVersion
+	}
 
 	sessionParameters := make(map[string]interface{})
 	paramsMutex.Lock()
@@ -374,6 +388,15 @@ func authenticate(
 	if sc.cfg.ClientStoreTemporaryCredential == ConfigBoolTrue {
 		sessionParameters[clientStoreTemporaryCredential] = true
 	}
+	if sc.cfg.QueryTag != "" {
+		sessionParameters[string(queryTag)] = sc.cfg.QueryTag
+	}
+	if sc.cfg.GeographyOutputFormat != "" {
+		sessionParameters[string(geographyOutputFormat)] = sc.cfg.GeographyOutputFormat
+	}
+	if sc.cfg.GeometryOutputFormat != "" {
+		sessionParameters[string(geometryOutputFormat)] = sc.cfg.GeometryOutputFormat
+	}
 	bodyCreator := func() ([]byte, error) {
 		return createRequestBody(sc, sessionParameters, clientEnvironment, proofKey, samlResponse)
 	}
@@ -401,15 +424,26 @@ func authenticate(
 	}
 	if !respd.Success {
 		logger.WithContext(ctx).Errorln("Authentication FAILED")
+		logStructured(ctx, sc.cfg, slog.LevelError, "login failed",
+			slog.String("authenticator", sc.cfg.Authenticator.String()), slog.String("code", respd.Code), slog.String(slogErrorKey, respd.Message))
 		sc.rest.TokenAccessor.SetTokens("", "", -1)
 		if sessionParameters[clientRequestMfaToken] == true {
-			credentialsStorage.deleteCredential(newMfaTokenSpec(sc.cfg.Host, sc.cfg.User))
+			getCredentialsStorage(sc.cfg).deleteCredential(newMfaTokenSpec(sc.cfg.Host, sc.cfg.User))
 		}
 		if sessionParameters[clientStoreTemporaryCredential] == true && sc.cfg.Authenticator == AuthTypeExternalBrowser {
-			credentialsStorage.deleteCredential(newIDTokenSpec(sc.cfg.Host, sc.cfg.User))
+			getCredentialsStorage(sc.cfg).deleteCredential(newIDTokenSpec(sc.cfg.Host, sc.cfg.User))
 		}
 		if sessionParameters[clientStoreTemporaryCredential] == true && sc.cfg.Authenticator.isOauthNativeFlow() {
-			credentialsStorage.deleteCredential(newOAuthAccessTokenSpec(sc.cfg.OauthTokenRequestURL, sc.cfg.User))
+			getCredentialsStorage(sc.cfg).deleteCredential(newOAuthAccessTokenSpec(sc.cfg.OauthTokenRequestURL, sc.cfg.User))
+		}
+		if respd.Data.NextAction == nextActionPasswordChange {
+			// the user cannot log in until the password is changed, so retrying is pointless
+			return nil, (&SnowflakeError{
+				Number:      ErrPasswordChangeRequired,
+				SQLState:    SQLStateConnectionRejected,
+				Message:     errMsgPasswordChangeRequired,
+				MessageArgs: []any{sc.cfg.User, respd.Code, respd.Message},
+			}).exceptionTelemetry(sc)
 		}
 		code, err := strconv.Atoi(respd.Code)
 		if err != nil {
@@ -422,14 +456,16 @@ func authenticate(
 		}).exceptionTelemetry(sc)
 	}
 	logger.WithContext(ctx).Info("Authentication SUCCESS")
+	logStructured(ctx, sc.cfg, slog.LevelInfo, "login succeeded",
+		slog.String("authenticator", sc.cfg.Authenticator.String()), slog.Int64(slogSessionIDKey, respd.Data.SessionID))
 	sc.rest.TokenAccessor.SetTokens(respd.Data.Token, respd.Data.MasterToken, respd.Data.SessionID)
 	if sessionParameters[clientRequestMfaToken] == true {
 		token := respd.Data.MfaToken
-		credentialsStorage.setCredential(newMfaTokenSpec(sc.cfg.Host, sc.cfg.User), token)
+		getCredentialsStorage(sc.cfg).setCredential(newMfaTokenSpec(sc.cfg.Host, sc.cfg.User), token)
 	}
 	if sessionParameters[clientStoreTemporaryCredential] == true {
 		token := respd.Data.IDToken
-		credentialsStorage.setCredential(newIDTokenSpec(sc.cfg.Host, sc.cfg.User), token)
+		getCredentialsStorage(sc.cfg).setCredential(newIDTokenSpec(sc.cfg.Host, sc.cfg.User), token)
 	}
 	return &respd.Data, nil
 }
@@ -586,13 +622,19 @@ func prepareJWTToken(config *Config) (string, error) {
 	accountName := extractAccountName(config.Account)
 	userName := strings.ToUpper(config.User)
 
+	expireTimeout := config.JWTExpireTimeout
+	if expireTimeout > maxJWTTimeout {
+		logger.Warnf("JWT timeout %v is longer than the maximum accepted by the server, using %v", expireTimeout, maxJWTTimeout)
+		expireTimeout = maxJWTTimeout
+	}
+
 	issueAtTime := time.Now().UTC()
 	jwtClaims := jwt.MapClaims{
-		"iss": fmt.Sprintf("%s.%s.%s", accountName, userName, "SHA256:"+base64.StdEncoding.EncodeToString(hash[:])),
-		"sub": fmt.Sprintf("%s.%s", accountName, userName),
+		"iss": cmp.Or(config.JWTIssuer, fmt.Sprintf("%s.%s.%s", accountName, userName, "SHA256:"+base64.StdEncoding.EncodeToString(hash[:]))),
+		"sub": cmp.Or(config.JWTSubject, fmt.Sprintf("%s.%s", accountName, userName)),
 		"iat": issueAtTime.Unix(),
 		"nbf": time.Date(2015, 10, 10, 12, 0, 0, 0, time.UTC).Unix(),
-		"exp": issueAtTime.Add(config.JWTExpireTimeout).Unix(),
+		"exp": issueAtTime.Add(expireTimeout).Unix(),
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwtClaims)
 
@@ -606,6 +648,21 @@ func prepareJWTToken(config *Config) (string, error) {
 	return tokenString, err
 }
 
+// fetchOAuthTokenFromProvider replaces the configured OAuth token with a fresh one
+// if the OAuthTokenProvider is set, so every login, including reconnects, uses a valid token.
+func fetchOAuthTokenFromProvider(sc *snowflakeConn) error {
+	if sc.cfg.Authenticator != AuthTypeOAuth || sc.cfg.OAuthTokenProvider == nil {
+		return nil
+	}
+	token, err := sc.cfg.OAuthTokenProvider(sc.ctx)
+	if err != nil {
+		logger.WithContext(sc.ctx).Errorf("failed to fetch OAuth token from the provider. %v", err)
+		return err
+	}
+	sc.cfg.Token = token
+	return nil
+}
+
 // Authenticate with sc.cfg
 func authenticateWithConfig(sc *snowflakeConn) error {
 	var authData *authResponseMain
@@ -619,7 +676,7 @@ func authenticateWithConfig(sc *snowflakeConn) error {
 			sc.cfg.ClientStoreTemporaryCredential = ConfigBoolTrue
 		}
 		if sc.cfg.Authenticator == AuthTypeExternalBrowser && sc.cfg.ClientStoreTemporaryCredential == ConfigBoolTrue {
-			sc.cfg.IDToken = credentialsStorage.getCredential(newIDTokenSpec(sc.cfg.Host, sc.cfg.User))
+			sc.cfg.IDToken = getCredentialsStorage(sc.cfg).getCredential(newIDTokenSpec(sc.cfg.Host, sc.cfg.User))
 		}
 		// Disable console login by default
 		if sc.cfg.DisableConsoleLogin == configBoolNotSet {
@@ -632,10 +689,15 @@ func authenticateWithConfig(sc *snowflakeConn) error {
 			sc.cfg.ClientRequestMfaToken = ConfigBoolTrue
 		}
 		if sc.cfg.ClientRequestMfaToken == ConfigBoolTrue {
-			sc.cfg.MfaToken = credentialsStorage.getCredential(newMfaTokenSpec(sc.cfg.Host, sc.cfg.User))
+			sc.cfg.MfaToken = getCredentialsStorage(sc.cfg).getCredential(newMfaTokenSpec(sc.cfg.Host, sc.cfg.User))
 		}
 	}
 
+	if err = fetchOAuthTokenFromProvider(sc); err != nil {
+		sc.cleanup()
+		return err
+	}
+
 	logger.WithContext(sc.ctx).Infof("Authenticating via %v", sc.cfg.Authenticator.String())
 	switch sc.cfg.Authenticator {
 	case AuthTypeExternalBrowser:
@@ -664,7 +726,7 @@ func authenticateWithConfig(sc *snowflakeConn) error {
 	if err != nil {
 		var se *SnowflakeError
 		if errors.As(err, &se) && slices.Contains(refreshOAuthTokenErrorCodes, strconv.Itoa(se.Number)) {
-			credentialsStorage.deleteCredential(newOAuthAccessTokenSpec(sc.cfg.OauthTokenRequestURL, sc.cfg.User))
+			getCredentialsStorage(sc.cfg).deleteCredential(newOAuthAccessTokenSpec(sc.cfg.OauthTokenRequestURL, sc.cfg.User))
 
 			if sc.cfg.Authenticator == AuthTypeOAuthAuthorizationCode {
 				var oauthClient *oauthClient
@@ -673,13 +735,20 @@ func authenticateWithConfig(sc *snowflakeConn) error {
 				} else {
 					if err = oauthClient.refreshToken(); err != nil {
 						logger.Warnf("cannot refresh token. %v", err)
-						credentialsStorage.deleteCredential(newOAuthRefreshTokenSpec(sc.cfg.OauthTokenRequestURL, sc.cfg.User))
+						getCredentialsStorage(sc.cfg).deleteCredential(newOAuthRefreshTokenSpec(sc.cfg.OauthTokenRequestURL, sc.cfg.User))
 					}
 				}
 			}
 
 			// if refreshing succeeds for authorization code, we will take a token from cache
 			// if it fails, we will just run the full flow
+			if err = fetchOAuthTokenFromProvider(sc); err == nil {
+				authData, err = authenticate(sc.ctx, sc, nil, nil)
+			}
+		}
+		if errors.As(err, &se) && sc.cfg.Authenticator == AuthTypeJwt && sc.cfg.PrivateKeySecondary != nil && strconv.Itoa(se.Number) == invalidJWTTokenCode {
+			logger.WithContext(sc.ctx).Warn("JWT signed with the primary private key was rejected, retrying with the secondary private key")
+			sc.cfg.PrivateKey, sc.cfg.PrivateKeySecondary = sc.cfg.PrivateKeySecondary, sc.cfg.PrivateKey
 			authData, err = authenticate(sc.ctx, sc, nil, nil)
 		}
 		if err != nil {
@@ -688,6 +757,14 @@ func authenticateWithConfig(sc *snowflakeConn) error {
 		}
 	}
 	sc.populateSessionParameters(authData.Parameters)
+	if onSessionParameters := sc.cfg.OnSessionParameters; onSessionParameters != nil {
+		parameters := make(map[string]any, len(authData.Parameters))
+		for _, param := range authData.Parameters {
+			parameters[param.Name] = param.Value
+		}
+		// a slow callback must not delay the login
+		go onSessionParameters(parameters)
+	}
 	sc.ctx = context.WithValue(sc.ctx, SFSessionIDKey, authData.SessionID)
 	return nil
 }
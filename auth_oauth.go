@@ -28,6 +28,7 @@ const (
 OAuth authentication completed successfully.
 </body></html>`
 	localApplicationClientCredentials = "LOCAL_APPLICATION"
+	oauthErrorAccessDenied            = "access_denied"
 )
 
 var defaultAuthorizationCodeProviderFactory = func() authorizationCodeProvider {
@@ -63,7 +64,7 @@ func newOauthClient(ctx context.Context, cfg *Config) (*oauthClient, error) {
 
 	redirectURITemplate := ""
 	if cfg.OauthRedirectURI == "" {
-		redirectURITemplate = "http://127.0.0.1:%v/"
+		redirectURITemplate = "http://" + net.JoinHostPort(cmp.Or(cfg.OauthRedirectHost, "127.0.0.1"), "%v") + "/"
 	}
 	logger.Debugf("Redirect URI template: %v, port: %v", redirectURITemplate, port)
 
@@ -89,11 +90,11 @@ type oauthBrowserResult struct {
 func (oauthClient *oauthClient) authenticateByOAuthAuthorizationCode() (string, error) {
 	accessTokenSpec := oauthClient.accessTokenSpec()
 	if oauthClient.cfg.ClientStoreTemporaryCredential == ConfigBoolTrue {
-		if accessToken := credentialsStorage.getCredential(accessTokenSpec); accessToken != "" {
+		if accessToken := getCredentialsStorage(oauthClient.cfg).getCredential(accessTokenSpec); accessToken != "" {
 			logger.Debugf("Access token retrieved from cache")
 			return accessToken, nil
 		}
-		if refreshToken := credentialsStorage.getCredential(oauthClient.refreshTokenSpec()); refreshToken != "" {
+		if refreshToken := getCredentialsStorage(oauthClient.cfg).getCredential(oauthClient.refreshTokenSpec()); refreshToken != "" {
 			return "", &SnowflakeError{Number: ErrMissingAccessATokenButRefreshTokenPresent}
 		}
 	}
@@ -115,12 +116,12 @@ func (oauthClient *oauthClient) authenticateByOAuthAuthorizationCode() (string,
 	})
 	select {
 	case <-time.After(oauthClient.cfg.ExternalBrowserTimeout):
-		return "", errors.New("authentication via browser timed out")
+		return "", &BrowserAuthTimeoutError{AuthType: oauthClient.cfg.Authenticator}
 	case result := <-resultChan:
 		if oauthClient.cfg.ClientStoreTemporaryCredential == ConfigBoolTrue {
 			logger.Debug("saving oauth access token in cache")
-			credentialsStorage.setCredential(oauthClient.accessTokenSpec(), result.accessToken)
-			credentialsStorage.setCredential(oauthClient.refreshTokenSpec(), result.refreshToken)
+			getCredentialsStorage(oauthClient.cfg).setCredential(oauthClient.accessTokenSpec(), result.accessToken)
+			getCredentialsStorage(oauthClient.cfg).setCredential(oauthClient.refreshTokenSpec(), result.refreshToken)
 		}
 		return result.accessToken, result.err
 	}
@@ -181,7 +182,13 @@ func (oauthClient *oauthClient) doAuthenticateByOAuthAuthorizationCode(tcpListen
 }
 
 func (oauthClient *oauthClient) setupListener() (*net.TCPListener, int, error) {
-	tcpListener, err := createLocalTCPListener(oauthClient.port)
+	var tcpListener *net.TCPListener
+	var err error
+	if oauthClient.port == 0 && oauthClient.cfg.OauthRedirectPortRange != "" {
+		tcpListener, err = oauthClient.createListenerInPortRange()
+	} else {
+		tcpListener, err = createLocalTCPListenerOnHost(oauthClient.listenerHost(), oauthClient.port)
+	}
 	if err != nil {
 		return nil, 0, err
 	}
@@ -190,9 +197,43 @@ func (oauthClient *oauthClient) setupListener() (*net.TCPListener, int, error) {
 	return tcpListener, callbackPort, nil
 }
 
+func (oauthClient *oauthClient) listenerHost() string {
+	return cmp.Or(oauthClient.cfg.OauthRedirectHost, "localhost")
+}
+
+// createListenerInPortRange binds the redirect listener to the first free port in the configured range.
+func (oauthClient *oauthClient) createListenerInPortRange() (*net.TCPListener, error) {
+	from, to, err := parsePortRange(oauthClient.cfg.OauthRedirectPortRange)
+	if err != nil {
+		return nil, err
+	}
+	host := oauthClient.listenerHost()
+	for port := from; port <= to; port++ {
+		tcpListener, err := createLocalTCPListenerOnHost(host, port)
+		if err == nil {
+			return tcpListener, nil
+		}
+		logger.Debugf("port %v is not available for oauth redirect listener. %v", port, err)
+	}
+	return nil, &SnowflakeError{
+		Number:      ErrCodeNoFreePortInRange,
+		Message:     errMsgNoFreePortInRange,
+		MessageArgs: []interface{}{oauthClient.cfg.OauthRedirectPortRange, host},
+	}
+}
+
 func (oauthClient *oauthClient) exchangeAccessToken(codeReq *http.Request, state string, oauth2cfg *oauth2.Config, codeVerifier string, responseBodyChan chan string) (*oauth2.Token, error) {
 	queryParams := codeReq.URL.Query()
 	errorMsg := queryParams.Get("error")
+	if errorMsg == oauthErrorAccessDenied {
+		cancelledErr := &BrowserAuthCancelledError{
+			AuthType:    oauthClient.cfg.Authenticator,
+			Reason:      errorMsg,
+			Description: queryParams.Get("error_description"),
+		}
+		responseBodyChan <- html.EscapeString(cancelledErr.Error())
+		return nil, cancelledErr
+	}
 	if errorMsg != "" {
 		errorDesc := queryParams.Get("error_description")
 		errMsg := fmt.Sprintf("error while getting authentication from oauth: %v. Details: %v", errorMsg, errorDesc)
@@ -243,6 +284,9 @@ func (oauthClient *oauthClient) eligibleForDefaultClientCredentials() bool {
 }
 
 func (oauthClient *oauthClient) isSnowflakeAsIDP() bool {
+	if oauthClient.cfg.DisableOAuthURLValidation == ConfigBoolTrue {
+		return true
+	}
 	return (oauthClient.cfg.OauthAuthorizationURL == "" || strings.Contains(oauthClient.cfg.OauthAuthorizationURL, oauthClient.cfg.Host)) &&
 		(oauthClient.cfg.OauthTokenRequestURL == "" || strings.Contains(oauthClient.cfg.OauthTokenRequestURL, oauthClient.cfg.Host))
 }
@@ -344,7 +388,7 @@ func (provider *browserBasedAuthorizationCodeProvider) createCodeVerifier() stri
 func (oauthClient *oauthClient) authenticateByOAuthClientCredentials() (string, error) {
 	accessTokenSpec := oauthClient.accessTokenSpec()
 	if oauthClient.cfg.ClientStoreTemporaryCredential == ConfigBoolTrue {
-		if accessToken := credentialsStorage.getCredential(accessTokenSpec); accessToken != "" {
+		if accessToken := getCredentialsStorage(oauthClient.cfg).getCredential(accessTokenSpec); accessToken != "" {
 			return accessToken, nil
 		}
 	}
@@ -357,7 +401,7 @@ func (oauthClient *oauthClient) authenticateByOAuthClientCredentials() (string,
 		return "", err
 	}
 	if oauthClient.cfg.ClientStoreTemporaryCredential == ConfigBoolTrue {
-		credentialsStorage.setCredential(accessTokenSpec, token.AccessToken)
+		getCredentialsStorage(oauthClient.cfg).setCredential(accessTokenSpec, token.AccessToken)
 	}
 	return token.AccessToken, nil
 }
@@ -380,7 +424,7 @@ func (oauthClient *oauthClient) refreshToken() error {
 		return nil
 	}
 	refreshTokenSpec := newOAuthRefreshTokenSpec(oauthClient.cfg.OauthTokenRequestURL, oauthClient.cfg.User)
-	refreshToken := credentialsStorage.getCredential(refreshTokenSpec)
+	refreshToken := getCredentialsStorage(oauthClient.cfg).getCredential(refreshTokenSpec)
 	if refreshToken == "" {
 		logger.Debug("no refresh token in cache, full flow must be run")
 		return nil
@@ -405,7 +449,7 @@ func (oauthClient *oauthClient) refreshToken() error {
 		if err != nil {
 			return err
 		}
-		credentialsStorage.deleteCredential(refreshTokenSpec)
+		getCredentialsStorage(oauthClient.cfg).deleteCredential(refreshTokenSpec)
 		return errors.New(string(respBody))
 	}
 	var tokenResponse tokenExchangeResponseBody
@@ -413,9 +457,9 @@ func (oauthClient *oauthClient) refreshToken() error {
 		return err
 	}
 	accessTokenSpec := oauthClient.accessTokenSpec()
-	credentialsStorage.setCredential(accessTokenSpec, tokenResponse.AccessToken)
+	getCredentialsStorage(oauthClient.cfg).setCredential(accessTokenSpec, tokenResponse.AccessToken)
 	if tokenResponse.RefreshToken != "" {
-		credentialsStorage.setCredential(refreshTokenSpec, tokenResponse.RefreshToken)
+		getCredentialsStorage(oauthClient.cfg).setCredential(refreshTokenSpec, tokenResponse.RefreshToken)
 	}
 	return nil
 }
@@ -4,8 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
 	"sync"
 	"testing"
 	"time"
@@ -653,6 +656,17 @@ func TestEligibleForDefaultClientCredentials(t *testing.T) {
 				},
 			},
 			expected: false,
+		}, {
+			name: "Non-account host with URL validation disabled",
+			oauthClient: &oauthClient{
+				cfg: &Config{
+					Host:                      "example.snowflakecomputing.com",
+					OauthAuthorizationURL:     "https://example.privatelink.snowflakecomputing.com/oauth/authorize",
+					OauthTokenRequestURL:      "https://example.privatelink.snowflakecomputing.com/oauth/token-request",
+					DisableOAuthURLValidation: ConfigBoolTrue,
+				},
+			},
+			expected: true,
 		},
 	}
 
@@ -712,3 +726,161 @@ func (provider *nonInteractiveAuthorizationCodeProvider) assertResponseBodyConta
 	defer provider.mu.Unlock()
 	assertStringContainsE(provider.t, provider.responseBody, str)
 }
+
+func TestOAuthRedirectPortRange(t *testing.T) {
+	reserveRange := func(t *testing.T, size int) (int, []net.Listener) {
+		for attempt := 0; attempt < 10; attempt++ {
+			probe, err := net.Listen("tcp", "0.0.0.0:0")
+			assertNilF(t, err)
+			from := probe.Addr().(*net.TCPAddr).Port
+			assertNilF(t, probe.Close())
+			if from+size > 65535 {
+				continue
+			}
+			var listeners []net.Listener
+			for port := from; port < from+size; port++ {
+				l, err := net.Listen("tcp", fmt.Sprintf("0.0.0.0:%v", port))
+				if err != nil {
+					break
+				}
+				listeners = append(listeners, l)
+			}
+			if len(listeners) == size {
+				return from, listeners
+			}
+			for _, l := range listeners {
+				assertNilF(t, l.Close())
+			}
+		}
+		t.Fatal("cannot reserve port range")
+		return 0, nil
+	}
+
+	t.Run("picks the only free port", func(t *testing.T) {
+		from, listeners := reserveRange(t, 3)
+		defer func() {
+			for _, l := range listeners[:2] {
+				assertNilF(t, l.Close())
+			}
+		}()
+		freePort := from + 2
+		assertNilF(t, listeners[2].Close())
+
+		client, err := newOauthClient(context.Background(), &Config{
+			OauthRedirectHost:      "127.0.0.1",
+			OauthRedirectPortRange: fmt.Sprintf("%v-%v", from, freePort),
+		})
+		assertNilF(t, err)
+		tcpListener, callbackPort, err := client.setupListener()
+		assertNilF(t, err)
+		defer tcpListener.Close()
+		assertEqualE(t, callbackPort, freePort)
+		assertEqualE(t, tcpListener.Addr().(*net.TCPAddr).IP.String(), "127.0.0.1")
+		assertEqualE(t, client.buildRedirectURI(callbackPort), fmt.Sprintf("http://127.0.0.1:%v/", freePort))
+	})
+
+	t.Run("fails when no port is free", func(t *testing.T) {
+		from, listeners := reserveRange(t, 2)
+		defer func() {
+			for _, l := range listeners {
+				assertNilF(t, l.Close())
+			}
+		}()
+
+		client, err := newOauthClient(context.Background(), &Config{
+			OauthRedirectPortRange: fmt.Sprintf("%v-%v", from, from+1),
+		})
+		assertNilF(t, err)
+		_, _, err = client.setupListener()
+		assertNotNilF(t, err)
+		var se *SnowflakeError
+		assertTrueF(t, errors.As(err, &se))
+		assertEqualE(t, se.Number, ErrCodeNoFreePortInRange)
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		for _, portRange := range []string{"abc", "5000-", "6000-5000", "0-10", "1-70000"} {
+			_, _, err := parsePortRange(portRange)
+			assertNotNilE(t, err, portRange)
+		}
+	})
+}
+
+type deniedAuthorizationCodeProvider struct {
+	nonInteractiveAuthorizationCodeProvider
+}
+
+func (provider *deniedAuthorizationCodeProvider) run(authorizationURL string) error {
+	authURL, err := url.Parse(authorizationURL)
+	if err != nil {
+		return err
+	}
+	redirectURI := authURL.Query().Get("redirect_uri")
+	params := url.Values{}
+	params.Add("error", "access_denied")
+	params.Add("error_description", "The user denied access")
+	params.Add("state", authURL.Query().Get("state"))
+	go func() {
+		resp, err := http.Get(redirectURI + "?" + params.Encode())
+		assertNilF(provider.t, err)
+		respBody, err := io.ReadAll(resp.Body)
+		assertNilF(provider.t, err)
+		provider.mu.Lock()
+		defer provider.mu.Unlock()
+		provider.responseBody = string(respBody)
+	}()
+	return nil
+}
+
+func TestOAuthAuthorizationCodeCancelledByUser(t *testing.T) {
+	cfg := &Config{
+		User:                   "testUser",
+		Role:                   "ANALYST",
+		Authenticator:          AuthTypeOAuthAuthorizationCode,
+		OauthClientID:          "testClientId",
+		OauthClientSecret:      "testClientSecret",
+		OauthAuthorizationURL:  "https://idp.example.com/oauth/authorize",
+		OauthTokenRequestURL:   "https://idp.example.com/oauth/token",
+		ExternalBrowserTimeout: defaultExternalBrowserTimeout,
+	}
+	client, err := newOauthClient(context.Background(), cfg)
+	assertNilF(t, err)
+	authCodeProvider := &deniedAuthorizationCodeProvider{nonInteractiveAuthorizationCodeProvider{t: t}}
+	client.authorizationCodeProviderFactory = func() authorizationCodeProvider {
+		return authCodeProvider
+	}
+
+	_, err = client.authenticateByOAuthAuthorizationCode()
+	assertNotNilF(t, err)
+	var cancelledErr *BrowserAuthCancelledError
+	assertTrueF(t, errors.As(err, &cancelledErr))
+	assertEqualE(t, cancelledErr.AuthType, AuthTypeOAuthAuthorizationCode)
+	assertEqualE(t, cancelledErr.Reason, "access_denied")
+	assertEqualE(t, cancelledErr.Description, "The user denied access")
+	var timeoutErr *BrowserAuthTimeoutError
+	assertFalseE(t, errors.As(err, &timeoutErr))
+	time.Sleep(100 * time.Millisecond)
+	authCodeProvider.assertResponseBodyContains("cancelled by the user")
+}
+
+func TestOAuthAuthorizationCodeTimeoutError(t *testing.T) {
+	cfg := &Config{
+		Authenticator:          AuthTypeOAuthAuthorizationCode,
+		OauthAuthorizationURL:  "https://idp.example.com/oauth/authorize",
+		OauthTokenRequestURL:   "https://idp.example.com/oauth/token",
+		ExternalBrowserTimeout: 100 * time.Millisecond,
+	}
+	client, err := newOauthClient(context.Background(), cfg)
+	assertNilF(t, err)
+	client.authorizationCodeProviderFactory = func() authorizationCodeProvider {
+		return &nonInteractiveAuthorizationCodeProvider{t: t, sleepTime: 200 * time.Millisecond}
+	}
+
+	_, err = client.authenticateByOAuthAuthorizationCode()
+	assertNotNilF(t, err)
+	assertEqualE(t, err.Error(), "authentication via browser timed out")
+	var timeoutErr *BrowserAuthTimeoutError
+	assertTrueF(t, errors.As(err, &timeoutErr))
+	assertEqualE(t, timeoutErr.AuthType, AuthTypeOAuthAuthorizationCode)
+	time.Sleep(200 * time.Millisecond) // awaiting timeout
+}
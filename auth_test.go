@@ -1,6 +1,7 @@
 package gosnowflake
 
 import (
+	"bytes"
 	"cmp"
 	"context"
 	"crypto/rand"
@@ -13,6 +14,8 @@ import (
 	"net/url"
 	"os"
 	"runtime"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -608,6 +611,132 @@ func TestUnitAuthenticateOAuth(t *testing.T) {
 	}
 }
 
+func TestUnitAuthenticateOAuthWithTokenProvider(t *testing.T) {
+	var sentTokens []string
+	postAuthRecordToken := func(ctx context.Context, sr *snowflakeRestful, client *http.Client, params *url.Values, headers map[string]string, bodyCreator bodyCreatorType, timeout time.Duration) (*authResponse, error) {
+		var ar authRequest
+		jsonBody, err := bodyCreator()
+		assertNilF(t, err)
+		assertNilF(t, json.Unmarshal(jsonBody, &ar))
+		sentTokens = append(sentTokens, ar.Data.Token)
+		return postAuthCheckOAuth(ctx, sr, client, params, headers, bodyCreator, timeout)
+	}
+	providerCalls := 0
+	sc := getDefaultSnowflakeConn()
+	sc.cfg.Authenticator = AuthTypeOAuth
+	sc.cfg.Token = "staleToken"
+	sc.cfg.OAuthTokenProvider = func(context.Context) (string, error) {
+		providerCalls++
+		return fmt.Sprintf("token%v", providerCalls), nil
+	}
+	sc.rest.FuncPostAuth = postAuthRecordToken
+	sc.ctx = context.Background()
+
+	assertNilF(t, authenticateWithConfig(sc))
+	// reconnecting logs in again with a fresh token
+	assertNilF(t, authenticateWithConfig(sc))
+	assertDeepEqualE(t, sentTokens, []string{"token1", "token2"})
+
+	sc.cfg.OAuthTokenProvider = func(context.Context) (string, error) {
+		return "", errors.New("provider failure")
+	}
+	err := authenticateWithConfig(sc)
+	assertNotNilF(t, err)
+	assertEqualE(t, err.Error(), "provider failure")
+	assertEqualE(t, len(sentTokens), 2)
+}
+
+func TestUnitAuthenticateOnSessionParameters(t *testing.T) {
+	postAuthWithParameters := func(ctx context.Context, sr *snowflakeRestful, client *http.Client, params *url.Values, headers map[string]string, bodyCreator bodyCreatorType, timeout time.Duration) (*authResponse, error) {
+		resp, err := postAuthCheckOAuth(ctx, sr, client, params, headers, bodyCreator, timeout)
+		if err != nil {
+			return nil, err
+		}
+		resp.Data.Parameters = []nameValueParameter{
+			{Name: "TIMEZONE", Value: "America/Los_Angeles"},
+			{Name: "BINARY_OUTPUT_FORMAT", Value: "HEX"},
+			{Name: "CLIENT_PREFETCH_THREADS", Value: float64(4)},
+		}
+		return resp, nil
+	}
+	received := make(chan map[string]any, 1)
+	sc := getDefaultSnowflakeConn()
+	sc.cfg.Authenticator = AuthTypeOAuth
+	sc.cfg.Token = "oauthToken"
+	sc.cfg.OnSessionParameters = func(parameters map[string]any) {
+		received <- parameters
+	}
+	sc.rest.FuncPostAuth = postAuthWithParameters
+	sc.ctx = context.Background()
+
+	assertNilF(t, authenticateWithConfig(sc))
+	select {
+	case parameters := <-received:
+		assertDeepEqualE(t, parameters, map[string]any{
+			"TIMEZONE":                "America/Los_Angeles",
+			"BINARY_OUTPUT_FORMAT":    "HEX",
+			"CLIENT_PREFETCH_THREADS": float64(4),
+		})
+		// the callback owns its copy
+		delete(parameters, "TIMEZONE")
+		assertEqualE(t, *sc.cfg.Params["timezone"], "America/Los_Angeles")
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnSessionParameters was not called")
+	}
+	select {
+	case <-received:
+		t.Fatal("OnSessionParameters should be called once per login")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestUnitAuthenticateRejectedLogin(t *testing.T) {
+	testcases := []struct {
+		name     string
+		response authResponse
+		is       func(error) bool
+		number   int
+	}{
+		{
+			name: "password expired",
+			response: authResponse{
+				Code:    "390106",
+				Message: "Password expired.",
+				Data:    authResponseMain{NextAction: nextActionPasswordChange},
+			},
+			is:     IsPasswordChangeRequired,
+			number: ErrPasswordChangeRequired,
+		},
+		{
+			name:     "user locked",
+			response: authResponse{Code: "390102", Message: "User temporarily locked."},
+			is:       IsUserLocked,
+			number:   ErrCodeUserLocked,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			logins := 0
+			sc := getDefaultSnowflakeConn()
+			sc.rest.FuncPostAuth = func(_ context.Context, _ *snowflakeRestful, _ *http.Client, _ *url.Values, _ map[string]string, _ bodyCreatorType, _ time.Duration) (*authResponse, error) {
+				logins++
+				resp := tc.response
+				return &resp, nil
+			}
+			sc.ctx = context.Background()
+
+			err := authenticateWithConfig(sc)
+			assertTrueF(t, tc.is(err), fmt.Sprintf("unexpected error: %v", err))
+			var se *SnowflakeError
+			assertTrueF(t, errors.As(err, &se))
+			assertEqualE(t, se.Number, tc.number)
+			assertEqualE(t, se.SQLState, SQLStateConnectionRejected)
+			assertStringContainsE(t, se.Error(), tc.response.Message)
+			assertEqualE(t, logins, 1)
+		})
+	}
+}
+
 func TestUnitAuthenticatePasscode(t *testing.T) {
 	var err error
 	sr := &snowflakeRestful{
@@ -661,6 +790,45 @@ func TestUnitAuthenticateJWT(t *testing.T) {
 	}
 }
 
+func TestPrepareJWTTokenClaims(t *testing.T) {
+	parseClaims := func(cfg *Config) jwt.MapClaims {
+		tokenString, err := prepareJWTToken(cfg)
+		assertNilF(t, err)
+		claims := jwt.MapClaims{}
+		_, err = jwt.ParseWithClaims(tokenString, claims, func(*jwt.Token) (interface{}, error) {
+			return testPrivKey.Public(), nil
+		})
+		assertNilF(t, err)
+		return claims
+	}
+	lifetime := func(claims jwt.MapClaims) time.Duration {
+		exp, err := claims.GetExpirationTime()
+		assertNilF(t, err)
+		iat, err := claims.GetIssuedAt()
+		assertNilF(t, err)
+		return exp.Sub(iat.Time)
+	}
+
+	t.Run("configured lifetime", func(t *testing.T) {
+		claims := parseClaims(&Config{Account: "a", User: "u", PrivateKey: testPrivKey, JWTExpireTimeout: 5 * time.Minute})
+		assertEqualE(t, lifetime(claims), 5*time.Minute)
+		assertEqualE(t, claims["sub"], "A.U")
+		assertStringContainsE(t, claims["iss"].(string), "A.U.SHA256:")
+	})
+
+	t.Run("lifetime is clamped", func(t *testing.T) {
+		claims := parseClaims(&Config{Account: "a", User: "u", PrivateKey: testPrivKey, JWTExpireTimeout: 2 * time.Hour})
+		assertEqualE(t, lifetime(claims), maxJWTTimeout)
+	})
+
+	t.Run("issuer and subject overrides", func(t *testing.T) {
+		claims := parseClaims(&Config{Account: "a", User: "u", PrivateKey: testPrivKey, JWTExpireTimeout: defaultJWTTimeout,
+			JWTIssuer: "ORG-A.U.SHA256:fingerprint", JWTSubject: "ORG-A.U"})
+		assertEqualE(t, claims["iss"], "ORG-A.U.SHA256:fingerprint")
+		assertEqualE(t, claims["sub"], "ORG-A.U")
+	})
+}
+
 func TestUnitAuthenticateUsernamePasswordMfa(t *testing.T) {
 	var err error
 	sr := &snowflakeRestful{
@@ -690,6 +858,70 @@ func TestUnitAuthenticateUsernamePasswordMfa(t *testing.T) {
 	}
 }
 
+func TestUnitAuthenticateJWTWithSecondaryKey(t *testing.T) {
+	primaryKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assertNilF(t, err)
+	var postAuthCalls int
+	// only the public key paired with testPrivKey is registered on the server side
+	postAuthAcceptOnlyTestPrivKey := func(ctx context.Context, sr *snowflakeRestful, client *http.Client, params *url.Values, headers map[string]string, bodyCreator bodyCreatorType, timeout time.Duration) (*authResponse, error) {
+		postAuthCalls++
+		if _, err := postAuthCheckJWTToken(ctx, sr, client, params, headers, bodyCreator, timeout); err != nil {
+			return &authResponse{
+				Success: false,
+				Code:    invalidJWTTokenCode,
+				Message: "JWT token is invalid.",
+			}, nil
+		}
+		return postAuthCheckJWTToken(ctx, sr, client, params, headers, bodyCreator, timeout)
+	}
+
+	t.Run("falls back to secondary key", func(t *testing.T) {
+		postAuthCalls = 0
+		sc := getDefaultSnowflakeConn()
+		sc.cfg.Authenticator = AuthTypeJwt
+		sc.cfg.JWTExpireTimeout = defaultJWTTimeout
+		sc.cfg.PrivateKey = primaryKey
+		sc.cfg.PrivateKeySecondary = testPrivKey
+		sc.rest.FuncPostAuth = postAuthAcceptOnlyTestPrivKey
+		sc.ctx = context.Background()
+
+		assertNilF(t, authenticateWithConfig(sc))
+		assertEqualE(t, postAuthCalls, 2)
+		assertEqualE(t, sc.cfg.PrivateKey, testPrivKey)
+	})
+
+	t.Run("primary key accepted", func(t *testing.T) {
+		postAuthCalls = 0
+		sc := getDefaultSnowflakeConn()
+		sc.cfg.Authenticator = AuthTypeJwt
+		sc.cfg.JWTExpireTimeout = defaultJWTTimeout
+		sc.cfg.PrivateKey = testPrivKey
+		sc.cfg.PrivateKeySecondary = primaryKey
+		sc.rest.FuncPostAuth = postAuthAcceptOnlyTestPrivKey
+		sc.ctx = context.Background()
+
+		assertNilF(t, authenticateWithConfig(sc))
+		assertEqualE(t, postAuthCalls, 1)
+	})
+
+	t.Run("no secondary key", func(t *testing.T) {
+		postAuthCalls = 0
+		sc := getDefaultSnowflakeConn()
+		sc.cfg.Authenticator = AuthTypeJwt
+		sc.cfg.JWTExpireTimeout = defaultJWTTimeout
+		sc.cfg.PrivateKey = primaryKey
+		sc.rest.FuncPostAuth = postAuthAcceptOnlyTestPrivKey
+		sc.ctx = context.Background()
+
+		err := authenticateWithConfig(sc)
+		assertNotNilF(t, err)
+		assertEqualE(t, postAuthCalls, 1)
+		var se *SnowflakeError
+		assertTrueF(t, errors.As(err, &se))
+		assertEqualE(t, strconv.Itoa(se.Number), invalidJWTTokenCode)
+	})
+}
+
 func TestUnitAuthenticateWithConfigMFA(t *testing.T) {
 	var err error
 	sr := &snowflakeRestful{
@@ -1094,3 +1326,72 @@ func TestWithOAuthClientCredentialsFlowManual(t *testing.T) {
 	defer db.Close()
 	runSmokeQuery(t, db)
 }
+
+func TestLoginRequestClientEnvironmentOverrides(t *testing.T) {
+	var clientEnvironment authRequestClientEnvironment
+	sc := getDefaultSnowflakeConn()
+	sc.rest.FuncPostAuth = func(_ context.Context, _ *snowflakeRestful, _ *http.Client, _ *url.Values, _ map[string]string, bodyCreator bodyCreatorType, _ time.Duration) (*authResponse, error) {
+		var ar authRequest
+		jsonBody, err := bodyCreator()
+		assertNilF(t, err)
+		assertNilF(t, json.Unmarshal(jsonBody, &ar))
+		clientEnvironment = ar.Data.ClientEnvironment
+		return &authResponse{
+			Success: true,
+			Data:    authResponseMain{Token: "t", MasterToken: "m"},
+		}, nil
+	}
+	_, err := authenticate(context.Background(), sc, nil, nil)
+	assertNilF(t, err)
+	assertEqualE(t, clientEnvironment.Os, runtime.GOOS)
+	assertEqualE(t, clientEnvironment.GoVersion, runtime.Version())
+
+	cfg, err := ParseDSN("u:p@a?clientOs=linux&clientOsVersion=alpine-3.20&clientGoVersion=go1.23.4")
+	assertNilF(t, err)
+	sc.cfg.ClientOS = cfg.ClientOS
+	sc.cfg.ClientOSVersion = cfg.ClientOSVersion
+	sc.cfg.ClientGoVersion = cfg.ClientGoVersion
+	_, err = authenticate(context.Background(), sc, nil, nil)
+	assertNilF(t, err)
+	assertEqualE(t, clientEnvironment.Os, "linux")
+	assertEqualE(t, clientEnvironment.OsVersion, "alpine-3.20")
+	assertEqualE(t, clientEnvironment.GoVersion, "go1.23.4")
+}
+
+func TestLoginRequestCarriesApplicationName(t *testing.T) {
+	cfg, err := ParseDSN("u:p@a?application=billing-service_v2.1")
+	assertNilF(t, err)
+	sc := getDefaultSnowflakeConn()
+	sc.cfg.Application = cfg.Application
+	sc.rest.FuncPostAuth = func(_ context.Context, _ *snowflakeRestful, _ *http.Client, _ *url.Values, _ map[string]string, bodyCreator bodyCreatorType, _ time.Duration) (*authResponse, error) {
+		var ar authRequest
+		jsonBody, err := bodyCreator()
+		assertNilF(t, err)
+		assertNilF(t, json.Unmarshal(jsonBody, &ar))
+		assertEqualE(t, ar.Data.ClientEnvironment.Application, "billing-service_v2.1")
+		return &authResponse{
+			Success: true,
+			Data:    authResponseMain{Token: "t", MasterToken: "m"},
+		}, nil
+	}
+	_, err = authenticate(context.Background(), sc, nil, nil)
+	assertNilF(t, err)
+
+	logger := GetLogger().(*defaultLogger)
+	initialOutput := logger.inner.Out
+	defer logger.SetOutput(initialOutput)
+	level := logger.GetLogLevel()
+	_ = logger.SetLogLevel("warn")
+	defer func() {
+		_ = logger.SetLogLevel(level)
+	}()
+	var buf bytes.Buffer
+	logger.SetOutput(&buf)
+	for _, application := range []string{"billing service", "1app", "app;drop", "a" + strings.Repeat("b", 51)} {
+		buf.Reset()
+		cfg, err = ParseDSN("u:p@a?application=" + url.QueryEscape(application))
+		assertNilF(t, err, "unusual application names are still accepted")
+		assertEqualE(t, cfg.Application, application)
+		assertStringContainsE(t, buf.String(), "may not be accepted by Snowflake")
+	}
+}
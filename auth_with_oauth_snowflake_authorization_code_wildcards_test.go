@@ -44,7 +44,7 @@ func TestOauthSnowflakeAuthorizationCodeWildcardsMismatchedUsername(t *testing.T
 		err := verifyConnectionToSnowflakeAuthTests(t, cfg)
 		var snowflakeErr *SnowflakeError
 		assertTrueF(t, errors.As(err, &snowflakeErr))
-		assertEqualE(t, snowflakeErr.Number, 390309, fmt.Sprintf("Expected 390309, but got %v", snowflakeErr.Number))
+		assertEqualE(t, snowflakeErr.Number, ErrCodeUsernameMismatch, fmt.Sprintf("Expected %v, but got %v", ErrCodeUsernameMismatch, snowflakeErr.Number))
 	}()
 	wg.Wait()
 }
@@ -5,7 +5,6 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -53,7 +52,13 @@ func buildResponse(body string) (bytes.Buffer, error) {
 // and any anycast IP addresses locally. By specifying "0", we are
 // able to bind to a free port.
 func createLocalTCPListener(port int) (*net.TCPListener, error) {
-	logger.Debugf("creating local TCP listener on port %v", port)
+	return createLocalTCPListenerOnHost("localhost", port)
+}
+
+// createLocalTCPListenerOnHost works like createLocalTCPListener, but the returned
+// listener is bound to the given host instead of localhost.
+func createLocalTCPListenerOnHost(host string, port int) (*net.TCPListener, error) {
+	logger.Debugf("creating local TCP listener on host %v and port %v", host, port)
 	allAddressesListener, err := net.Listen("tcp", fmt.Sprintf("0.0.0.0:%v", port))
 	if err != nil {
 		logger.Warnf("error while setting up 0.0.0.0 listener: %v", err)
@@ -65,7 +70,7 @@ func createLocalTCPListener(port int) (*net.TCPListener, error) {
 		return nil, err
 	}
 
-	l, err := net.Listen("tcp", fmt.Sprintf("localhost:%v", port))
+	l, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
 	if err != nil {
 		logger.Warnf("error while setting up listener: %v", err)
 		return nil, err
@@ -234,7 +239,7 @@ func authenticateByExternalBrowser(
 	)
 	select {
 	case <-time.After(externalBrowserTimeout):
-		return nil, nil, errors.New("authentication timed out")
+		return nil, nil, &BrowserAuthTimeoutError{AuthType: AuthTypeExternalBrowser}
 	case result := <-resultChan:
 		return result.escapedSamlResponse, result.proofKey, result.err
 	}
@@ -136,6 +136,9 @@ func TestAuthenticationTimeout(t *testing.T) {
 	}
 	_, _, err := authenticateByExternalBrowser(context.Background(), sr, authenticator, application, account, user, password, timeout, ConfigBoolTrue)
 	assertEqualE(t, err.Error(), "authentication timed out", err.Error())
+	var timeoutErr *BrowserAuthTimeoutError
+	assertTrueF(t, errors.As(err, &timeoutErr))
+	assertEqualE(t, timeoutErr.AuthType, AuthTypeExternalBrowser)
 }
 
 func Test_createLocalTCPListener(t *testing.T) {
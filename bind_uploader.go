@@ -5,10 +5,12 @@ import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"encoding"
 	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -18,6 +20,9 @@ const (
 
 	// size (in bytes) of max input stream (10MB default) as per JDBC specs
 	inputStreamBufferSize = 1024 * 1024 * 10
+
+	// default maximum number of array bind values sent inline in a single request
+	defaultArrayBindChunkSize = 65280
 )
 
 type bindUploader struct {
@@ -231,6 +236,125 @@ func (sc *snowflakeConn) processBindings(
 	return nil
 }
 
+// arrayBindChunkSize returns the maximum number of array bind values sent inline
+// in a single request. 0 means that array binds are never split.
+func (sc *snowflakeConn) arrayBindChunkSize() int {
+	switch {
+	case sc.cfg.ArrayBindChunkSize < 0:
+		return 0
+	case sc.cfg.ArrayBindChunkSize == 0:
+		return defaultArrayBindChunkSize
+	default:
+		return sc.cfg.ArrayBindChunkSize
+	}
+}
+
+// splitArrayBindings splits an array bind which is sent inline and has more values
+// than the chunk size into chunks of rows, each one under the chunk size.
+// nil is returned when the bindings don't need to be split.
+func (sc *snowflakeConn) splitArrayBindings(bindings []driver.NamedValue) ([][]driver.NamedValue, error) {
+	chunkSize := sc.arrayBindChunkSize()
+	if chunkSize == 0 || !isArrayBind(bindings) {
+		return nil, nil
+	}
+	numBinds, err := arrayBindValueCount(bindings)
+	if err != nil {
+		return nil, err
+	}
+	if numBinds <= chunkSize {
+		return nil, nil
+	}
+	if arrayBindThreshold := sc.getArrayBindStageThreshold(); 0 < arrayBindThreshold && arrayBindThreshold <= numBinds {
+		// uploaded to the bind stage instead
+		return nil, nil
+	}
+	numRows := -1
+	for _, binding := range bindings {
+		if _, ok := binding.Value.(*arrowColumnArray); ok {
+			return nil, nil
+		}
+		if column := reflect.ValueOf(binding.Value); column.Kind() == reflect.Pointer && column.Elem().Kind() == reflect.Slice {
+			if numRows >= 0 && numRows != column.Elem().Len() {
+				// arrays of different lengths are reported by the server
+				return nil, nil
+			}
+			numRows = column.Elem().Len()
+		}
+	}
+	rowsPerChunk := max(chunkSize/len(bindings), 1)
+	var chunks [][]driver.NamedValue
+	for from := 0; from < numRows; from += rowsPerChunk {
+		to := min(from+rowsPerChunk, numRows)
+		chunk := make([]driver.NamedValue, len(bindings))
+		for i, binding := range bindings {
+			chunk[i] = binding
+			if column := reflect.ValueOf(binding.Value); column.Kind() == reflect.Pointer && column.Elem().Kind() == reflect.Slice {
+				rows := reflect.New(column.Elem().Type())
+				rows.Elem().Set(column.Elem().Slice(from, to))
+				chunk[i].Value = rows.Interface()
+			}
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks, nil
+}
+
+// execArrayBindChunks executes the statement once for every chunk of the array bind.
+// Unless the session is in a transaction already, the chunks are executed in a
+// transaction of their own, so that either all or none of the rows are inserted.
+func (sc *snowflakeConn) execArrayBindChunks(ctx context.Context, query string, isInternal bool, chunks [][]driver.NamedValue) (driver.Result, error) {
+	logger.WithContext(ctx).Infof("array bind split into %v requests", len(chunks))
+	inTransaction, err := sc.inTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ownTransaction := !inTransaction
+	if ownTransaction {
+		if _, err := sc.exec(ctx, "BEGIN", false /* noResult */, true /* isInternal */, false /* describeOnly */, nil); err != nil {
+			return nil, err
+		}
+	}
+	result := &snowflakeResult{insertID: -1}
+	for _, chunk := range chunks {
+		data, err := sc.exec(ctx, query, false /* noResult */, isInternal, false /* describeOnly */, chunk)
+		if err == nil {
+			var affectedRows int64
+			if affectedRows, err = updateRows(data.Data); err == nil {
+				result.affectedRows += affectedRows
+				result.queryID = data.Data.QueryID
+				continue
+			}
+		}
+		if ownTransaction {
+			if _, rollbackErr := sc.exec(ctx, "ROLLBACK", false /* noResult */, true /* isInternal */, false /* describeOnly */, nil); rollbackErr != nil {
+				logger.WithContext(ctx).Errorf("failed to roll back the array bind. err: %v", rollbackErr)
+			}
+		}
+		return nil, err
+	}
+	if ownTransaction {
+		if _, err := sc.exec(ctx, "COMMIT", false /* noResult */, true /* isInternal */, false /* describeOnly */, nil); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// inTransaction asks the server whether the session has an open transaction, as it may have
+// been started with a BEGIN statement as well as with BeginTx.
+func (sc *snowflakeConn) inTransaction(ctx context.Context) (bool, error) {
+	rows, err := sc.queryContextInternal(WithInternal(ctx), "SELECT CURRENT_TRANSACTION()", nil)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	dest := make([]driver.Value, 1)
+	if err = rows.Next(dest); err != nil {
+		return false, err
+	}
+	return dest[0] != nil, nil
+}
+
 func getBindValues(bindings []driver.NamedValue, params map[string]*string) (map[string]execBindParameter, error) {
 	tsmode := timestampNtzType
 	idx := 1
@@ -241,11 +365,23 @@ func getBindValues(bindings []driver.NamedValue, params map[string]*string) (map
 			tsmode = convertTzTypeToSnowflakeType(tnt.TzType)
 			binding.Value = tnt.Time
 		}
+		if tb, ok := binding.Value.(TypedBinary); ok {
+			val, err := tb.toHex()
+			if err != nil {
+				return nil, newBindError(binding, idx, binaryType, err)
+			}
+			bindValues[bindingName(binding, idx)] = execBindParameter{
+				Type:  binaryType.String(),
+				Value: val,
+			}
+			idx++
+			continue
+		}
 		t := goTypeToSnowflake(binding.Value, tsmode)
 		if t == changeType {
 			tsmode, err = dataTypeMode(binding.Value)
 			if err != nil {
-				return nil, err
+				return nil, newBindError(binding, idx, unSupportedType, err)
 			}
 		} else {
 			var val interface{}
@@ -254,13 +390,13 @@ func getBindValues(bindings []driver.NamedValue, params map[string]*string) (map
 				// retrieve array binding data
 				t, val, err = snowflakeArrayToString(&binding, false)
 				if err != nil {
-					return nil, err
+					return nil, newBindError(binding, idx, unSupportedType, err)
 				}
 			} else {
 				bv, err = valueToString(binding.Value, tsmode, params)
 				val = bv.value
 				if err != nil {
-					return nil, err
+					return nil, newBindError(binding, idx, t, err)
 				}
 			}
 			if t == nullType || t == unSupportedType {
@@ -282,6 +418,29 @@ func getBindValues(bindings []driver.NamedValue, params map[string]*string) (map
 	return bindValues, nil
 }
 
+// newBindError adds the position and the types of the binding to the conversion error.
+func newBindError(nv driver.NamedValue, idx int, t snowflakeType, err error) error {
+	bindErr := &BindError{
+		Position: nv.Ordinal,
+		Name:     nv.Name,
+		GoType:   fmt.Sprintf("%T", nv.Value),
+		Err:      err,
+	}
+	if bindErr.Position == 0 {
+		bindErr.Position = idx
+	}
+	switch t {
+	case unSupportedType, changeType, nullType, sliceType:
+	case nilObjectType, mapType, nilMapType:
+		bindErr.SnowflakeType = objectType.String()
+	case nilArrayType:
+		bindErr.SnowflakeType = arrayType.String()
+	default:
+		bindErr.SnowflakeType = t.String()
+	}
+	return bindErr
+}
+
 func bindingName(nv driver.NamedValue, idx int) string {
 	if nv.Name != "" {
 		return nv.Name
@@ -321,7 +480,7 @@ func supportedArrayBind(nv *driver.NamedValue) bool {
 		reflect.TypeOf(&stringArray{}), reflect.TypeOf(&byteArray{}),
 		reflect.TypeOf(&timestampNtzArray{}), reflect.TypeOf(&timestampLtzArray{}),
 		reflect.TypeOf(&timestampTzArray{}), reflect.TypeOf(&dateArray{}),
-		reflect.TypeOf(&timeArray{}):
+		reflect.TypeOf(&timeArray{}), reflect.TypeOf(&arrowColumnArray{}):
 		return true
 	case reflect.TypeOf([]uint8{}):
 		// internal binding ts mode
@@ -356,6 +515,59 @@ func supportedNullBind(nv *driver.NamedValue) bool {
 	return false
 }
 
+// isTextMarshalerBind reports whether the value is bound as the text of its MarshalText method.
+// driver.Valuer implementations and time.Time are converted by database/sql as usual.
+func isTextMarshalerBind(nv *driver.NamedValue) bool {
+	if _, ok := nv.Value.(driver.Valuer); ok {
+		return false
+	}
+	if _, ok := nv.Value.(time.Time); ok {
+		return false
+	}
+	_, ok := nv.Value.(encoding.TextMarshaler)
+	return ok
+}
+
+// convertValuerBind replaces a driver.Valuer with the value it returns. It reports whether
+// the value is a map or a slice, which database/sql rejects but which are bound as structured types.
+// Nil pointers are left for database/sql to convert.
+func convertValuerBind(nv *driver.NamedValue) (bool, error) {
+	valuer, ok := nv.Value.(driver.Valuer)
+	if !ok {
+		return false, nil
+	}
+	if rv := reflect.ValueOf(valuer); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return false, nil
+	}
+	value, err := valuer.Value()
+	if err != nil {
+		return false, err
+	}
+	nv.Value = value
+	if _, ok = value.([]byte); ok {
+		return false, nil
+	}
+	return supportedStructuredMapBind(nv) || supportedStructuredArrayBind(nv), nil
+}
+
+func convertTextMarshalerBind(nv *driver.NamedValue) error {
+	if rv := reflect.ValueOf(nv.Value); rv.Kind() == reflect.Pointer && rv.IsNil() {
+		nv.Value = nil
+		return nil
+	}
+	text, err := nv.Value.(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return err
+	}
+	nv.Value = string(text)
+	return nil
+}
+
+func supportedTypedBinaryBind(nv *driver.NamedValue) bool {
+	_, ok := nv.Value.(TypedBinary)
+	return ok
+}
+
 func supportedStructuredObjectWriterBind(nv *driver.NamedValue) bool {
 	if _, ok := nv.Value.(StructuredObjectWriter); ok {
 		return true
@@ -4,11 +4,14 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"log"
 	"math"
 	"math/big"
 	"math/rand"
+	"net/url"
 	"reflect"
 	"slices"
 	"strconv"
@@ -1559,3 +1562,107 @@ func getRandomDate() time.Time {
 func getRandomBool() bool {
 	return rand.Int63n(time.Now().Unix())%2 == 0
 }
+
+func TestArrayBindSplitIntoChunks(t *testing.T) {
+	var statements []string
+	var insertedIDs []string
+	var transactionID *string
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+		_ *url.Values, _ map[string]string, body []byte, _ time.Duration,
+		_ UUID, _ *Config) (*execResponse, error) {
+		var req execRequest
+		assertNilF(t, json.Unmarshal(body, &req))
+		statements = append(statements, req.SQLText)
+		switch req.SQLText {
+		case "INSERT INTO t VALUES (?, ?)":
+		case "SELECT CURRENT_TRANSACTION()":
+			return &execResponse{
+				Data: execResponseData{
+					StatementTypeID:   statementTypeIDSelect,
+					QueryResultFormat: "json",
+					RowType:           []execResponseRowType{{Name: "CURRENT_TRANSACTION()", Type: "text", Nullable: true}},
+					RowSet:            [][]*string{{transactionID}},
+				},
+				Code:    "0",
+				Success: true,
+			}, nil
+		case "BEGIN":
+			id := "1234"
+			transactionID = &id
+			return &execResponse{Code: "0", Success: true}, nil
+		case "COMMIT", "ROLLBACK":
+			transactionID = nil
+			return &execResponse{Code: "0", Success: true}, nil
+		default:
+			return &execResponse{Code: "0", Success: true}, nil
+		}
+		ids := req.Bindings["1"].Value.([]interface{})
+		assertTrueF(t, 2*len(ids) <= 10, "too many bind values in a single request")
+		for _, id := range ids {
+			insertedIDs = append(insertedIDs, id.(string))
+		}
+		inserted := strconv.Itoa(len(ids))
+		return &execResponse{
+			Data: execResponseData{
+				StatementTypeID: statementTypeIDDml,
+				RowType:         []execResponseRowType{{Name: "number of rows inserted", Type: "fixed"}},
+				RowSet:          [][]*string{{&inserted}},
+			},
+			Code:    "0",
+			Success: true,
+		}, nil
+	}
+	sc := &snowflakeConn{
+		cfg:                 &Config{Params: map[string]*string{}, ArrayBindChunkSize: 10},
+		rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+	}
+	ids := make([]int, 23)
+	names := make([]string, 23)
+	for i := range ids {
+		ids[i] = i
+		names[i] = fmt.Sprintf("name%v", i)
+	}
+
+	res, err := sc.ExecContext(context.Background(), "INSERT INTO t VALUES (?, ?)",
+		[]driver.NamedValue{{Ordinal: 1, Value: Array(&ids)}, {Ordinal: 2, Value: Array(&names)}})
+	assertNilF(t, err)
+	affected, err := res.RowsAffected()
+	assertNilF(t, err)
+	assertEqualE(t, affected, int64(23))
+	assertEqualE(t, len(insertedIDs), 23)
+	for i, id := range insertedIDs {
+		assertEqualE(t, id, strconv.Itoa(i))
+	}
+	assertEqualE(t, len(statements), 8)
+	assertEqualE(t, statements[0], "SELECT CURRENT_TRANSACTION()")
+	assertEqualE(t, statements[1], "BEGIN")
+	assertEqualE(t, statements[len(statements)-1], "COMMIT")
+
+	t.Run("joins the transaction started with BeginTx", func(t *testing.T) {
+		tx, err := sc.BeginTx(context.Background(), driver.TxOptions{})
+		assertNilF(t, err)
+		statements = nil
+		_, err = sc.ExecContext(context.Background(), "INSERT INTO t VALUES (?, ?)",
+			[]driver.NamedValue{{Ordinal: 1, Value: Array(&ids)}, {Ordinal: 2, Value: Array(&names)}})
+		assertNilF(t, err)
+		assertEqualE(t, len(statements), 6)
+		assertFalseE(t, slices.Contains(statements, "BEGIN"))
+		assertFalseE(t, slices.Contains(statements, "COMMIT"))
+		assertNilF(t, tx.Rollback())
+	})
+
+	t.Run("joins the transaction started with a BEGIN statement", func(t *testing.T) {
+		_, err := sc.ExecContext(context.Background(), "BEGIN", nil)
+		assertNilF(t, err)
+		statements = nil
+		_, err = sc.ExecContext(context.Background(), "INSERT INTO t VALUES (?, ?)",
+			[]driver.NamedValue{{Ordinal: 1, Value: Array(&ids)}, {Ordinal: 2, Value: Array(&names)}})
+		assertNilF(t, err)
+		assertEqualE(t, len(statements), 6)
+		assertFalseE(t, slices.Contains(statements, "BEGIN"))
+		assertFalseE(t, slices.Contains(statements, "COMMIT"))
+		assertNotNilE(t, transactionID, "the transaction of the user must stay open")
+	})
+}
@@ -2,6 +2,7 @@ package gosnowflake
 
 import (
 	"bufio"
+	"bytes"
 	"compress/gzip"
 	"context"
 	"encoding/json"
@@ -14,15 +15,20 @@ import (
 	"net/url"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/apache/arrow-go/v18/arrow"
 	"github.com/apache/arrow-go/v18/arrow/ipc"
 	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/klauspost/compress/zstd"
 )
 
 var (
 	errNoConnection = errors.New("failed to retrieve connection")
+
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
 )
 
 type chunkDownloader interface {
@@ -38,6 +44,8 @@ type chunkDownloader interface {
 	setNextChunkDownloader(downloader chunkDownloader)
 	getNextChunkDownloader() chunkDownloader
 	getArrowBatches() []*ArrowBatch
+	totalRows() int64
+	chunkProgress() (downloaded int, total int)
 }
 
 type snowflakeChunkDownloader struct {
@@ -69,6 +77,7 @@ type snowflakeChunkDownloader struct {
 	FuncDownload       func(context.Context, *snowflakeChunkDownloader, int)
 	FuncDownloadHelper func(context.Context, *snowflakeChunkDownloader, int) error
 	FuncGet            func(context.Context, *snowflakeConn, string, map[string]string, time.Duration) (*http.Response, error)
+	downloadedChunks   atomic.Int64 // number of chunks decoded so far, read concurrently by chunkProgress
 }
 
 func (scd *snowflakeChunkDownloader) totalUncompressedSize() (acc int64) {
@@ -111,7 +120,7 @@ func (scd *snowflakeChunkDownloader) start() error {
 			return fmt.Errorf("getting config params: %w", err)
 		}
 		// if the rowsetbase64 retrieved from the server is empty, move on to downloading chunks
-		loc := getCurrentLocation(params)
+		loc := scd.sc.getTimestampLTZLocation()
 		firstArrowChunk, err := buildFirstArrowChunk(scd.RowSet.RowSetBase64, loc, scd.pool)
 		if err != nil {
 			return fmt.Errorf("building first arrow chunk: %w", err)
@@ -137,7 +146,7 @@ func (scd *snowflakeChunkDownloader) start() error {
 		for i := 0; i < chunkMetaLen; i++ {
 			chunk := scd.ChunkMetas[i]
 			logger.WithContext(scd.ctx).Debugf("add chunk to channel ChunksChan: %v, URL: %v, RowCount: %v, UncompressedSize: %v, ChunkResultFormat: %v",
-				i+1, chunk.URL, chunk.RowCount, chunk.UncompressedSize, scd.QueryResultFormat)
+				i+1, redactURL(chunk.URL), chunk.RowCount, chunk.UncompressedSize, scd.QueryResultFormat)
 			scd.ChunksChan <- i
 		}
 		for i := 0; i < intMin(MaxChunkDownloadWorkers, chunkMetaLen); i++ {
@@ -172,6 +181,9 @@ func (scd *snowflakeChunkDownloader) checkErrorRetry() error {
 
 			scd.ChunksFinalErrors = append(scd.ChunksFinalErrors, errc)
 			logger.WithContext(scd.ctx).Warningf("chunk idx: %v, err: %v. no further retry", errc.Index, errc.Error)
+			if scd.ChunksErrorCounter >= maxChunkDownloaderErrorCounter {
+				scd.sc.reportClientError(scd.ctx, errc.Error)
+			}
 			return errc.Error
 		}
 
@@ -270,6 +282,14 @@ func (scd *snowflakeChunkDownloader) getArrowBatches() []*ArrowBatch {
 	return append([]*ArrowBatch{scd.FirstBatch}, scd.ArrowBatches...)
 }
 
+func (scd *snowflakeChunkDownloader) totalRows() int64 {
+	return scd.Total
+}
+
+func (scd *snowflakeChunkDownloader) chunkProgress() (int, int) {
+	return int(scd.downloadedChunks.Load()), len(scd.ChunkMetas)
+}
+
 func (scd *snowflakeChunkDownloader) getConfigParams() (map[string]*string, error) {
 	if scd.sc == nil || scd.sc.cfg == nil {
 		return map[string]*string{}, errNoConnection
@@ -294,11 +314,10 @@ func getChunk(
 
 func (scd *snowflakeChunkDownloader) startArrowBatches() error {
 	var loc *time.Location
-	params, err := scd.getConfigParams()
-	if err != nil {
+	if _, err := scd.getConfigParams(); err != nil {
 		return fmt.Errorf("getting config params: %w", err)
 	}
-	loc = getCurrentLocation(params)
+	loc = scd.sc.getTimestampLTZLocation()
 	if scd.RowSet.RowSetBase64 != "" {
 		firstArrowChunk, err := buildFirstArrowChunk(scd.RowSet.RowSetBase64, loc, scd.pool)
 		if err != nil {
@@ -382,7 +401,7 @@ func downloadChunkHelper(ctx context.Context, scd *snowflakeChunkDownloader, idx
 	if len(scd.ChunkHeader) > 0 {
 		logger.WithContext(ctx).Debug("chunk header is provided.")
 		for k, v := range scd.ChunkHeader {
-			logger.WithContext(ctx).Debugf("adding header: %v, value: %v", k, v)
+			logger.WithContext(ctx).Debugf("adding header: %v, value: %v", k, redactHeaderValue(k, v))
 
 			headers[k] = v
 		}
@@ -390,20 +409,23 @@ func downloadChunkHelper(ctx context.Context, scd *snowflakeChunkDownloader, idx
 		headers[headerSseCAlgorithm] = headerSseCAes
 		headers[headerSseCKey] = scd.Qrmk
 	}
+	if _, ok := headers[headerAcceptEncoding]; !ok {
+		headers[headerAcceptEncoding] = chunkAcceptEncoding
+	}
 
 	resp, err := scd.FuncGet(ctx, scd.sc, scd.ChunkMetas[idx].URL, headers, scd.sc.rest.RequestTimeout)
 	if err != nil {
 		return fmt.Errorf("getting chunk: %w", err)
 	}
 	defer resp.Body.Close()
-	logger.WithContext(ctx).Debugf("response returned chunk: %v for URL: %v", idx+1, scd.ChunkMetas[idx].URL)
+	logger.WithContext(ctx).Debugf("response returned chunk: %v for URL: %v", idx+1, redactURL(scd.ChunkMetas[idx].URL))
 	if resp.StatusCode != http.StatusOK {
 		b, err := io.ReadAll(resp.Body)
 		if err != nil {
 			logger.WithContext(ctx).Warnf("reading response body: %v", err)
 		}
-		logger.WithContext(ctx).Infof("HTTP: %v, URL: %v, Body: %v", resp.StatusCode, scd.ChunkMetas[idx].URL, b)
-		logger.WithContext(ctx).Infof("Header: %v", resp.Header)
+		logger.WithContext(ctx).Infof("HTTP: %v, URL: %v, Body: %v", resp.StatusCode, redactURL(scd.ChunkMetas[idx].URL), b)
+		logger.WithContext(ctx).Infof("Header: %v", redactHeaders(resp.Header))
 		return &SnowflakeError{
 			Number:      ErrFailedToGetChunk,
 			SQLState:    SQLStateConnectionFailure,
@@ -416,24 +438,38 @@ func downloadChunkHelper(ctx context.Context, scd *snowflakeChunkDownloader, idx
 	return decodeChunk(ctx, scd, idx, bufStream)
 }
 
-func decodeChunk(ctx context.Context, scd *snowflakeChunkDownloader, idx int, bufStream *bufio.Reader) error {
-	gzipMagic, err := bufStream.Peek(2)
-	if err != nil {
-		return fmt.Errorf("peeking for gzip magic bytes: %w", err)
+// decompressChunkStream detects gzip or zstd compressed chunk data by its magic bytes
+// and returns a reader of the uncompressed data. Uncompressed data is returned as is.
+func decompressChunkStream(bufStream *bufio.Reader) (io.ReadCloser, error) {
+	magic, err := bufStream.Peek(len(zstdMagic))
+	if err != nil && len(magic) < len(gzipMagic) {
+		return nil, fmt.Errorf("peeking for compression magic bytes: %w", err)
 	}
-	start := time.Now()
-	var source io.Reader
-	if gzipMagic[0] == 0x1f && gzipMagic[1] == 0x8b {
-		// detects and uncompresses Gzip format data
-		bufStream0, err := gzip.NewReader(bufStream)
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		gzipReader, err := gzip.NewReader(bufStream)
 		if err != nil {
-			return fmt.Errorf("creating gzip reader: %w", err)
+			return nil, fmt.Errorf("creating gzip reader: %w", err)
 		}
-		defer bufStream0.Close()
-		source = bufStream0
-	} else {
-		source = bufStream
+		return gzipReader, nil
+	case bytes.HasPrefix(magic, zstdMagic):
+		zstdReader, err := zstd.NewReader(bufStream)
+		if err != nil {
+			return nil, fmt.Errorf("creating zstd reader: %w", err)
+		}
+		return zstdReader.IOReadCloser(), nil
+	default:
+		return io.NopCloser(bufStream), nil
 	}
+}
+
+func decodeChunk(ctx context.Context, scd *snowflakeChunkDownloader, idx int, bufStream *bufio.Reader) error {
+	source, err := decompressChunkStream(bufStream)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	start := time.Now()
 	st := &largeResultSetReader{
 		status: 0,
 		body:   source,
@@ -468,7 +504,7 @@ func decodeChunk(ctx context.Context, scd *snowflakeChunkDownloader, idx int, bu
 		if err != nil {
 			return fmt.Errorf("getting config params: %w", err)
 		}
-		loc = getCurrentLocation(params)
+		loc = scd.sc.getTimestampLTZLocation()
 		arc := arrowResultChunk{
 			ipcReader,
 			0,
@@ -483,6 +519,7 @@ func decodeChunk(ctx context.Context, scd *snowflakeChunkDownloader, idx int, bu
 			}
 			// updating metadata
 			scd.ArrowBatches[idx].rowCount = countArrowBatchRows(scd.ArrowBatches[idx].rec)
+			scd.downloadedChunks.Add(1)
 			return nil
 		}
 		highPrec := higherPrecisionEnabled(scd.ctx)
@@ -501,6 +538,7 @@ func decodeChunk(ctx context.Context, scd *snowflakeChunkDownloader, idx int, bu
 	scd.ChunksMutex.Lock()
 	defer scd.ChunksMutex.Unlock()
 	scd.Chunks[idx] = respd
+	scd.downloadedChunks.Add(1)
 	return nil
 }
 
@@ -521,6 +559,8 @@ type streamChunkDownloader struct {
 	ChunkMetas     []execResponseChunk
 	NextDownloader chunkDownloader
 	RowSet         rowSetType
+
+	downloadedChunks atomic.Int64
 }
 
 func (scd *streamChunkDownloader) totalUncompressedSize() (acc int64) {
@@ -584,6 +624,7 @@ func (scd *streamChunkDownloader) start() error {
 					break
 				}
 				logger.WithContext(scd.ctx).Infof("fetched chunk %d (%d rows) in %vms", i, chunk.RowCount, time.Since(t).Microseconds())
+				scd.downloadedChunks.Add(1)
 				t = time.Now()
 			}
 		},
@@ -624,6 +665,14 @@ func (scd *streamChunkDownloader) getArrowBatches() []*ArrowBatch {
 	return nil
 }
 
+func (scd *streamChunkDownloader) totalRows() int64 {
+	return -1 // rows are streamed as they are read, the total is not known upfront
+}
+
+func (scd *streamChunkDownloader) chunkProgress() (int, int) {
+	return int(scd.downloadedChunks.Load()), len(scd.ChunkMetas)
+}
+
 func useStreamDownloader(ctx context.Context) bool {
 	val := ctx.Value(streamChunkDownload)
 	if val == nil {
@@ -668,8 +717,9 @@ func newStreamChunkDownloader(
 func (f *httpStreamChunkFetcher) fetch(URL string, rows chan<- []*string) error {
 	if len(f.headers) == 0 {
 		f.headers = map[string]string{
-			headerSseCAlgorithm: headerSseCAes,
-			headerSseCKey:       f.qrmk,
+			headerSseCAlgorithm:  headerSseCAes,
+			headerSseCKey:        f.qrmk,
+			headerAcceptEncoding: chunkAcceptEncoding,
 		}
 	}
 
@@ -696,21 +746,11 @@ func (f *httpStreamChunkFetcher) fetch(URL string, rows chan<- []*string) error
 }
 
 func copyChunkStream(body io.Reader, rows chan<- []*string) error {
-	bufStream := bufio.NewReader(body)
-	gzipMagic, err := bufStream.Peek(2)
+	source, err := decompressChunkStream(bufio.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("peeking for gzip magic bytes: %w", err)
-	}
-	var source io.Reader = bufStream
-	if gzipMagic[0] == 0x1f && gzipMagic[1] == 0x8b {
-		// detect and decompress Gzip format data
-		bufStream0, err := gzip.NewReader(bufStream)
-		if err != nil {
-			return fmt.Errorf("creating gzip reader: %w", err)
-		}
-		defer bufStream0.Close()
-		source = bufStream0
+		return err
 	}
+	defer source.Close()
 	r := io.MultiReader(strings.NewReader("["), source, strings.NewReader("]"))
 	dec := json.NewDecoder(r)
 	openToken := json.Delim('[')
@@ -1,6 +1,7 @@
 package gosnowflake
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"database/sql/driver"
@@ -17,6 +18,7 @@ import (
 	"github.com/apache/arrow-go/v18/arrow/array"
 	"github.com/apache/arrow-go/v18/arrow/ipc"
 	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/klauspost/compress/zstd"
 )
 
 func TestBadChunkData(t *testing.T) {
@@ -295,6 +297,34 @@ func TestCopyChunkStream(t *testing.T) {
 	assertEqualRows([]*string{}, <-c)
 }
 
+func TestDecodeZstdCompressedChunk(t *testing.T) {
+	var compressed bytes.Buffer
+	zw, err := zstd.NewWriter(&compressed)
+	assertNilF(t, err)
+	_, err = zw.Write([]byte(`["1","foo"],["2",null]`))
+	assertNilF(t, err)
+	assertNilF(t, zw.Close())
+
+	scd := &snowflakeChunkDownloader{
+		ctx:               context.Background(),
+		ChunkMetas:        []execResponseChunk{{RowCount: 2}},
+		Chunks:            make(map[int][]chunkRowType),
+		ChunksMutex:       &sync.Mutex{},
+		QueryResultFormat: "json",
+	}
+	assertNilF(t, decodeChunk(context.Background(), scd, 0, bufio.NewReader(bytes.NewReader(compressed.Bytes()))))
+	foo, one, two := "foo", "1", "2"
+	assertDeepEqualE(t, scd.Chunks[0], []chunkRowType{
+		{RowSet: []*string{&one, &foo}},
+		{RowSet: []*string{&two, nil}},
+	})
+
+	c := make(chan []*string, 2)
+	assertNilF(t, copyChunkStream(bytes.NewReader(compressed.Bytes()), c))
+	assertEqualRows([]*string{&one, &foo}, <-c)
+	assertEqualRows([]*string{&two, nil}, <-c)
+}
+
 func TestCopyChunkStreamInvalid(t *testing.T) {
 	var r io.Reader
 	var c chan []*string
@@ -0,0 +1,189 @@
+package gosnowflake
+
+import (
+	"crypto/rsa"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ConfigBuilder builds a Config step by step, e.g.
+//
+//	cfg, err := NewConfigBuilder().Account("myaccount").User("me").Password("secret").Database("db").Build()
+//
+// Build reports all missing and invalid settings at once instead of failing on the first one when connecting.
+type ConfigBuilder struct {
+	cfg Config
+}
+
+// NewConfigBuilder returns a builder of a Config using the Snowflake authenticator by default.
+func NewConfigBuilder() *ConfigBuilder {
+	return &ConfigBuilder{cfg: Config{
+		Params:        make(map[string]*string),
+		Authenticator: AuthTypeSnowflake,
+	}}
+}
+
+// Account sets the account name, e.g. myorg-myaccount.
+func (b *ConfigBuilder) Account(account string) *ConfigBuilder {
+	b.cfg.Account = account
+	return b
+}
+
+// User sets the login name.
+func (b *ConfigBuilder) User(user string) *ConfigBuilder {
+	b.cfg.User = user
+	return b
+}
+
+// Password sets the password, used by the Snowflake, MFA, Okta and programmatic access token authenticators.
+func (b *ConfigBuilder) Password(password string) *ConfigBuilder {
+	b.cfg.Password = password
+	return b
+}
+
+// Authenticator sets the authentication method.
+func (b *ConfigBuilder) Authenticator(authenticator AuthType) *ConfigBuilder {
+	b.cfg.Authenticator = authenticator
+	return b
+}
+
+// Token sets the token used by the OAuth, programmatic access token and workload identity authenticators.
+func (b *ConfigBuilder) Token(token string) *ConfigBuilder {
+	b.cfg.Token = token
+	return b
+}
+
+// PrivateKey sets the private key used by the SNOWFLAKE_JWT authenticator.
+func (b *ConfigBuilder) PrivateKey(privateKey *rsa.PrivateKey) *ConfigBuilder {
+	b.cfg.PrivateKey = privateKey
+	return b
+}
+
+// OAuthClient sets the client ID and secret used by the OAuth authorization code and client credentials flows.
+func (b *ConfigBuilder) OAuthClient(clientID, clientSecret string) *ConfigBuilder {
+	b.cfg.OauthClientID = clientID
+	b.cfg.OauthClientSecret = clientSecret
+	return b
+}
+
+// Host sets the host name, by default it is derived from the account and region.
+func (b *ConfigBuilder) Host(host string) *ConfigBuilder {
+	b.cfg.Host = host
+	return b
+}
+
+// Port sets the port, 443 by default.
+func (b *ConfigBuilder) Port(port int) *ConfigBuilder {
+	b.cfg.Port = port
+	return b
+}
+
+// Region sets the region of the account.
+func (b *ConfigBuilder) Region(region string) *ConfigBuilder {
+	b.cfg.Region = region
+	return b
+}
+
+// Database sets the default database of the session.
+func (b *ConfigBuilder) Database(database string) *ConfigBuilder {
+	b.cfg.Database = database
+	return b
+}
+
+// Schema sets the default schema of the session.
+func (b *ConfigBuilder) Schema(schema string) *ConfigBuilder {
+	b.cfg.Schema = schema
+	return b
+}
+
+// Warehouse sets the default warehouse of the session.
+func (b *ConfigBuilder) Warehouse(warehouse string) *ConfigBuilder {
+	b.cfg.Warehouse = warehouse
+	return b
+}
+
+// Role sets the default role of the session.
+func (b *ConfigBuilder) Role(role string) *ConfigBuilder {
+	b.cfg.Role = role
+	return b
+}
+
+// Application sets the name of the application connecting to Snowflake.
+func (b *ConfigBuilder) Application(application string) *ConfigBuilder {
+	b.cfg.Application = application
+	return b
+}
+
+// LoginTimeout sets the timeout of the login request.
+func (b *ConfigBuilder) LoginTimeout(timeout time.Duration) *ConfigBuilder {
+	b.cfg.LoginTimeout = timeout
+	return b
+}
+
+// Param sets a session parameter, e.g. QUERY_TAG.
+func (b *ConfigBuilder) Param(name, value string) *ConfigBuilder {
+	b.cfg.Params[name] = &value
+	return b
+}
+
+// Build validates the settings and returns the Config with the defaults filled in the same way
+// as ParseDSN does. The returned error lists every missing or invalid setting, each of them is a *SnowflakeError.
+func (b *ConfigBuilder) Build() (*Config, error) {
+	cfg := b.cfg
+	cfg.Params = make(map[string]*string, len(b.cfg.Params))
+	for k, v := range b.cfg.Params {
+		cfg.Params[k] = v
+	}
+	var errs []error
+	// the account may include the region as in a DSN, e.g. myaccount.us-east-1
+	if cfg.Host == "" && strings.TrimSpace(cfg.Account) != "" {
+		if info, err := ParseAccountIdentifier(cfg.Account); err != nil {
+			errs = append(errs, err)
+		} else {
+			cfg.Account = info.Account
+			if cfg.Region == "" {
+				cfg.Region = info.Region
+				cfg.Host = info.Host
+			}
+		}
+	}
+	errs = append(errs, validateConfigParameters(&cfg)...)
+	errs = append(errs, conflictingAuthOptions(&cfg)...)
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	if err := fillMissingConfigParameters(&cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// conflictingAuthOptions returns an error for every credential that the authenticator does not use.
+func conflictingAuthOptions(cfg *Config) []error {
+	var errs []error
+	if cfg.Password != "" && !authUsesPassword(cfg) {
+		errs = append(errs, errConflictingAuthOption("password", cfg.Authenticator))
+	}
+	if cfg.Token != "" && !authUsesToken(cfg) {
+		errs = append(errs, errConflictingAuthOption("token", cfg.Authenticator))
+	}
+	if cfg.PrivateKey != nil && cfg.Authenticator != AuthTypeJwt {
+		errs = append(errs, errConflictingAuthOption("private key", cfg.Authenticator))
+	}
+	return errs
+}
+
+func authUsesPassword(cfg *Config) bool {
+	return cfg.Authenticator == AuthTypeSnowflake ||
+		cfg.Authenticator == AuthTypeUsernamePasswordMFA ||
+		cfg.Authenticator == AuthTypeOkta ||
+		cfg.Authenticator == AuthTypePat
+}
+
+func authUsesToken(cfg *Config) bool {
+	return cfg.Authenticator == AuthTypeOAuth ||
+		cfg.Authenticator == AuthTypePat ||
+		cfg.Authenticator == AuthTypeWorkloadIdentityFederation
+}
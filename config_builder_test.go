@@ -0,0 +1,87 @@
+package gosnowflake
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+)
+
+func TestConfigBuilderBuildsSameConfigAsDSN(t *testing.T) {
+	cfg, err := NewConfigBuilder().
+		Account("myorg-myaccount").
+		User("u").
+		Password("p").
+		Database("db").
+		Schema("s").
+		Warehouse("wh").
+		Role("r").
+		Param("QUERY_TAG", "tag").
+		Build()
+	assertNilF(t, err)
+
+	expected, err := ParseDSN("u:p@myorg-myaccount/db/s?warehouse=wh&role=r&QUERY_TAG=tag")
+	assertNilF(t, err)
+	assertDeepEqualE(t, cfg, expected)
+}
+
+func TestConfigBuilderReportsAllErrors(t *testing.T) {
+	_, err := NewConfigBuilder().Password("p").Build()
+	assertNotNilF(t, err)
+	var numbers []int
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var se *SnowflakeError
+		assertTrueF(t, errors.As(e, &se))
+		numbers = append(numbers, se.Number)
+	}
+	assertDeepEqualE(t, numbers, []int{ErrCodeEmptyAccountCode, ErrCodeEmptyUsernameCode})
+	assertStringContainsE(t, err.Error(), "account is empty")
+	assertStringContainsE(t, err.Error(), "user is empty")
+}
+
+func TestConfigBuilderRejectsConflictingAuthOptions(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assertNilF(t, err)
+	_, err = NewConfigBuilder().
+		Account("a").
+		User("u").
+		Password("p").
+		Token("t").
+		PrivateKey(privateKey).
+		Authenticator(AuthTypeJwt).
+		Build()
+	assertNotNilF(t, err)
+	assertStringContainsE(t, err.Error(), "password cannot be used with authenticator SNOWFLAKE_JWT")
+	assertStringContainsE(t, err.Error(), "token cannot be used with authenticator SNOWFLAKE_JWT")
+
+	cfg, err := NewConfigBuilder().Account("a").User("u").PrivateKey(privateKey).Authenticator(AuthTypeJwt).Build()
+	assertNilF(t, err)
+	assertEqualE(t, cfg.Host, "a.snowflakecomputing.com")
+}
+
+func TestConfigBuilderKeepsRegionOfAccount(t *testing.T) {
+	cfg, err := NewConfigBuilder().Account("acc.us-east-1").User("u").Password("p").Database("db").Build()
+	assertNilF(t, err)
+	expected, err := ParseDSN("u:p@acc.us-east-1/db")
+	assertNilF(t, err)
+	assertDeepEqualE(t, cfg, expected)
+	assertEqualE(t, cfg.Host, "acc.us-east-1.snowflakecomputing.com")
+	assertEqualE(t, cfg.Account, "acc")
+	assertEqualE(t, cfg.Region, "us-east-1")
+}
+
+func TestConfigBuilderReportsInvalidSettings(t *testing.T) {
+	_, err := NewConfigBuilder().Account("a").User("u").Password("p").Build()
+	assertNilF(t, err)
+	b := NewConfigBuilder().Account("a;b").User("u").Password("p")
+	b.cfg.UploadCompressionLevel = 42
+	b.cfg.DialNetwork = "udp"
+	_, err = b.Build()
+	var numbers []int
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var se *SnowflakeError
+		assertTrueF(t, errors.As(e, &se))
+		numbers = append(numbers, se.Number)
+	}
+	assertDeepEqualE(t, numbers, []int{ErrCodeInvalidAccountIdentifier, ErrCodeInvalidCompressionLevel, ErrCodeInvalidDialNetwork})
+}
@@ -3,17 +3,23 @@ package gosnowflake
 import (
 	"bufio"
 	"bytes"
+	"cmp"
 	"compress/gzip"
 	"context"
+	"crypto/tls"
 	"database/sql"
 	"database/sql/driver"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -69,14 +75,18 @@ const (
 // External cancellation should not be supported because the connection
 // may be reused after the original query/request has completed.
 type snowflakeConn struct {
-	ctx                 context.Context
-	cfg                 *Config
-	rest                *snowflakeRestful
-	SequenceCounter     uint64
-	telemetry           *snowflakeTelemetry
-	internal            InternalClient
-	queryContextCache   *queryContextCache
-	currentTimeProvider currentTimeProvider
+	ctx                  context.Context
+	cfg                  *Config
+	rest                 *snowflakeRestful
+	SequenceCounter      uint64
+	telemetry            *snowflakeTelemetry
+	internal             InternalClient
+	queryContextCache    *queryContextCache
+	currentTimeProvider  currentTimeProvider
+	preparedStatements   *preparedStatementCache
+	statementLimiter     *statementLimiter
+	readOnlyTransaction  bool           // the current transaction was started with sql.TxOptions.ReadOnly
+	timestampLTZLocation *time.Location // Config.TimestampLTZTimezone resolved once, nil if not set
 }
 
 var (
@@ -85,6 +95,34 @@ var (
 )
 
 func (sc *snowflakeConn) exec(
+	ctx context.Context,
+	query string,
+	noResult bool,
+	isInternal bool,
+	describeOnly bool,
+	bindings []driver.NamedValue) (
+	*execResponse, error) {
+	numbers := retryOnErrors(ctx)
+	if len(numbers) == 0 || describeOnly {
+		return sc.execOnce(ctx, query, noResult, isInternal, describeOnly, bindings)
+	}
+	for attempt := 1; ; attempt++ {
+		data, err := sc.execOnce(ctx, query, noResult, isInternal, describeOnly, bindings)
+		if err == nil || attempt > sc.cfg.MaxRetryCount || !slices.ContainsFunc(numbers, func(number int) bool {
+			return hasErrorNumber(err, number)
+		}) {
+			return data, err
+		}
+		sleepTime := waitAlgoFor(sc.cfg).calculateWaitBeforeRetry(attempt)
+		logger.WithContext(ctx).Warnf("statement failed with a retryable error: %v. retrying in %v (%v/%v)",
+			err, sleepTime, attempt, sc.cfg.MaxRetryCount)
+		if err = sleepWithContext(ctx, sleepTime); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (sc *snowflakeConn) execOnce(
 	ctx context.Context,
 	query string,
 	noResult bool,
@@ -93,12 +131,22 @@ func (sc *snowflakeConn) exec(
 	bindings []driver.NamedValue) (
 	*execResponse, error) {
 	var err error
+	if sc.readOnlyTransaction && !isInternal && !describeOnly {
+		if err = checkReadOnlyStatement(query); err != nil {
+			return nil, err
+		}
+	}
+	if !describeOnly {
+		if err = sc.statementLimiter.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if bindings, err = expandArrowRecordBindings(query, bindings); err != nil {
+		sc.reportClientError(ctx, err)
+		return nil, err
+	}
 	counter := atomic.AddUint64(&sc.SequenceCounter, 1) // query sequence counter
 
-	queryContext, err := buildQueryContext(sc.queryContextCache)
-	if err != nil {
-		logger.WithContext(ctx).Errorf("error while building query context: %v", err)
-	}
 	req := execRequest{
 		SQLText:      query,
 		AsyncExec:    noResult,
@@ -106,20 +154,69 @@ func (sc *snowflakeConn) exec(
 		IsInternal:   isInternal,
 		DescribeOnly: describeOnly,
 		SequenceID:   counter,
-		QueryContext: queryContext,
+	}
+	if !sc.cfg.DisableQueryContextCache {
+		queryContext, err := buildQueryContext(sc.queryContextCache)
+		if err != nil {
+			logger.WithContext(ctx).Errorf("error while building query context: %v", err)
+		}
+		req.QueryContext = &queryContext
 	}
 	if key := ctx.Value(multiStatementCount); key != nil {
 		req.Parameters[string(multiStatementCount)] = key
 	}
 	if tag := ctx.Value(queryTag); tag != nil {
 		req.Parameters[string(queryTag)] = tag
+		// the tag returned by the server for this query must not leak to the connection state
+		defer sc.restoreSessionParameter(strings.ToLower(string(queryTag)))()
+	}
+	if format, ok := ctx.Value(queryResultFormat).(resultFormat); ok {
+		req.Parameters[string(queryResultFormat)] = strings.ToUpper(string(format))
+		defer sc.restoreSessionParameter(strings.ToLower(string(queryResultFormat)))()
+	}
+	for _, key := range []contextKey{geographyOutputFormat, geometryOutputFormat} {
+		if format, ok := ctx.Value(key).(string); ok {
+			req.Parameters[string(key)] = format
+			defer sc.restoreSessionParameter(strings.ToLower(string(key)))()
+		}
+	}
+	if format, ok := ctx.Value(binaryOutputFormat).(string); ok {
+		req.Parameters[string(binaryOutputFormat)] = strings.ToUpper(format)
+		defer sc.restoreSessionParameter(strings.ToLower(string(binaryOutputFormat)))()
+	}
+	if usesGeospatialValues(ctx) {
+		for _, key := range []contextKey{geographyOutputFormat, geometryOutputFormat} {
+			if _, ok := req.Parameters[string(key)]; !ok {
+				defer sc.restoreSessionParameter(strings.ToLower(string(key)))()
+			}
+			req.Parameters[string(key)] = "EWKB"
+		}
+	}
+	if useCache, ok := ctx.Value(useCachedResult).(bool); ok {
+		req.Parameters[string(useCachedResult)] = useCache
+		defer sc.restoreSessionParameter(strings.ToLower(string(useCachedResult)))()
+	}
+	if limit := rowLimit(ctx); limit > 0 {
+		// one row more than the limit tells whether the result was truncated
+		req.Parameters[string(rowsPerResultSet)] = limit + 1
+		defer sc.restoreSessionParameter(strings.ToLower(string(rowsPerResultSet)))()
+	}
+	if timeout := statementTimeoutSeconds(ctx); timeout > 0 {
+		req.Parameters[string(statementTimeoutInSeconds)] = timeout
+		defer sc.restoreSessionParameter(strings.ToLower(string(statementTimeoutInSeconds)))()
+	}
+	overrides := sessionContextOverrides(ctx)
+	for key, value := range overrides {
+		req.Parameters[string(key)] = value
 	}
 	logger.WithContext(ctx).Infof("parameters: %v", req.Parameters)
 
 	// handle bindings, if required
 	requestID := getOrGenerateRequestIDFromContext(ctx)
+	recordRequestID(ctx, requestID)
 	if len(bindings) > 0 {
 		if err = sc.processBindings(ctx, bindings, describeOnly, requestID, &req); err != nil {
+			sc.reportClientError(ctx, err)
 			return nil, err
 		}
 	}
@@ -146,8 +243,12 @@ func (sc *snowflakeConn) exec(
 		return nil, err
 	}
 
+	queryStart := time.Now()
+	logQueryStarted(ctx, sc.cfg, requestID)
+	defer trackActiveRequest(ctx, requestID, sc.rest)()
 	data, err := sc.rest.FuncPostQuery(ctx, sc.rest, &url.Values{}, headers,
 		jsonBody, sc.rest.RequestTimeout, requestID, sc.cfg)
+	logQueryFinished(ctx, sc.cfg, requestID, queryStart, data, err)
 	if err != nil {
 		return data, err
 	}
@@ -160,7 +261,7 @@ func (sc *snowflakeConn) exec(
 	}
 	logger.WithContext(ctx).Infof("Success: %v, Code: %v", data.Success, code)
 	if !data.Success {
-		err = (populateErrorFields(code, data)).exceptionTelemetry(sc)
+		err = (populateErrorFields(code, data)).statementExceptionTelemetry(ctx, sc)
 		return nil, err
 	}
 
@@ -193,20 +294,62 @@ func (sc *snowflakeConn) exec(
 	}
 
 	logger.WithContext(ctx).Infof("Exec/Query SUCCESS with total=%v, returned=%v", data.Data.Total, data.Data.Returned)
-	if data.Data.FinalDatabaseName != "" {
-		sc.cfg.Database = data.Data.FinalDatabaseName
+	sc.updateSessionContext(data.Data, overrides)
+	sc.populateSessionParameters(data.Data.Parameters)
+	return data, err
+}
+
+// sessionContextOverrides returns the warehouse, role, database and schema
+// set in the context for a single query.
+func sessionContextOverrides(ctx context.Context) map[contextKey]string {
+	overrides := make(map[contextKey]string)
+	for _, key := range []contextKey{queryWarehouse, queryRole, queryDatabase, querySchema} {
+		if value, ok := ctx.Value(key).(string); ok && value != "" {
+			overrides[key] = value
+		}
 	}
-	if data.Data.FinalSchemaName != "" {
-		sc.cfg.Schema = data.Data.FinalSchemaName
+	return overrides
+}
+
+// updateSessionContext stores the database, schema, warehouse and role the
+// session ended up with. Names overridden for the query are request-scoped and
+// don't change the connection.
+func (sc *snowflakeConn) updateSessionContext(data execResponseData, overrides map[contextKey]string) {
+	if _, ok := overrides[queryDatabase]; ok {
+		data.FinalDatabaseName = ""
 	}
-	if data.Data.FinalWarehouseName != "" {
-		sc.cfg.Warehouse = data.Data.FinalWarehouseName
+	if _, ok := overrides[querySchema]; ok {
+		data.FinalSchemaName = ""
 	}
-	if data.Data.FinalRoleName != "" {
-		sc.cfg.Role = data.Data.FinalRoleName
+	if _, ok := overrides[queryWarehouse]; ok {
+		data.FinalWarehouseName = ""
 	}
-	sc.populateSessionParameters(data.Data.Parameters)
-	return data, err
+	if _, ok := overrides[queryRole]; ok {
+		data.FinalRoleName = ""
+	}
+	if sc.sessionContextChanged(&data) {
+		sc.preparedStatements.reset()
+	}
+	if data.FinalDatabaseName != "" {
+		sc.cfg.Database = data.FinalDatabaseName
+	}
+	if data.FinalSchemaName != "" {
+		sc.cfg.Schema = data.FinalSchemaName
+	}
+	if data.FinalWarehouseName != "" {
+		sc.cfg.Warehouse = data.FinalWarehouseName
+	}
+	if data.FinalRoleName != "" {
+		sc.cfg.Role = data.FinalRoleName
+	}
+}
+
+// sessionContextChanged reports whether the response moves the session to
+// another database, schema or role, which may change how statements resolve.
+func (sc *snowflakeConn) sessionContextChanged(data *execResponseData) bool {
+	return (data.FinalDatabaseName != "" && data.FinalDatabaseName != sc.cfg.Database) ||
+		(data.FinalSchemaName != "" && data.FinalSchemaName != sc.cfg.Schema) ||
+		(data.FinalRoleName != "" && data.FinalRoleName != sc.cfg.Role)
 }
 
 func extractQueryContext(data *execResponse) (queryContext, error) {
@@ -217,7 +360,13 @@ func extractQueryContext(data *execResponse) (queryContext, error) {
 
 func buildQueryContext(qcc *queryContextCache) (requestQueryContext, error) {
 	rqc := requestQueryContext{}
-	if qcc == nil || len(qcc.entries) == 0 {
+	if qcc == nil {
+		logger.Debugf("empty qcc")
+		return rqc, nil
+	}
+	qcc.mutex.Lock()
+	defer qcc.mutex.Unlock()
+	if len(qcc.entries) == 0 {
 		logger.Debugf("empty qcc")
 		return rqc, nil
 	}
@@ -245,18 +394,13 @@ func (sc *snowflakeConn) BeginTx(
 	opts driver.TxOptions) (
 	driver.Tx, error) {
 	logger.WithContext(ctx).Info("BeginTx")
-	if opts.ReadOnly {
-		return nil, (&SnowflakeError{
-			Number:   ErrNoReadOnlyTransaction,
-			SQLState: SQLStateFeatureNotSupported,
-			Message:  errMsgNoReadOnlyTransaction,
-		}).exceptionTelemetry(sc)
-	}
-	if int(opts.Isolation) != int(sql.LevelDefault) {
+	// Snowflake transactions always run with READ COMMITTED isolation
+	if isolation := sql.IsolationLevel(opts.Isolation); isolation != sql.LevelDefault && isolation != sql.LevelReadCommitted {
 		return nil, (&SnowflakeError{
-			Number:   ErrNoDefaultTransactionIsolationLevel,
-			SQLState: SQLStateFeatureNotSupported,
-			Message:  errMsgNoDefaultTransactionIsolationLevel,
+			Number:      ErrNoDefaultTransactionIsolationLevel,
+			SQLState:    SQLStateFeatureNotSupported,
+			Message:     errMsgNoDefaultTransactionIsolationLevel,
+			MessageArgs: []interface{}{isolation},
 		}).exceptionTelemetry(sc)
 	}
 	if sc.rest == nil {
@@ -268,6 +412,7 @@ func (sc *snowflakeConn) BeginTx(
 		isInternal, isDesc, nil); err != nil {
 		return nil, err
 	}
+	sc.readOnlyTransaction = opts.ReadOnly && !isDesc
 	return &snowflakeTx{sc, ctx}, nil
 }
 
@@ -297,6 +442,44 @@ func (sc *snowflakeConn) Close() (err error) {
 	return nil
 }
 
+// IsValid implements driver.Validator. A connection whose session expired
+// and could not be renewed is reported as invalid so database/sql discards it.
+func (sc *snowflakeConn) IsValid() bool {
+	return sc.rest != nil && !sc.rest.sessionExpired.Load()
+}
+
+// ResetSession implements driver.SessionResetter. It is called by database/sql
+// before a pooled connection is reused. Per-query context overrides are sent with
+// each request only, so only the query tag changed with ALTER SESSION is restored
+// to the one configured for the connection, without a request if it did not change.
+// The prepared statements described by the previous user of the connection are dropped.
+func (sc *snowflakeConn) ResetSession(ctx context.Context) error {
+	if !sc.IsValid() {
+		return driver.ErrBadConn
+	}
+	sc.preparedStatements.reset()
+	name := strings.ToLower(string(queryTag))
+	paramsMutex.Lock()
+	current, ok := sc.cfg.Params[name]
+	paramsMutex.Unlock()
+	if !ok || *current == sc.cfg.QueryTag {
+		return nil
+	}
+	logger.WithContext(ctx).Infoln("ResetSession")
+	query := "ALTER SESSION UNSET QUERY_TAG"
+	if sc.cfg.QueryTag != "" {
+		query = "ALTER SESSION SET QUERY_TAG = " + quoteStringLiteral(sc.cfg.QueryTag)
+	}
+	if _, err := sc.exec(ctx, query, false /* noResult */, true /* isInternal */, false /* describeOnly */, nil); err != nil {
+		return err
+	}
+	paramsMutex.Lock()
+	defer paramsMutex.Unlock()
+	tag := sc.cfg.QueryTag
+	sc.cfg.Params[name] = &tag
+	return nil
+}
+
 func (sc *snowflakeConn) PrepareContext(
 	ctx context.Context,
 	query string) (
@@ -309,9 +492,36 @@ func (sc *snowflakeConn) PrepareContext(
 		sc:    sc,
 		query: query,
 	}
+	if sc.preparedStatements != nil {
+		metadata, err := sc.describeStatement(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		stmt.metadata = metadata
+	}
 	return stmt, nil
 }
 
+// describeStatement returns the describe result of the query, asking the
+// server only if it is not in the prepared statement cache yet.
+func (sc *snowflakeConn) describeStatement(ctx context.Context, query string) (*preparedStatementMetadata, error) {
+	// statements described with a request-scoped session context may resolve differently
+	cacheable := len(sessionContextOverrides(ctx)) == 0
+	if metadata, ok := sc.preparedStatements.get(query); ok && cacheable {
+		logger.WithContext(ctx).Debugf("prepared statement cache hit for %v", query)
+		return metadata, nil
+	}
+	data, err := sc.exec(ctx, query, false /* noResult */, false /* isInternal */, true /* describeOnly */, nil)
+	if err != nil {
+		return nil, err
+	}
+	metadata := &preparedStatementMetadata{numberOfBinds: data.Data.NumberOfBinds}
+	if cacheable {
+		sc.preparedStatements.put(query, metadata)
+	}
+	return metadata, nil
+}
+
 func (sc *snowflakeConn) ExecContext(
 	ctx context.Context,
 	query string,
@@ -325,6 +535,15 @@ func (sc *snowflakeConn) ExecContext(
 	isDesc := isDescribeOnly(ctx)
 	isInternal := isInternal(ctx)
 	ctx = setResultType(ctx, execResultType)
+	if !noResult && !isDesc {
+		chunks, err := sc.splitArrayBindings(args)
+		if err != nil {
+			return nil, err
+		}
+		if len(chunks) > 1 {
+			return sc.execArrayBindChunks(ctx, query, isInternal, chunks)
+		}
+	}
 	data, err := sc.exec(ctx, query, noResult, isInternal, isDesc, args)
 	if err != nil {
 		logger.WithContext(ctx).Infof("error: %v", err)
@@ -338,7 +557,7 @@ func (sc *snowflakeConn) ExecContext(
 				SQLState: data.Data.SQLState,
 				Message:  err.Error(),
 				QueryID:  data.Data.QueryID,
-			}).exceptionTelemetry(sc)
+			}).statementExceptionTelemetry(ctx, sc)
 		}
 		return nil, err
 	}
@@ -362,6 +581,8 @@ func (sc *snowflakeConn) ExecContext(
 		}, nil // last insert id is not supported by Snowflake
 	} else if isMultiStmt(&data.Data) {
 		return sc.handleMultiExec(ctx, data.Data)
+	} else if returnsGeneratedKey(ctx) {
+		return sc.generatedKeyResult(ctx, data.Data)
 	} else if isDql(&data.Data) {
 		logger.WithContext(ctx).Debugf("DQL")
 		if isStatementContext(ctx) {
@@ -426,7 +647,7 @@ func (sc *snowflakeConn) queryContextInternal(
 				SQLState: data.Data.SQLState,
 				Message:  err.Error(),
 				QueryID:  data.Data.QueryID,
-			}).exceptionTelemetry(sc)
+			}).statementExceptionTelemetry(ctx, sc)
 		}
 		return nil, err
 	}
@@ -440,7 +661,6 @@ func (sc *snowflakeConn) queryContextInternal(
 	rows.sc = sc
 	rows.queryID = data.Data.QueryID
 	rows.ctx = ctx
-	rows.format = resultFormat(data.Data.QueryResultFormat)
 
 	if isMultiStmt(&data.Data) {
 		// handleMultiQuery is responsible to fill rows with childResults
@@ -449,6 +669,7 @@ func (sc *snowflakeConn) queryContextInternal(
 		}
 	} else {
 		rows.addDownloader(populateChunkDownloader(ctx, sc, data.Data))
+		rows.fileTransferResults = data.Data.FileTransferResults
 	}
 
 	err = rows.ChunkDownloader.start()
@@ -482,7 +703,7 @@ func (sc *snowflakeConn) Ping(ctx context.Context) error {
 	isDesc := isDescribeOnly(ctx)
 	isInternal := isInternal(ctx)
 	ctx = setResultType(ctx, execResultType)
-	_, err := sc.exec(ctx, "SELECT 1", noResult, isInternal,
+	_, err := sc.exec(ctx, cmp.Or(sc.cfg.ValidationQuery, defaultValidationQuery), noResult, isInternal,
 		isDesc, []driver.NamedValue{})
 	return err
 }
@@ -490,9 +711,15 @@ func (sc *snowflakeConn) Ping(ctx context.Context) error {
 // CheckNamedValue determines which types are handled by this driver aside from
 // the instances captured by driver.Value
 func (sc *snowflakeConn) CheckNamedValue(nv *driver.NamedValue) error {
-	if supportedNullBind(nv) || supportedArrayBind(nv) || supportedStructuredObjectWriterBind(nv) || supportedStructuredArrayBind(nv) || supportedStructuredMapBind(nv) {
+	if supportedNullBind(nv) || supportedTypedBinaryBind(nv) || supportedArrayBind(nv) || supportedArrowRecordBind(nv) || supportedStructuredObjectWriterBind(nv) || supportedStructuredArrayBind(nv) || supportedStructuredMapBind(nv) {
 		return nil
 	}
+	if isTextMarshalerBind(nv) {
+		return convertTextMarshalerBind(nv)
+	}
+	if structured, err := convertValuerBind(nv); err != nil || structured {
+		return err
+	}
 	return driver.ErrSkip
 }
 
@@ -515,6 +742,34 @@ func (sc *snowflakeConn) GetQueryStatus(
 	}, nil
 }
 
+// GetResultStatus returns whether the query is still running, succeeded or failed.
+// The query may have been submitted by another session or process of the same account.
+// An error is returned only if the status cannot be fetched. A status the driver does
+// not know is reported as QueryExecutionUnknown.
+func (sc *snowflakeConn) GetResultStatus(ctx context.Context, queryID string) (QueryExecutionStatus, error) {
+	queryRet, err := sc.fetchQueryStatus(ctx, queryID)
+	if err != nil {
+		return QueryExecutionUnknown, err
+	}
+	if queryRet.ErrorCode != "" {
+		return QueryExecutionFailed, nil
+	}
+	qStatus, ok := strQueryStatusMap[queryRet.Status]
+	switch {
+	case !ok:
+		logger.WithContext(ctx).Warnf("unknown status %v of query %v", queryRet.Status, queryID)
+		return QueryExecutionUnknown, nil
+	case qStatus == SFQuerySuccess:
+		return QueryExecutionSucceeded, nil
+	case qStatus.isError():
+		return QueryExecutionFailed, nil
+	case qStatus.isRunning() || qStatus == SFQueryRestarted:
+		return QueryExecutionRunning, nil
+	default:
+		return QueryExecutionUnknown, nil
+	}
+}
+
 // QueryArrowStream returns batches which can be queried for their raw arrow
 // ipc stream of bytes. This way consumers don't need to be using the exact
 // same version of Arrow as the connection is using internally in order
@@ -609,7 +864,7 @@ func (asb *ArrowStreamBatch) downloadChunkStreamHelper(ctx context.Context) erro
 	if len(asb.scd.ChunkHeader) > 0 {
 		logger.WithContext(ctx).Debug("chunk header is provided")
 		for k, v := range asb.scd.ChunkHeader {
-			logger.Debugf("adding header: %v, value: %v", k, v)
+			logger.Debugf("adding header: %v, value: %v", k, redactHeaderValue(k, v))
 
 			headers[k] = v
 		}
@@ -622,7 +877,7 @@ func (asb *ArrowStreamBatch) downloadChunkStreamHelper(ctx context.Context) erro
 	if err != nil {
 		return err
 	}
-	logger.WithContext(ctx).Debugf("response returned chunk: %v for URL: %v", asb.idx+1, asb.scd.ChunkMetas[asb.idx].URL)
+	logger.WithContext(ctx).Debugf("response returned chunk: %v for URL: %v", asb.idx+1, redactURL(asb.scd.ChunkMetas[asb.idx].URL))
 	if resp.StatusCode != http.StatusOK {
 		defer resp.Body.Close()
 		b, err := io.ReadAll(resp.Body)
@@ -630,8 +885,8 @@ func (asb *ArrowStreamBatch) downloadChunkStreamHelper(ctx context.Context) erro
 			return err
 		}
 
-		logger.WithContext(ctx).Infof("HTTP: %v, URL: %v, Body: %v", resp.StatusCode, asb.scd.ChunkMetas[asb.idx].URL, b)
-		logger.WithContext(ctx).Infof("Header: %v", resp.Header)
+		logger.WithContext(ctx).Infof("HTTP: %v, URL: %v, Body: %v", resp.StatusCode, redactURL(asb.scd.ChunkMetas[asb.idx].URL), b)
+		logger.WithContext(ctx).Infof("Header: %v", redactHeaders(resp.Header))
 		return &SnowflakeError{
 			Number:      ErrFailedToGetChunk,
 			SQLState:    SQLStateConnectionFailure,
@@ -709,7 +964,7 @@ type snowflakeArrowStreamChunkDownloader struct {
 
 func (scd *snowflakeArrowStreamChunkDownloader) Location() *time.Location {
 	if scd.sc != nil && scd.sc.cfg != nil {
-		return getCurrentLocation(scd.sc.cfg.Params)
+		return scd.sc.getTimestampLTZLocation()
 	}
 	return nil
 }
@@ -802,22 +1057,42 @@ func buildSnowflakeConn(ctx context.Context, config Config) (*snowflakeConn, err
 		cfg:                 &config,
 		queryContextCache:   (&queryContextCache{}).init(),
 		currentTimeProvider: defaultTimeProvider,
+		preparedStatements:  newPreparedStatementCache(config.PreparedStatementCacheSize),
+		statementLimiter:    newStatementLimiter(config.MaxStatementsPerSecond),
 	}
 	err := initEasyLogging(config.ClientConfigFile)
 	if err != nil {
 		return nil, err
 	}
+	if config.TimestampLTZTimezone != "" {
+		if sc.timestampLTZLocation, err = time.LoadLocation(config.TimestampLTZTimezone); err != nil {
+			return nil, err
+		}
+	}
 	var st http.RoundTripper = SnowflakeTransport
 	if sc.cfg.Transporter == nil {
+		if sc.cfg.Proxy != "" {
+			// the requests must not bypass an invalid proxy
+			if err = validateProxy(sc.cfg.Proxy); err != nil {
+				return nil, err
+			}
+		}
 		if sc.cfg.DisableOCSPChecks || sc.cfg.InsecureMode {
 			// no revocation check with OCSP. Think twice when you want to enable this option.
 			st = snowflakeNoOcspTransport
+		} else if sc.cfg.CertRevocationCheckMode != CertRevocationCheckDisabled {
+			// revocation check with CRLs instead of OCSP
+			if st, err = withCrlSettings(snowflakeNoOcspTransport, sc.cfg); err != nil {
+				return nil, err
+			}
 		} else {
 			// set OCSP fail open mode
 			ocspResponseCacheLock.Lock()
 			atomic.StoreUint32((*uint32)(&ocspFailOpen), uint32(sc.cfg.OCSPFailOpen))
 			ocspResponseCacheLock.Unlock()
+			st = withOCSPSettings(st, sc.cfg)
 		}
+		st = withProxy(withDialSettings(withTLSSettings(st, sc.cfg), sc.cfg), sc.cfg)
 	} else {
 		// use the custom transport
 		st = sc.cfg.Transporter
@@ -890,8 +1165,156 @@ func getTransport(cfg *Config) http.RoundTripper {
 	}
 	if cfg.DisableOCSPChecks || cfg.InsecureMode {
 		logger.Debug("getTransport: skipping OCSP validation for cloud storage")
-		return snowflakeNoOcspTransport
+		return withProxy(withDialSettings(withTLSSettings(snowflakeNoOcspTransport, cfg), cfg), cfg)
+	}
+	if cfg.CertRevocationCheckMode != CertRevocationCheckDisabled {
+		logger.Debug("getTransport: will perform CRL validation for cloud storage")
+		st, err := withCrlSettings(snowflakeNoOcspTransport, cfg)
+		if err != nil {
+			// the connection could not have been opened with the same config
+			logger.Errorf("getTransport: failed to set up CRL validation: %v", err)
+			return &failingTransport{err: err}
+		}
+		return withProxy(withDialSettings(withTLSSettings(st, cfg), cfg), cfg)
 	}
 	logger.Debug("getTransport: will perform OCSP validation for cloud storage")
-	return SnowflakeTransport
+	return withProxy(withDialSettings(withTLSSettings(withOCSPSettings(SnowflakeTransport, cfg), cfg), cfg), cfg)
+}
+
+// failingTransport fails every request with the error which prevented setting up the transport.
+type failingTransport struct {
+	err error
+}
+
+func (ft *failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, ft.err
+}
+
+// dialTransports caches the transports with a custom dialer per dial timeout and network.
+var dialTransports = newTransportCache(maxCachedTransports)
+
+// withDialSettings returns a copy of the transport which opens TCP connections over the dial network of
+// the config and gives up after its dial timeout. The TLS handshake and the revocation checks done during
+// it are not affected.
+func withDialSettings(rt http.RoundTripper, cfg *Config) http.RoundTripper {
+	if cfg.DialTimeout <= 0 && cfg.DialNetwork == "" {
+		return rt
+	}
+	transport, ok := rt.(*http.Transport)
+	if !ok {
+		return rt
+	}
+	settings := fmt.Sprintf("%v/%v", cfg.DialTimeout, cfg.DialNetwork)
+	return dialTransports.getOrCreate(transport, settings, nil, func() *http.Transport {
+		dialer := newDialer(cfg)
+		custom := transport.Clone()
+		custom.DialContext = dialer.DialContext
+		if network := cfg.DialNetwork; network != "" {
+			custom.DialContext = func(ctx context.Context, _, address string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, address)
+			}
+		}
+		return custom
+	})
+}
+
+// newDialer returns the dialer opening the TCP connections of the config, which gives up after
+// its dial timeout, 30 seconds by default.
+func newDialer(cfg *Config) *net.Dialer {
+	dialer := &net.Dialer{
+		Timeout:   cfg.DialTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+	if dialer.Timeout <= 0 {
+		dialer.Timeout = 30 * time.Second
+	}
+	return dialer
+}
+
+// ocspTransports caches the transports with disabled OCSP response caches or a proxy.
+var ocspTransports = newTransportCache(maxCachedTransports)
+
+// withOCSPSettings returns a copy of the transport whose certificate revocation check
+// skips the OCSP response caches disabled in the config and fetches the OCSP responses
+// through the proxy of the config.
+func withOCSPSettings(rt http.RoundTripper, cfg *Config) http.RoundTripper {
+	options := ocspCacheOptions{
+		inMemoryCacheDisabled: cfg.OcspInMemoryCacheDisabled,
+		onDiskCacheDisabled:   cfg.OcspOnDiskCacheDisabled,
+	}
+	if options == (ocspCacheOptions{}) && cfg.Proxy == "" {
+		return rt
+	}
+	transport, ok := rt.(*http.Transport)
+	if !ok {
+		return rt
+	}
+	settings := fmt.Sprintf("%v/%v/%v", options.inMemoryCacheDisabled, options.onDiskCacheDisabled, cfg.Proxy)
+	return ocspTransports.getOrCreate(transport, settings, nil, func() *http.Transport {
+		var ocspTransport http.RoundTripper
+		if cfg.Proxy != "" {
+			ocspTransport = withProxy(snowflakeNoOcspTransport, cfg)
+		}
+		custom := transport.Clone()
+		if custom.TLSClientConfig == nil {
+			custom.TLSClientConfig = &tls.Config{}
+		}
+		custom.TLSClientConfig.VerifyPeerCertificate = verifyPeerCertificateWithOptions(options, ocspTransport)
+		return custom
+	})
+}
+
+// proxyTransports caches the transports with a proxy per proxy URL.
+var proxyTransports = newTransportCache(maxCachedTransports)
+
+// withProxy returns a copy of the transport which sends the requests through the proxy
+// of the config instead of the proxy set in the environment. The requests fail if the
+// proxy is invalid rather than being sent without it.
+func withProxy(rt http.RoundTripper, cfg *Config) http.RoundTripper {
+	if cfg.Proxy == "" {
+		return rt
+	}
+	transport, ok := rt.(*http.Transport)
+	if !ok {
+		return rt
+	}
+	if err := validateProxy(cfg.Proxy); err != nil {
+		// the connection could not have been opened with the same config
+		logger.Errorf("the proxy of the config is invalid: %v", err)
+		return &failingTransport{err: err}
+	}
+	return proxyTransports.getOrCreate(transport, cfg.Proxy, nil, func() *http.Transport {
+		proxyURL, _ := url.Parse(cfg.Proxy) // checked by validateProxy
+		custom := transport.Clone()
+		custom.Proxy = http.ProxyURL(proxyURL)
+		return custom
+	})
+}
+
+// tlsTransports caches the transports with a custom TLS minimum version, cipher suites or root CAs.
+var tlsTransports = newTransportCache(maxCachedTransports)
+
+// withTLSSettings returns a copy of the transport which applies the TLS minimum version,
+// cipher suites and root CAs of the config. The certificate revocation check of the transport is kept.
+func withTLSSettings(rt http.RoundTripper, cfg *Config) http.RoundTripper {
+	if cfg.TLSMinVersion == 0 && len(cfg.TLSCipherSuites) == 0 && cfg.RootCAs == nil {
+		return rt
+	}
+	transport, ok := rt.(*http.Transport)
+	if !ok {
+		return rt
+	}
+	settings := fmt.Sprintf("%v/%v", cfg.TLSMinVersion, cfg.TLSCipherSuites)
+	return tlsTransports.getOrCreate(transport, settings, cfg.RootCAs, func() *http.Transport {
+		custom := transport.Clone()
+		if custom.TLSClientConfig == nil {
+			custom.TLSClientConfig = &tls.Config{}
+		}
+		custom.TLSClientConfig.MinVersion = cfg.TLSMinVersion
+		custom.TLSClientConfig.CipherSuites = cfg.TLSCipherSuites
+		if cfg.RootCAs != nil {
+			custom.TLSClientConfig.RootCAs = cfg.RootCAs
+		}
+		return custom
+	})
 }
@@ -70,6 +70,14 @@ func loadConnectionConfig() (*Config, error) {
 }
 
 func parseToml(cfg *Config, connectionMap map[string]interface{}) error {
+	// the passphrase has to be known before the private key is parsed
+	for key, value := range connectionMap {
+		if strings.ReplaceAll(strings.ToLower(key), "_", "") == "privatekeypwd" {
+			if err := handleSingleParam(cfg, key, value); err != nil {
+				return err
+			}
+		}
+	}
 	for key, value := range connectionMap {
 		if err := handleSingleParam(cfg, key, value); err != nil {
 			return err
@@ -130,10 +138,22 @@ func handleSingleParam(cfg *Config, key string, value interface{}) error {
 		cfg.RequestTimeout, err = parseDuration(value)
 	case "jwttimeout":
 		cfg.JWTExpireTimeout, err = parseDuration(value)
+	case "jwtissuer":
+		cfg.JWTIssuer, err = parseString(value)
+	case "jwtsubject":
+		cfg.JWTSubject, err = parseString(value)
 	case "externalbrowsertimeout":
 		cfg.ExternalBrowserTimeout, err = parseDuration(value)
+	case "dialtimeout":
+		cfg.DialTimeout, err = parseBackoffDurationValue(value)
+	case "dialnetwork":
+		cfg.DialNetwork, err = parseString(value)
 	case "maxretrycount":
 		cfg.MaxRetryCount, err = parseInt(value)
+	case "retrybackoffbase":
+		cfg.RetryBackoffBase, err = parseBackoffDurationValue(value)
+	case "retrybackoffcap":
+		cfg.RetryBackoffCap, err = parseBackoffDurationValue(value)
 	case "application":
 		cfg.Application, err = parseString(value)
 	case "authenticator":
@@ -154,6 +174,48 @@ func handleSingleParam(cfg *Config, key string, value interface{}) error {
 			return err
 		}
 		cfg.OCSPFailOpen = OCSPFailOpenMode(vv)
+	case "ocspinmemorycachedisabled":
+		cfg.OcspInMemoryCacheDisabled, err = parseBool(value)
+	case "ocspondiskcachedisabled":
+		cfg.OcspOnDiskCacheDisabled, err = parseBool(value)
+	case "certrevocationcheckmode":
+		v, err = parseString(value)
+		if err = checkParsingError(err, key, value); err != nil {
+			return err
+		}
+		cfg.CertRevocationCheckMode, err = parseCertRevocationCheckMode(v)
+	case "crlallowcertificateswithoutcrlurl":
+		cfg.CrlAllowCertificatesWithoutCrlURL, err = parseBool(value)
+	case "crlcachevaliditytime":
+		cfg.CrlCacheValidityTime, err = parseBackoffDurationValue(value)
+	case "crlinmemorycachedisabled":
+		cfg.CrlInMemoryCacheDisabled, err = parseBool(value)
+	case "crlondiskcachedisabled":
+		cfg.CrlOnDiskCacheDisabled, err = parseBool(value)
+	case "crlondiskcachedir":
+		cfg.CrlOnDiskCacheDir, err = parseString(value)
+	case "crlhttpclienttimeout":
+		cfg.CrlHTTPClientTimeout, err = parseBackoffDurationValue(value)
+	case "crlpreloaddir":
+		cfg.CrlPreloadDir, err = parseString(value)
+	case "crldownloaddisabled":
+		cfg.CrlDownloadDisabled, err = parseBool(value)
+	case "crlsharedcache":
+		cfg.CrlSharedCache, err = parseBool(value)
+	case "crlminimumsignaturehash":
+		v, err = parseString(value)
+		if err = checkParsingError(err, key, value); err != nil {
+			return err
+		}
+		cfg.CrlMinimumSignatureHash, err = parseCrlSignatureHash(v)
+	case "crlinmemorycachemaxentries":
+		cfg.CrlInMemoryCacheMaxEntries, err = parseInt(value)
+	case "crlcheckallchains":
+		cfg.CrlCheckAllChains, err = parseBool(value)
+	case "crldownloadretries":
+		cfg.CrlDownloadRetries, err = parseInt(value)
+	case "crldownloadretrybackoff":
+		cfg.CrlDownloadRetryBackoff, err = parseBackoffDurationValue(value)
 	case "token":
 		cfg.Token, err = parseString(value)
 	case "privatekey":
@@ -168,19 +230,37 @@ func handleSingleParam(cfg *Config, key string, value interface{}) error {
 				Message: "Base64 decode failed",
 			}
 		}
-		cfg.PrivateKey, err = parsePKCS8PrivateKey(block)
+		cfg.PrivateKey, err = parsePrivateKey(block, cfg.PrivateKeyPassphrase)
+	case "privatekeypwd":
+		cfg.PrivateKeyPassphrase, err = parseString(value)
 	case "validatedefaultparameters":
 		cfg.ValidateDefaultParameters, err = parseConfigBool(value)
 	case "clientrequestmfatoken":
 		cfg.ClientRequestMfaToken, err = parseConfigBool(value)
 	case "clientstoretemporarycredential":
 		cfg.ClientStoreTemporaryCredential, err = parseConfigBool(value)
+	case "credentialcachedir":
+		cfg.CredentialCacheDir, err = parseString(value)
 	case "tracing":
 		cfg.Tracing, err = parseString(value)
 	case "tmpdirpath":
 		cfg.TmpDirPath, err = parseString(value)
+	case "uploadcompressionlevel":
+		cfg.UploadCompressionLevel, err = parseInt(value)
+	case "maxgetconcurrency":
+		cfg.MaxGetConcurrency, err = parseInt(value)
 	case "disablequerycontextcache":
 		cfg.DisableQueryContextCache, err = parseBool(value)
+	case "failifwarehousesuspended":
+		cfg.FailIfWarehouseSuspended, err = parseBool(value)
+	case "preparedstatementcachesize":
+		cfg.PreparedStatementCacheSize, err = parseInt(value)
+	case "maxstatementspersecond":
+		cfg.MaxStatementsPerSecond, err = parseInt(value)
+	case "validationquery":
+		cfg.ValidationQuery, err = parseString(value)
+	case "arraybindchunksize":
+		cfg.ArrayBindChunkSize, err = parseInt(value)
 	case "includeretryreason":
 		cfg.IncludeRetryReason, err = parseConfigBool(value)
 	case "clientconfigfile":
@@ -189,6 +269,8 @@ func handleSingleParam(cfg *Config, key string, value interface{}) error {
 		cfg.DisableConsoleLogin, err = parseConfigBool(value)
 	case "disablesamlurlcheck":
 		cfg.DisableSamlURLCheck, err = parseConfigBool(value)
+	case "disableoauthurlvalidation":
+		cfg.DisableOAuthURLValidation, err = parseConfigBool(value)
 	case "oauthauthorizationurl":
 		cfg.OauthAuthorizationURL, err = parseString(value)
 	case "oauthclientid":
@@ -201,6 +283,46 @@ func handleSingleParam(cfg *Config, key string, value interface{}) error {
 		cfg.OauthRedirectURI, err = parseString(value)
 	case "oauthscope":
 		cfg.OauthScope, err = parseString(value)
+	case "querytag":
+		cfg.QueryTag, err = parseString(value)
+	case "clientos":
+		cfg.ClientOS, err = parseString(value)
+	case "clientosversion":
+		cfg.ClientOSVersion, err = parseString(value)
+	case "clientgoversion":
+		cfg.ClientGoVersion, err = parseString(value)
+	case "geographyoutputformat":
+		cfg.GeographyOutputFormat, err = parseString(value)
+	case "geometryoutputformat":
+		cfg.GeometryOutputFormat, err = parseString(value)
+	case "proxy":
+		cfg.Proxy, err = parseString(value)
+	case "timestampltztimezone":
+		cfg.TimestampLTZTimezone, err = parseString(value)
+	case "integerscantype":
+		cfg.IntegerScanType, err = parseString(value)
+	case "realscantype":
+		cfg.RealScanType, err = parseString(value)
+	case "oauthredirecthost":
+		cfg.OauthRedirectHost, err = parseString(value)
+	case "oauthredirectportrange":
+		cfg.OauthRedirectPortRange, err = parseString(value)
+	case "tlsminversion":
+		var v string
+		if v, err = parseString(value); err == nil {
+			cfg.TLSMinVersion, err = parseTLSVersion(v)
+		}
+	case "rootcasfile":
+		var v string
+		if v, err = parseString(value); err == nil {
+			cfg.RootCAsFile = v
+			cfg.RootCAs, err = loadRootCAsFromFile(v)
+		}
+	case "tlsciphersuites":
+		var v string
+		if v, err = parseString(value); err == nil {
+			cfg.TLSCipherSuites, err = parseTLSCipherSuites(v)
+		}
 	case "workloadidentityprovider":
 		cfg.WorkloadIdentityProvider, err = parseString(value)
 	case "workloadidentityentraresource":
@@ -286,6 +408,13 @@ func parseDuration(i interface{}) (time.Duration, error) {
 	return parseTimeout(v)
 }
 
+func parseBackoffDurationValue(i interface{}) (time.Duration, error) {
+	if v, ok := i.(string); ok {
+		return parseBackoffDuration(v)
+	}
+	return parseDuration(i)
+}
+
 func readToken(tokenPath string) (string, error) {
 	if tokenPath == "" {
 		tokenPath = defaultTokenPath
@@ -376,7 +505,7 @@ func validateFilePermission(filePath string) error {
 }
 
 func shouldReadTokenFromFile(cfg *Config) bool {
-	return cfg != nil && cfg.Authenticator == AuthTypeOAuth && len(cfg.Token) == 0
+	return cfg != nil && cfg.Authenticator == AuthTypeOAuth && len(cfg.Token) == 0 && cfg.OAuthTokenProvider == nil
 }
 
 func shouldSkipWarningForReadPermissions() bool {
@@ -298,22 +298,37 @@ func TestParseToml(t *testing.T) {
 				"schema", "role", "region", "protocol", "passcode", "application", "token",
 				"tracing", "tmpDirPath", "tmp_dir_path", "clientConfigFile", "client_config_file", "oauth_authorization_url", "oauth_client_id",
 				"oauth_client_secret", "oauth_token_request_url", "oauth_redirect_uri", "oauth_scope",
-				"workload_identity_provider", "workload_identity_entra_resource"},
+				"workload_identity_provider", "workload_identity_entra_resource", "crlOnDiskCacheDir", "crl_preload_dir"},
 			values: []interface{}{"value"},
 		},
+		{
+			testParams: []string{"certRevocationCheckMode", "cert_revocation_check_mode"},
+			values:     []interface{}{"advisory", "ENABLED"},
+		},
+		{
+			testParams: []string{"crlMinimumSignatureHash", "crl_minimum_signature_hash"},
+			values:     []interface{}{"SHA-1", "sha512"},
+		},
+		{
+			testParams: []string{"crlCacheValidityTime", "crl_http_client_timeout", "crlDownloadRetryBackoff", "crl_download_retry_backoff"},
+			values:     []interface{}{"300", 500, "12h"},
+		},
 		{
 			testParams: []string{"privatekey", "private_key"},
 			values:     []interface{}{generatePKCS8StringSupress(testPrivKey)},
 		},
 		{
 			testParams: []string{"port", "maxRetryCount", "max_retry_count", "clientTimeout", "client_timeout", "jwtClientTimeout", "jwt_client_timeout", "loginTimeout",
-				"login_timeout", "requestTimeout", "request_timeout", "jwtTimeout", "jwt_timeout", "externalBrowserTimeout", "external_browser_timeout"},
+				"login_timeout", "requestTimeout", "request_timeout", "jwtTimeout", "jwt_timeout", "externalBrowserTimeout", "external_browser_timeout",
+				"crlInMemoryCacheMaxEntries", "crl_in_memory_cache_max_entries", "crlDownloadRetries", "crl_download_retries"},
 			values: []interface{}{"300", 500},
 		},
 		{
 			testParams: []string{"ocspFailOpen", "ocsp_fail_open", "insecureMode", "insecure_mode", "PasscodeInPassword", "passcode_in_password", "validateDEFAULTParameters", "validate_default_parameters",
 				"clientRequestMFAtoken", "client_request_mfa_token", "clientStoreTemporaryCredential", "client_store_temporary_credential", "disableQueryContextCache", "disable_query_context_cache", "disable_ocsp_checks",
-				"includeRetryReason", "include_retry_reason", "disableConsoleLogin", "disable_console_login", "disableSamlUrlCheck", "disable_saml_url_check"},
+				"includeRetryReason", "include_retry_reason", "disableConsoleLogin", "disable_console_login", "disableSamlUrlCheck", "disable_saml_url_check",
+				"crlAllowCertificatesWithoutCrlURL", "crl_in_memory_cache_disabled", "crlOnDiskCacheDisabled", "crl_download_disabled", "crl_shared_cache",
+				"crlCheckAllChains", "crl_check_all_chains"},
 			values: []interface{}{true, "true", false, "false"},
 		},
 	}
@@ -343,7 +358,8 @@ func TestParseTomlWithWrongValue(t *testing.T) {
 		},
 		{
 			testParams: []string{"port", "maxRetryCount", "clientTimeout", "jwtClientTimeout", "loginTimeout",
-				"requestTimeout", "jwtTimeout", "externalBrowserTimeout", "authenticator"},
+				"requestTimeout", "jwtTimeout", "externalBrowserTimeout", "authenticator", "certRevocationCheckMode", "crlCacheValidityTime", "crlMinimumSignatureHash",
+				"crlInMemoryCacheMaxEntries", "crlDownloadRetries", "crlDownloadRetryBackoff"},
 			values: []interface{}{"wrong_value", false},
 		},
 		{
@@ -3,6 +3,7 @@ package gosnowflake
 import (
 	"bytes"
 	"context"
+	"database/sql/driver"
 	"errors"
 	"fmt"
 	"io"
@@ -105,6 +106,18 @@ func (sc *snowflakeConn) processFileTransfer(
 	if err != nil {
 		return nil, err
 	}
+	if length, ok := ctx.Value(fileStreamLength).(int64); ok && fs != nil {
+		if length < 0 {
+			return nil, (&SnowflakeError{
+				Number:      ErrInvalidFileStreamLength,
+				SQLState:    data.Data.SQLState,
+				QueryID:     data.Data.QueryID,
+				Message:     errMsgInvalidFileStreamLength,
+				MessageArgs: []interface{}{length},
+			}).exceptionTelemetry(sc)
+		}
+		fs = &fixedLengthReader{reader: fs, length: length, remaining: length}
+	}
 	if fs != nil {
 		sfa.sourceStream = fs
 		if isInternal {
@@ -144,6 +157,32 @@ func getFileStream(ctx context.Context) (io.Reader, error) {
 	return r, nil
 }
 
+// fixedLengthReader reads exactly length bytes from the reader
+// and fails if the reader ends earlier.
+type fixedLengthReader struct {
+	reader    io.Reader
+	length    int64
+	remaining int64
+}
+
+func (flr *fixedLengthReader) Read(p []byte) (int, error) {
+	if flr.remaining <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > flr.remaining {
+		p = p[:flr.remaining]
+	}
+	n, err := flr.reader.Read(p)
+	flr.remaining -= int64(n)
+	if err == io.EOF && flr.remaining > 0 {
+		return n, fmt.Errorf("%w: file stream ended after %v of %v bytes", io.ErrUnexpectedEOF, flr.length-flr.remaining, flr.length)
+	}
+	if err == nil && flr.remaining == 0 {
+		return n, io.EOF
+	}
+	return n, err
+}
+
 func getFileTransferOptions(ctx context.Context) *SnowflakeFileTransferOptions {
 	v := ctx.Value(fileTransferOptions)
 	if v == nil {
@@ -199,6 +238,22 @@ func (sc *snowflakeConn) populateSessionParameters(parameters []nameValueParamet
 	}
 }
 
+// restoreSessionParameter returns a function restoring the current value of the session parameter.
+func (sc *snowflakeConn) restoreSessionParameter(name string) func() {
+	paramsMutex.Lock()
+	value, ok := sc.cfg.Params[name]
+	paramsMutex.Unlock()
+	return func() {
+		paramsMutex.Lock()
+		defer paramsMutex.Unlock()
+		if ok {
+			sc.cfg.Params[name] = value
+		} else {
+			delete(sc.cfg.Params, name)
+		}
+	}
+}
+
 func isAsyncMode(ctx context.Context) bool {
 	val := ctx.Value(asyncMode)
 	if val == nil {
@@ -258,6 +313,62 @@ func updateRows(data execResponseData) (int64, error) {
 	return count, nil
 }
 
+// generatedKeyResult returns a result whose LastInsertId is the single integer value
+// returned by the statement, in either the JSON or the Arrow result format.
+func (sc *snowflakeConn) generatedKeyResult(ctx context.Context, data execResponseData) (driver.Result, error) {
+	noGeneratedKey := func(reason string) error {
+		return &SnowflakeError{
+			QueryID:     data.QueryID,
+			Number:      ErrNoGeneratedKey,
+			Message:     errMsgNoGeneratedKey,
+			MessageArgs: []any{reason},
+		}
+	}
+	if len(data.RowType) != 1 {
+		return nil, noGeneratedKey(fmt.Sprintf("%v columns returned", len(data.RowType)))
+	}
+	rows := &snowflakeRows{
+		sc:      sc,
+		queryID: data.QueryID,
+		ctx:     ctx,
+	}
+	rows.addDownloader(populateChunkDownloader(ctx, sc, data))
+	if err := rows.ChunkDownloader.start(); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	dest := make([]driver.Value, 1)
+	if err := rows.Next(dest); err == io.EOF {
+		return nil, noGeneratedKey("no rows returned")
+	} else if err != nil {
+		return nil, err
+	}
+	if err := rows.Next(make([]driver.Value, 1)); err != io.EOF {
+		if err != nil {
+			return nil, err
+		}
+		return nil, noGeneratedKey("more than one row returned")
+	}
+	var id int64
+	switch v := dest[0].(type) {
+	case int64:
+		id = v
+	case nil:
+		return nil, noGeneratedKey("NULL returned")
+	case string:
+		var err error
+		if id, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return nil, noGeneratedKey(fmt.Sprintf("%q is not an integer", v))
+		}
+	default:
+		return nil, noGeneratedKey(fmt.Sprintf("%v value returned", data.RowType[0].Type))
+	}
+	return &snowflakeResult{
+		insertID: id,
+		queryID:  data.QueryID,
+	}, nil
+}
+
 // isMultiStmt returns true if the statement code is of type multistatement
 // Note that the statement type code is also equivalent to type INSERT, so an
 // additional check of the name is required
@@ -5,6 +5,8 @@ import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -13,6 +15,7 @@ import (
 	"math/big"
 	"reflect"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -90,6 +93,9 @@ func goTypeToSnowflake(v driver.Value, tsmode snowflakeType) snowflakeType {
 	if v == nil {
 		return nullType
 	}
+	if tsmode == vectorType && isVector(v) {
+		return vectorType
+	}
 	switch t := v.(type) {
 	case int64, sql.NullInt64:
 		return fixedType
@@ -169,6 +175,13 @@ func snowflakeTypeToGo(ctx context.Context, dbtype snowflakeType, precision int6
 		return reflect.TypeOf([]byte{})
 	case booleanType:
 		return reflect.TypeOf(true)
+	case vectorType:
+		if len(fields) == 1 && getSnowflakeType(fields[0].Type) == fixedType {
+			return reflect.TypeOf([]int32{})
+		}
+		return reflect.TypeOf([]float32{})
+	case geographyType, geometryType:
+		return reflect.TypeOf("")
 	case objectType:
 		if len(fields) > 0 && structuredTypesEnabled {
 			return reflect.TypeOf(ObjectType{})
@@ -274,6 +287,10 @@ func valueToString(v driver.Value, tsmode snowflakeType, params map[string]*stri
 		}
 	}
 
+	if tsmode == vectorType && isVector(v) {
+		return vectorToString(v)
+	}
+
 	switch v1.Kind() {
 	case reflect.Bool:
 		s := strconv.FormatBool(v1.Bool())
@@ -301,6 +318,85 @@ func valueToString(v driver.Value, tsmode snowflakeType, params map[string]*stri
 	return bindingValue{}, fmt.Errorf("unsupported type: %v", v1.Kind())
 }
 
+// geoOutputIsBinary reports whether GEOGRAPHY or GEOMETRY values are returned as WKB or EWKB.
+// The format set in the context takes precedence over the one of the session.
+func geoOutputIsBinary(ctx context.Context, typ snowflakeType, params map[string]*string) bool {
+	if usesGeospatialValues(ctx) {
+		return true
+	}
+	key := geographyOutputFormat
+	if typ == geometryType {
+		key = geometryOutputFormat
+	}
+	format, ok := ctx.Value(key).(string)
+	if !ok {
+		paramsMutex.Lock()
+		if v := params[strings.ToLower(string(key))]; v != nil {
+			format = *v
+		}
+		paramsMutex.Unlock()
+	}
+	format = strings.ToUpper(format)
+	return format == "WKB" || format == "EWKB"
+}
+
+func isVector(v driver.Value) bool {
+	switch v.(type) {
+	case []float32, []int32:
+		return true
+	}
+	return false
+}
+
+// vectorToString converts a []float32 or []int32 to the VECTOR literal, e.g. [1.5,2,3].
+func vectorToString(v driver.Value) (bindingValue, error) {
+	var elements []string
+	switch vector := v.(type) {
+	case []float32:
+		for _, f := range vector {
+			elements = append(elements, strconv.FormatFloat(float64(f), 'g', -1, 32))
+		}
+	case []int32:
+		for _, i := range vector {
+			elements = append(elements, strconv.FormatInt(int64(i), 10))
+		}
+	default:
+		return bindingValue{}, fmt.Errorf("unsupported vector type: %T", v)
+	}
+	s := "[" + strings.Join(elements, ",") + "]"
+	return bindingValue{&s, "", nil}, nil
+}
+
+// jsonToVector decodes a VECTOR returned as JSON into []float32 or []int32 depending on the element type.
+func jsonToVector(columnMeta fieldMetadata, srcValue string) (snowflakeValue, error) {
+	var value snowflakeValue
+	var length int
+	if len(columnMeta.Fields) == 1 && getSnowflakeType(columnMeta.Fields[0].Type) == fixedType {
+		var vector []int32
+		if err := json.Unmarshal([]byte(srcValue), &vector); err != nil {
+			return nil, err
+		}
+		value, length = vector, len(vector)
+	} else {
+		var vector []float32
+		if err := json.Unmarshal([]byte(srcValue), &vector); err != nil {
+			return nil, err
+		}
+		value, length = vector, len(vector)
+	}
+	if err := validateVectorDimension(length, columnMeta.VectorDimension); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func validateVectorDimension(elements int, dimension int) error {
+	if dimension > 0 && elements != dimension {
+		return errVectorDimensionMismatch(elements, dimension)
+	}
+	return nil
+}
+
 // isUUIDImplementer checks if a value is a UUID that satisfies RFC 4122
 func isUUIDImplementer(v reflect.Value) bool {
 	rt := v.Type()
@@ -462,6 +558,9 @@ func mapToString(v driver.Value, tsmode snowflakeType, params map[string]*string
 		return bindingValue{nil, "", nil}, nil
 	}
 	typOf := reflect.TypeOf(v)
+	if valOf.Type().ConvertibleTo(anyMapType) {
+		return anyMapToString(valOf.Convert(anyMapType).Interface().(map[string]any), params)
+	}
 	var jsonBytes []byte
 	if tsmode == binaryType {
 		m := make(map[string]*string, valOf.Len())
@@ -633,6 +732,26 @@ func mapToString(v driver.Value, tsmode snowflakeType, params map[string]*string
 	return bindingValue{&jsonString, jsonFormatStr, &schema}, nil
 }
 
+// anyMapToString binds a map[string]any as an OBJECT whose schema is inferred from the values.
+func anyMapToString(m map[string]any, params map[string]*string) (bindingValue, error) {
+	sowc := structuredObjectWriterContext{}
+	sowc.init(params)
+	if err := sowc.writeMap(m); err != nil {
+		return bindingValue{}, err
+	}
+	jsonBytes, err := json.Marshal(sowc.values)
+	if err != nil {
+		return bindingValue{}, err
+	}
+	jsonString := string(jsonBytes)
+	schema := bindingSchema{
+		Typ:      "object",
+		Nullable: true,
+		Fields:   sowc.toFields(),
+	}
+	return bindingValue{&jsonString, jsonFormatStr, &schema}, nil
+}
+
 func toNullableInt64(val any) (int64, bool) {
 	switch v := val.(type) {
 	case sql.NullByte:
@@ -983,6 +1102,32 @@ func stringToValue(ctx context.Context, dest *driver.Value, srcColumnMeta execRe
 	case "text", "real", "variant":
 		*dest = *srcValue
 		return nil
+	case "geography", "geometry":
+		if geoOutputIsBinary(ctx, getSnowflakeType(srcColumnMeta.Type), params) {
+			b, err := hex.DecodeString(*srcValue)
+			if err != nil {
+				return &SnowflakeError{
+					Number:   ErrInvalidBinaryHexForm,
+					SQLState: SQLStateNumericValueOutOfRange,
+					Message:  err.Error(),
+				}
+			}
+			if usesGeospatialValues(ctx) {
+				*dest, err = parseEWKB(b)
+				return err
+			}
+			*dest = b
+			return nil
+		}
+		*dest = *srcValue
+		return nil
+	case "vector":
+		v, err := jsonToVector(srcColumnMeta.toFieldMetadata(), *srcValue)
+		if err != nil {
+			return err
+		}
+		*dest = v
+		return nil
 	case "fixed":
 		if higherPrecisionEnabled(ctx) {
 			if srcColumnMeta.Scale == 0 {
@@ -1064,7 +1209,7 @@ func stringToValue(ctx context.Context, dest *driver.Value, srcColumnMeta execRe
 		*dest = tt.In(loc)
 		return nil
 	case "binary":
-		b, err := hex.DecodeString(*srcValue)
+		b, err := decodeBinaryOutput(ctx, *srcValue, params)
 		if err != nil {
 			return &SnowflakeError{
 				Number:   ErrInvalidBinaryHexForm,
@@ -1072,7 +1217,7 @@ func stringToValue(ctx context.Context, dest *driver.Value, srcColumnMeta execRe
 				Message:  err.Error(),
 			}
 		}
-		*dest = b
+		*dest = encodeBinaryOutput(ctx, b)
 		return nil
 	case "array":
 		if len(srcColumnMeta.Fields) == 0 || !structuredTypesEnabled {
@@ -1660,7 +1805,23 @@ func arrowToValue(ctx context.Context, rowIdx int, srcColumnMeta fieldMetadata,
 			return buildMapFromNativeArrow(ctx, rowIdx, srcColumnMeta.Fields[0], srcColumnMeta.Fields[1], srcValue, loc, higherPrecision, params)
 		}
 	case binaryType:
-		return arrowBinaryToValue(srcValue.(*array.Binary), rowIdx), nil
+		if b, ok := arrowBinaryToValue(srcValue.(*array.Binary), rowIdx).([]byte); ok {
+			return encodeBinaryOutput(ctx, b), nil
+		}
+		return nil, nil
+	case vectorType:
+		return arrowVectorToValue(srcValue, rowIdx, srcColumnMeta)
+	case geographyType, geometryType:
+		switch geo := srcValue.(type) {
+		case *array.Binary:
+			if b, ok := arrowBinaryToValue(geo, rowIdx).([]byte); ok && usesGeospatialValues(ctx) {
+				return parseEWKB(b)
+			}
+			return arrowBinaryToValue(geo, rowIdx), nil
+		case *array.String:
+			return arrowStringToValue(geo, rowIdx), nil
+		}
+		return nil, fmt.Errorf("unsupported arrow type %v for %v", srcValue.DataType(), snowflakeType)
 	case dateType:
 		return arrowDateToValue(srcValue.(*array.Date32), rowIdx), nil
 	case timeType:
@@ -2465,6 +2626,33 @@ func arrowBinaryToValue(srcValue *array.Binary, rowIdx int) snowflakeValue {
 	return nil
 }
 
+func arrowVectorToValue(srcValue arrow.Array, rowIdx int, srcColumnMeta fieldMetadata) (snowflakeValue, error) {
+	if srcValue.IsNull(rowIdx) {
+		return nil, nil
+	}
+	if strings, ok := srcValue.(*array.String); ok {
+		return jsonToVector(srcColumnMeta, strings.Value(rowIdx))
+	}
+	list, ok := srcValue.(*array.FixedSizeList)
+	if !ok {
+		return nil, fmt.Errorf("unsupported arrow type %v for vector", srcValue.DataType())
+	}
+	start, end := list.ValueOffsets(rowIdx)
+	var value snowflakeValue
+	switch values := list.ListValues().(type) {
+	case *array.Float32:
+		value = slices.Clone(values.Float32Values()[start:end])
+	case *array.Int32:
+		value = slices.Clone(values.Int32Values()[start:end])
+	default:
+		return nil, fmt.Errorf("unsupported arrow type %v for vector elements", values.DataType())
+	}
+	if err := validateVectorDimension(int(end-start), srcColumnMeta.VectorDimension); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
 func arrowDateToValue(srcValue *array.Date32, rowID int) snowflakeValue {
 	if !srcValue.IsNull(rowID) {
 		return time.Unix(int64(srcValue.Value(rowID))*86400, 0).UTC()
@@ -2714,6 +2902,8 @@ func snowflakeArrayToString(nv *driver.NamedValue, stream bool) (snowflakeType,
 			}
 			arr = append(arr, &v)
 		}
+	case reflect.TypeOf(&arrowColumnArray{}):
+		return arrowColumnToString(nv.Value.(*arrowColumnArray).col, stream)
 	default:
 		// Support for bulk array binding insertion using []interface{}
 		nvValue := reflect.ValueOf(nv)
@@ -3244,6 +3434,148 @@ type TypedNullTime struct {
 	TzType timezoneType
 }
 
+// BinaryEncoding is the client side encoding of BINARY values.
+type BinaryEncoding int
+
+const (
+	// BinaryEncodingRaw means the value holds the raw bytes.
+	BinaryEncodingRaw BinaryEncoding = iota
+	// BinaryEncodingHex means the value holds the bytes as hex encoded text.
+	BinaryEncodingHex
+	// BinaryEncodingBase64 means the value holds the bytes as standard base64 encoded text.
+	BinaryEncodingBase64
+)
+
+// TypedBinary binds the value as BINARY. The value is interpreted according to the encoding,
+// so data that is already hex or base64 encoded can be bound without decoding it first.
+// A nil value binds NULL.
+type TypedBinary struct {
+	Value    []byte
+	Encoding BinaryEncoding
+}
+
+// toHex returns the value in the hex form BINARY binds are sent in.
+func (tb TypedBinary) toHex() (*string, error) {
+	if tb.Value == nil {
+		return nil, nil
+	}
+	var b []byte
+	var err error
+	switch tb.Encoding {
+	case BinaryEncodingRaw:
+		b = tb.Value
+	case BinaryEncodingHex:
+		b, err = hex.DecodeString(string(tb.Value))
+		if err != nil {
+			return nil, &SnowflakeError{
+				Number:   ErrInvalidBinaryHexForm,
+				SQLState: SQLStateNumericValueOutOfRange,
+				Message:  err.Error(),
+			}
+		}
+	case BinaryEncodingBase64:
+		b, err = base64.StdEncoding.DecodeString(string(tb.Value))
+		if err != nil {
+			return nil, &SnowflakeError{
+				Number:   ErrInvalidBinaryBase64Form,
+				SQLState: SQLStateNumericValueOutOfRange,
+				Message:  err.Error(),
+			}
+		}
+	default:
+		return nil, &SnowflakeError{
+			Number:      ErrInvalidBinaryEncoding,
+			Message:     errMsgInvalidBinaryEncoding,
+			MessageArgs: []interface{}{tb.Encoding},
+		}
+	}
+	s := hex.EncodeToString(b)
+	return &s, nil
+}
+
+// Geospatial is a GEOGRAPHY or GEOMETRY value returned with WithGeospatialValues.
+// WKB holds the value in the well-known binary format without the SRID, so it can be passed
+// to GIS libraries as is, and SRID holds the spatial reference system identifier of the value.
+type Geospatial struct {
+	SRID int
+	WKB  []byte
+}
+
+// ewkbSRIDFlag is set in the geometry type of EWKB values followed by an SRID.
+const ewkbSRIDFlag = 0x20000000
+
+// parseEWKB splits an EWKB value into its SRID and the WKB of the geometry.
+// A value without an SRID has SRID 0.
+func parseEWKB(b []byte) (Geospatial, error) {
+	if len(b) < 5 {
+		return Geospatial{}, &SnowflakeError{
+			Number:      ErrInvalidEWKB,
+			Message:     errMsgInvalidEWKB,
+			MessageArgs: []interface{}{"value too short"},
+		}
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	if b[0] == 0 {
+		order = binary.BigEndian
+	}
+	geomType := order.Uint32(b[1:5])
+	if geomType&ewkbSRIDFlag == 0 {
+		return Geospatial{WKB: b}, nil
+	}
+	if len(b) < 9 {
+		return Geospatial{}, &SnowflakeError{
+			Number:      ErrInvalidEWKB,
+			Message:     errMsgInvalidEWKB,
+			MessageArgs: []interface{}{"missing SRID"},
+		}
+	}
+	wkb := make([]byte, len(b)-4)
+	wkb[0] = b[0]
+	order.PutUint32(wkb[1:5], geomType&^ewkbSRIDFlag)
+	copy(wkb[5:], b[9:])
+	return Geospatial{SRID: int(order.Uint32(b[5:9])), WKB: wkb}, nil
+}
+
+// decodeBinaryOutput decodes a BINARY value of a JSON result, which the server renders in the
+// BINARY_OUTPUT_FORMAT of the query, HEX by default.
+func decodeBinaryOutput(ctx context.Context, s string, params map[string]*string) ([]byte, error) {
+	format, ok := ctx.Value(binaryOutputFormat).(string)
+	if !ok {
+		paramsMutex.Lock()
+		if v := params[strings.ToLower(string(binaryOutputFormat))]; v != nil {
+			format = *v
+		}
+		paramsMutex.Unlock()
+	}
+	if strings.EqualFold(format, "BASE64") {
+		return base64.StdEncoding.DecodeString(s)
+	}
+	return hex.DecodeString(s)
+}
+
+// encodeBinaryOutput returns the BINARY value in the encoding requested with WithBinaryOutputEncoding,
+// or else in the format requested with WithBinaryOutputFormat. Raw bytes are returned by default.
+func encodeBinaryOutput(ctx context.Context, b []byte) snowflakeValue {
+	encoding, ok := ctx.Value(binaryOutputEncoding).(BinaryEncoding)
+	if !ok {
+		format, _ := ctx.Value(binaryOutputFormat).(string)
+		switch strings.ToUpper(format) {
+		case "HEX":
+			encoding = BinaryEncodingHex
+		case "BASE64":
+			encoding = BinaryEncodingBase64
+		}
+	}
+	switch encoding {
+	case BinaryEncodingHex:
+		return strings.ToUpper(hex.EncodeToString(b))
+	case BinaryEncodingBase64:
+		return base64.StdEncoding.EncodeToString(b)
+	default:
+		return b
+	}
+}
+
 func convertTzTypeToSnowflakeType(tzType timezoneType) snowflakeType {
 	switch tzType {
 	case TimestampNTZType:
@@ -4,11 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math"
 	"math/big"
 	"math/cmplx"
+	"net/url"
 	"reflect"
 	"strconv"
 	"strings"
@@ -156,6 +160,8 @@ func TestSnowflakeTypeToGo(t *testing.T) {
 		{in: fixedType, precision: 38, scale: 0, out: reflect.TypeOf(&big.Int{}), ctx: WithHigherPrecision(context.Background())},
 		{in: fixedType, scale: 2, out: reflect.TypeOf(&big.Float{}), ctx: WithHigherPrecision(context.Background())},
 		{in: realType, scale: 0, out: reflect.TypeOf(float64(0)), ctx: context.Background()},
+		{in: vectorType, fields: []fieldMetadata{{Type: "real"}}, out: reflect.TypeOf([]float32{}), ctx: context.Background()},
+		{in: vectorType, fields: []fieldMetadata{{Type: "fixed"}}, out: reflect.TypeOf([]int32{}), ctx: context.Background()},
 		{in: textType, scale: 0, out: reflect.TypeOf(""), ctx: context.Background()},
 		{in: dateType, scale: 0, out: reflect.TypeOf(time.Now()), ctx: context.Background()},
 		{in: timeType, scale: 0, out: reflect.TypeOf(time.Now()), ctx: context.Background()},
@@ -2638,3 +2644,428 @@ func TestNumbersScanType(t *testing.T) {
 		})
 	}
 }
+
+func TestVectorToValue(t *testing.T) {
+	floatVector := execResponseRowType{Type: "vector", VectorDimension: 3, Fields: []fieldMetadata{{Type: "real"}}}
+	intVector := execResponseRowType{Type: "vector", VectorDimension: 2, Fields: []fieldMetadata{{Type: "fixed"}}}
+
+	t.Run("json", func(t *testing.T) {
+		var dest driver.Value
+		src := "[1.5,-2,3.25]"
+		assertNilF(t, stringToValue(context.Background(), &dest, floatVector, &src, nil, nil))
+		assertDeepEqualE(t, dest, []float32{1.5, -2, 3.25})
+
+		src = "[7,8]"
+		assertNilF(t, stringToValue(context.Background(), &dest, intVector, &src, nil, nil))
+		assertDeepEqualE(t, dest, []int32{7, 8})
+
+		src = "[1.5,2]"
+		err := stringToValue(context.Background(), &dest, floatVector, &src, nil, nil)
+		se, ok := err.(*SnowflakeError)
+		assertTrueF(t, ok)
+		assertEqualE(t, se.Number, ErrVectorDimensionMismatch)
+	})
+
+	t.Run("arrow", func(t *testing.T) {
+		pool := memory.NewCheckedAllocator(memory.NewGoAllocator())
+		defer pool.AssertSize(t, 0)
+		builder := array.NewFixedSizeListBuilder(pool, 3, arrow.PrimitiveTypes.Float32)
+		defer builder.Release()
+		values := builder.ValueBuilder().(*array.Float32Builder)
+		builder.Append(true)
+		values.AppendValues([]float32{1.5, -2, 3.25}, nil)
+		builder.AppendNull()
+		values.AppendValues([]float32{0, 0, 0}, nil)
+		arr := builder.NewArray()
+		defer arr.Release()
+
+		dest := make([]snowflakeValue, 2)
+		assertNilF(t, arrowToValues(context.Background(), dest, floatVector, arr, nil, false, nil))
+		assertDeepEqualE(t, dest[0], []float32{1.5, -2, 3.25})
+		assertNilE(t, dest[1])
+
+		err := arrowToValues(context.Background(), dest, execResponseRowType{Type: "vector", VectorDimension: 4, Fields: floatVector.Fields}, arr, nil, false, nil)
+		se, ok := err.(*SnowflakeError)
+		assertTrueF(t, ok)
+		assertEqualE(t, se.Number, ErrVectorDimensionMismatch)
+	})
+}
+
+func TestVectorBinding(t *testing.T) {
+	bindings, err := getBindValues([]driver.NamedValue{
+		{Ordinal: 1, Value: DataTypeVector},
+		{Ordinal: 2, Value: []float32{1.5, -2, 3.25}},
+		{Ordinal: 3, Value: []int32{7, 8}},
+		{Ordinal: 4, Value: "text"},
+	}, map[string]*string{})
+	assertNilF(t, err)
+	assertEqualE(t, bindings["1"].Type, "VECTOR")
+	assertEqualE(t, *bindings["1"].Value.(*string), "[1.5,-2,3.25]")
+	assertEqualE(t, bindings["2"].Type, "VECTOR")
+	assertEqualE(t, *bindings["2"].Value.(*string), "[7,8]")
+	assertEqualE(t, bindings["3"].Type, "TEXT")
+
+	// without the data type a []float32 is still bound as an array
+	bindings, err = getBindValues([]driver.NamedValue{{Ordinal: 1, Value: []float32{1.5}}}, map[string]*string{})
+	assertNilF(t, err)
+	assertEqualE(t, bindings["1"].Type, "ARRAY")
+}
+
+func TestTypedBinaryBindingRoundTrip(t *testing.T) {
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+		_ *url.Values, _ map[string]string, body []byte, _ time.Duration,
+		_ UUID, _ *Config) (*execResponse, error) {
+		var req execRequest
+		assertNilF(t, json.Unmarshal(body, &req))
+		// the mock server returns the bound value as a BINARY column
+		binding := req.Bindings["1"]
+		assertEqualF(t, binding.Type, "BINARY")
+		value := binding.Value.(string)
+		return &execResponse{
+			Data: execResponseData{
+				RowType:           []execResponseRowType{{Name: "b", Type: "binary"}},
+				RowSet:            [][]*string{{&value}},
+				QueryResultFormat: "json",
+				StatementTypeID:   statementTypeIDSelect,
+			},
+			Code:    "0",
+			Success: true,
+		}, nil
+	}
+	sc := &snowflakeConn{
+		cfg:                 &Config{Params: map[string]*string{}},
+		rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+	}
+	query := func(ctx context.Context, tb TypedBinary) driver.Value {
+		assertNilF(t, sc.CheckNamedValue(&driver.NamedValue{Value: tb}))
+		rows, err := sc.QueryContext(ctx, "SELECT ?", []driver.NamedValue{{Ordinal: 1, Value: tb}})
+		assertNilF(t, err)
+		defer rows.Close()
+		dest := make([]driver.Value, 1)
+		assertNilF(t, rows.Next(dest))
+		return dest[0]
+	}
+	data := []byte{0x00, 0xfb, 0xff, 0x10}
+	for _, tb := range []TypedBinary{
+		{Value: data},
+		{Value: []byte("00fbff10"), Encoding: BinaryEncodingHex},
+		{Value: []byte("APv/EA=="), Encoding: BinaryEncodingBase64},
+	} {
+		assertDeepEqualE(t, query(context.Background(), tb), data)
+		assertEqualE(t, query(WithBinaryOutputEncoding(context.Background(), BinaryEncodingHex), tb), "00FBFF10")
+		assertEqualE(t, query(WithBinaryOutputEncoding(context.Background(), BinaryEncodingBase64), tb), "APv/EA==")
+	}
+
+	var se *SnowflakeError
+	_, err := getBindValues([]driver.NamedValue{{Ordinal: 1, Value: TypedBinary{Value: []byte("zz"), Encoding: BinaryEncodingHex}}}, map[string]*string{})
+	assertTrueF(t, errors.As(err, &se))
+	assertEqualE(t, se.Number, ErrInvalidBinaryHexForm)
+	_, err = getBindValues([]driver.NamedValue{{Ordinal: 1, Value: TypedBinary{Value: []byte("!"), Encoding: BinaryEncodingBase64}}}, map[string]*string{})
+	assertTrueF(t, errors.As(err, &se))
+	assertEqualE(t, se.Number, ErrInvalidBinaryBase64Form)
+
+	bindings, err := getBindValues([]driver.NamedValue{{Ordinal: 1, Value: TypedBinary{}}}, map[string]*string{})
+	assertNilF(t, err)
+	assertEqualE(t, bindings["1"].Type, "BINARY")
+	assertNilE(t, bindings["1"].Value.(*string))
+}
+
+type testAddressValuer struct {
+	city string
+	zip  string
+}
+
+func (a testAddressValuer) Value() (driver.Value, error) {
+	return map[string]any{"address": map[string]any{"city": a.city, "zip": a.zip}, "tags": []any{"home"}}, nil
+}
+
+func TestValuerReturningMapBinding(t *testing.T) {
+	sc := &snowflakeConn{cfg: &Config{Params: map[string]*string{}}}
+	nv := driver.NamedValue{Ordinal: 1, Value: testAddressValuer{city: "Warsaw", zip: "00-001"}}
+	assertNilF(t, sc.CheckNamedValue(&nv))
+	bindings, err := getBindValues([]driver.NamedValue{nv}, map[string]*string{})
+	assertNilF(t, err)
+	binding := bindings["1"]
+	assertEqualF(t, binding.Type, "OBJECT")
+	assertEqualE(t, binding.Format, jsonFormatStr)
+	fieldTypes := make(map[string]string)
+	for _, field := range binding.Schema.Fields {
+		fieldTypes[field.Name] = field.Type
+	}
+	assertDeepEqualE(t, fieldTypes, map[string]string{"address": "object", "tags": "array"})
+	var res map[string]any
+	assertNilF(t, json.Unmarshal([]byte(*binding.Value.(*string)), &res))
+	assertDeepEqualE(t, res, map[string]any{"address": map[string]any{"city": "Warsaw", "zip": "00-001"}, "tags": []any{"home"}})
+
+	t.Run("scalar values are left to database/sql", func(t *testing.T) {
+		nv := driver.NamedValue{Ordinal: 1, Value: sql.NullString{String: "a", Valid: true}}
+		assertNilF(t, sc.CheckNamedValue(&nv))
+		uuid := newTestUUID()
+		nv = driver.NamedValue{Ordinal: 1, Value: uuid}
+		assertErrIsE(t, sc.CheckNamedValue(&nv), driver.ErrSkip)
+		assertEqualE(t, nv.Value, uuid.String())
+	})
+}
+
+func TestAnyMapBindingRoundTrip(t *testing.T) {
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+		_ *url.Values, _ map[string]string, body []byte, _ time.Duration,
+		_ UUID, _ *Config) (*execResponse, error) {
+		var req execRequest
+		assertNilF(t, json.Unmarshal(body, &req))
+		binding := req.Bindings["1"]
+		assertEqualF(t, binding.Type, "OBJECT")
+		assertEqualE(t, binding.Format, jsonFormatStr)
+		assertEqualE(t, binding.Schema.Typ, "object")
+		fieldTypes := make(map[string]string)
+		for _, field := range binding.Schema.Fields {
+			fieldTypes[field.Name] = field.Type
+		}
+		assertDeepEqualE(t, fieldTypes, map[string]string{"name": "text", "count": "real", "active": "boolean", "address": "object", "tags": "array"})
+		// the mock server returns the bound value as an OBJECT column
+		value := binding.Value.(string)
+		return &execResponse{
+			Data: execResponseData{
+				RowType:           []execResponseRowType{{Name: "obj", Type: "object"}},
+				RowSet:            [][]*string{{&value}},
+				QueryResultFormat: "json",
+				StatementTypeID:   statementTypeIDSelect,
+			},
+			Code:    "0",
+			Success: true,
+		}, nil
+	}
+	sc := &snowflakeConn{
+		cfg:                 &Config{Params: map[string]*string{}},
+		rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+	}
+	// numbers are float64 so that the object decoded from JSON has the same shape
+	object := map[string]any{
+		"name":    "snowflake",
+		"count":   float64(3),
+		"active":  true,
+		"address": map[string]any{"city": "Warsaw", "zip": nil},
+		"tags":    []any{"a", "b"},
+	}
+	assertNilF(t, sc.CheckNamedValue(&driver.NamedValue{Value: object}))
+	rows, err := sc.QueryContext(context.Background(), "SELECT ?::OBJECT", []driver.NamedValue{{Ordinal: 1, Value: object}})
+	assertNilF(t, err)
+	defer rows.Close()
+	dest := make([]driver.Value, 1)
+	assertNilF(t, rows.Next(dest))
+	var res map[string]any
+	assertNilF(t, json.Unmarshal([]byte(dest[0].(string)), &res))
+	assertDeepEqualE(t, res, object)
+
+	_, err = getBindValues([]driver.NamedValue{{Ordinal: 1, Value: map[string]any{"mixed": []any{"a", 1}}}}, map[string]*string{})
+	assertNotNilF(t, err)
+	assertStringContainsE(t, err.Error(), "array mixed mixes elements of type text and fixed")
+}
+
+func TestBindErrorNamesParameter(t *testing.T) {
+	_, err := getBindValues([]driver.NamedValue{
+		{Ordinal: 1, Value: int64(1)},
+		{Ordinal: 2, Value: complex(1, 2)},
+	}, map[string]*string{})
+	assertNotNilF(t, err)
+	var bindErr *BindError
+	assertTrueF(t, errors.As(err, &bindErr))
+	assertEqualE(t, bindErr.Position, 2)
+	assertEqualE(t, bindErr.GoType, "complex128")
+	assertEqualE(t, err.Error(), "unsupported type: complex128 (bind parameter 2, Go type complex128)")
+
+	_, err = getBindValues([]driver.NamedValue{{Name: "obj", Ordinal: 1, Value: map[string]any{"mixed": []any{"a", 1}}}}, map[string]*string{})
+	assertNotNilF(t, err)
+	assertHasPrefixE(t, err.Error(), "array mixed mixes elements of type text and fixed")
+	assertStringContainsE(t, err.Error(), `(bind parameter 1 "obj", Go type map[string]interface {}, Snowflake type OBJECT)`)
+}
+
+type testCivilDate struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+func (d *testCivilDate) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%04d-%02d-%02d", d.year, d.month, d.day)), nil
+}
+
+func TestTextMarshalerBinding(t *testing.T) {
+	var bindings map[string]execBindParameter
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+		_ *url.Values, _ map[string]string, body []byte, _ time.Duration,
+		_ UUID, _ *Config) (*execResponse, error) {
+		var req execRequest
+		assertNilF(t, json.Unmarshal(body, &req))
+		bindings = req.Bindings
+		return &execResponse{
+			Data:    execResponseData{StatementTypeID: statementTypeIDDml},
+			Code:    "0",
+			Success: true,
+		}, nil
+	}
+	sc := &snowflakeConn{
+		cfg:                 &Config{Params: map[string]*string{}},
+		rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+	}
+	args := []driver.NamedValue{
+		{Ordinal: 1, Value: &testCivilDate{2024, time.February, 29}},
+		{Ordinal: 2, Value: (*testCivilDate)(nil)},
+	}
+	for i := range args {
+		assertNilF(t, sc.CheckNamedValue(&args[i]))
+	}
+	_, err := sc.ExecContext(context.Background(), "INSERT INTO t VALUES (?, ?)", args)
+	assertNilF(t, err)
+	assertEqualE(t, bindings["1"].Type, "TEXT")
+	assertEqualE(t, bindings["1"].Value, "2024-02-29")
+	assertEqualE(t, bindings["2"].Type, "TEXT")
+	assertNilE(t, bindings["2"].Value)
+
+	// time.Time implements encoding.TextMarshaler but is bound as a timestamp
+	assertEqualE(t, sc.CheckNamedValue(&driver.NamedValue{Value: time.Now()}), driver.ErrSkip)
+}
+
+func TestVectorRoundTrip(t *testing.T) {
+	for _, forceFormat := range []string{forceJSON, forceARROW} {
+		t.Run(forceFormat, func(t *testing.T) {
+			runDBTest(t, func(dbt *DBTest) {
+				dbt.mustExecT(t, forceFormat)
+				dbt.mustExecT(t, "CREATE OR REPLACE TABLE test_vector (f VECTOR(FLOAT, 3), i VECTOR(INT, 2))")
+				defer dbt.mustExecT(t, "DROP TABLE IF EXISTS test_vector")
+				dbt.mustExecT(t, "INSERT INTO test_vector SELECT ?, ?", DataTypeVector, []float32{1.5, -2, 3.25}, []int32{7, 8})
+
+				rows := dbt.mustQueryContextT(context.Background(), t, "SELECT f, i FROM test_vector")
+				defer rows.Close()
+				rows.mustNext()
+				var floats []float32
+				var ints []int32
+				rows.mustScan(&floats, &ints)
+				assertDeepEqualE(t, floats, []float32{1.5, -2, 3.25})
+				assertDeepEqualE(t, ints, []int32{7, 8})
+
+				types, err := rows.ColumnTypes()
+				assertNilF(t, err)
+				assertEqualE(t, types[0].DatabaseTypeName(), "VECTOR")
+				assertEqualE(t, types[0].ScanType(), reflect.TypeOf([]float32{}))
+				assertEqualE(t, types[1].ScanType(), reflect.TypeOf([]int32{}))
+			})
+		})
+	}
+}
+
+func TestGeoOutputFormats(t *testing.T) {
+	geoJSON := `{"coordinates":[1,2],"type":"Point"}`
+	wkt := "POINT(1 2)"
+	wkbHex := "0101000000000000000000F03F0000000000000040"
+	wkb, err := hex.DecodeString(wkbHex)
+	assertNilF(t, err)
+
+	for _, typ := range []string{"geography", "geometry"} {
+		t.Run(typ, func(t *testing.T) {
+			param := strings.ToLower(typ) + "_output_format"
+			rowType := execResponseRowType{Type: typ}
+			testcases := []struct {
+				format   string
+				raw      string
+				expected driver.Value
+			}{
+				{format: "GeoJSON", raw: geoJSON, expected: geoJSON},
+				{format: "WKT", raw: wkt, expected: wkt},
+				{format: "WKB", raw: wkbHex, expected: wkb},
+			}
+			for _, tc := range testcases {
+				t.Run(tc.format, func(t *testing.T) {
+					var dest driver.Value
+					format := tc.format
+					params := map[string]*string{param: &format}
+					assertNilF(t, stringToValue(context.Background(), &dest, rowType, &tc.raw, nil, params))
+					assertDeepEqualE(t, dest, tc.expected)
+				})
+			}
+
+			t.Run("context overrides session", func(t *testing.T) {
+				var dest driver.Value
+				format := "GeoJSON"
+				ctx := WithGeographyOutputFormat(WithGeometryOutputFormat(context.Background(), "WKB"), "WKB")
+				raw := wkbHex
+				assertNilF(t, stringToValue(ctx, &dest, rowType, &raw, nil, map[string]*string{param: &format}))
+				assertDeepEqualE(t, dest, wkb)
+			})
+
+			t.Run("arrow", func(t *testing.T) {
+				pool := memory.NewCheckedAllocator(memory.NewGoAllocator())
+				defer pool.AssertSize(t, 0)
+				dest := make([]snowflakeValue, 1)
+
+				sb := array.NewStringBuilder(pool)
+				defer sb.Release()
+				sb.Append(wkt)
+				strs := sb.NewArray()
+				defer strs.Release()
+				assertNilF(t, arrowToValues(context.Background(), dest, rowType, strs, nil, false, nil))
+				assertEqualE(t, dest[0], wkt)
+
+				bb := array.NewBinaryBuilder(pool, arrow.BinaryTypes.Binary)
+				defer bb.Release()
+				bb.Append(wkb)
+				bins := bb.NewArray()
+				defer bins.Release()
+				assertNilF(t, arrowToValues(context.Background(), dest, rowType, bins, nil, false, nil))
+				assertDeepEqualE(t, dest[0], wkb)
+			})
+		})
+	}
+}
+
+func TestGeospatialValues(t *testing.T) {
+	// POINT(1 2) with SRID 3857
+	ewkbHex := "0101000020110F0000000000000000F03F0000000000000040"
+	ewkb, err := hex.DecodeString(ewkbHex)
+	assertNilF(t, err)
+	wkb, err := hex.DecodeString("0101000000000000000000F03F0000000000000040")
+	assertNilF(t, err)
+	expected := Geospatial{SRID: 3857, WKB: wkb}
+	ctx := WithGeospatialValues(context.Background())
+
+	for _, typ := range []string{"geography", "geometry"} {
+		t.Run(typ, func(t *testing.T) {
+			rowType := execResponseRowType{Type: typ}
+
+			var dest driver.Value
+			raw := ewkbHex
+			assertNilF(t, stringToValue(ctx, &dest, rowType, &raw, nil, nil))
+			assertDeepEqualE(t, dest, expected)
+
+			pool := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer pool.AssertSize(t, 0)
+			bb := array.NewBinaryBuilder(pool, arrow.BinaryTypes.Binary)
+			defer bb.Release()
+			bb.Append(ewkb)
+			bins := bb.NewArray()
+			defer bins.Release()
+			values := make([]snowflakeValue, 1)
+			assertNilF(t, arrowToValues(ctx, values, rowType, bins, nil, false, nil))
+			assertDeepEqualE(t, values[0], expected)
+		})
+	}
+
+	t.Run("without SRID", func(t *testing.T) {
+		geo, err := parseEWKB(wkb)
+		assertNilF(t, err)
+		assertDeepEqualE(t, geo, Geospatial{WKB: wkb})
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := parseEWKB(ewkb[:7])
+		var se *SnowflakeError
+		assertTrueF(t, errors.As(err, &se))
+		assertEqualE(t, se.Number, ErrInvalidEWKB)
+	})
+}
@@ -0,0 +1,166 @@
+package gosnowflake
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	copyFromReaderStagePrefix = "@~/gosnowflake_copy/"
+	copyFromReaderFileName    = "data"
+	defaultCopyFileFormat     = "TYPE = CSV"
+)
+
+// CopyFromReaderOptions configures how CopyFromReader loads the data.
+type CopyFromReaderOptions struct {
+	FileFormat         string // FILE_FORMAT of the data, e.g. "TYPE = CSV SKIP_HEADER = 1". "TYPE = CSV" by default
+	CopyOptions        string // Additional COPY INTO options, e.g. "ON_ERROR = CONTINUE"
+	DisableCompression bool   // Uploads the data as is instead of compressing it with gzip on the fly
+}
+
+// CopyResult contains the load statistics of a COPY INTO command summed up over all loaded files.
+type CopyResult struct {
+	RowsParsed int64
+	RowsLoaded int64
+	ErrorsSeen int64
+	FirstError string // first error reported by Snowflake, empty if there were no errors
+}
+
+// CopyFromReader streams the data from the reader to a user stage location
+// and loads it into the table with COPY INTO. The staged file is purged once loaded,
+// or removed if the COPY fails. Large data is uploaded in parts the same way as PUT
+// from a file stream.
+func (sc *snowflakeConn) CopyFromReader(ctx context.Context, table string, reader io.Reader, options *CopyFromReaderOptions) (result *CopyResult, err error) {
+	if options == nil {
+		options = &CopyFromReaderOptions{}
+	}
+	fileFormat := options.FileFormat
+	if fileFormat == "" {
+		fileFormat = defaultCopyFileFormat
+	}
+	stageLocation := copyFromReaderStagePrefix + NewUUID().String()
+
+	// the file name in the PUT command is a placeholder, the data comes from the stream
+	putCommand := fmt.Sprintf("PUT 'file:///tmp/placeholder/%v' '%v' OVERWRITE = TRUE AUTO_COMPRESS = %v",
+		copyFromReaderFileName, stageLocation, strings.ToUpper(strconv.FormatBool(!options.DisableCompression)))
+	putCtx := WithFileStream(ctx, reader)
+	if _, err = sc.exec(putCtx, putCommand, false /* noResult */, false /* isInternal */, false /* describeOnly */, nil); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			sc.removeStagedCopyFile(ctx, stageLocation)
+		}
+	}()
+
+	copyCommand := fmt.Sprintf("COPY INTO %v FROM '%v' FILE_FORMAT = (%v) PURGE = TRUE", table, stageLocation, fileFormat)
+	if options.CopyOptions != "" {
+		copyCommand += " " + options.CopyOptions
+	}
+	rows, err := sc.QueryContext(ctx, copyCommand, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return readCopyResult(rows)
+}
+
+// removeStagedCopyFile removes the file staged by CopyFromReader, which PURGE did not remove
+// as the COPY failed. The removal runs even if the context of the COPY was cancelled.
+func (sc *snowflakeConn) removeStagedCopyFile(ctx context.Context, stageLocation string) {
+	removeCommand := fmt.Sprintf("REMOVE '%v'", stageLocation)
+	if _, err := sc.exec(context.WithoutCancel(ctx), removeCommand, false /* noResult */, true /* isInternal */, false /* describeOnly */, nil); err != nil {
+		logger.WithContext(ctx).Warnf("failed to remove the staged file %v: %v", stageLocation, err)
+	}
+}
+
+// CopyLoadResult is the outcome of loading a single file by COPY INTO.
+type CopyLoadResult struct {
+	File           string
+	Status         string // LOADED, LOAD_FAILED, PARTIALLY_LOADED or LOAD_SKIPPED
+	RowsParsed     int64
+	RowsLoaded     int64
+	ErrorsSeen     int64
+	FirstError     string // empty if the file was loaded without errors
+	FirstErrorLine int64
+}
+
+// CopyInto runs the COPY INTO command and returns the load result of every file,
+// including the files that failed to load with ON_ERROR = CONTINUE or SKIP_FILE.
+func (sc *snowflakeConn) CopyInto(ctx context.Context, copyCommand string) ([]CopyLoadResult, error) {
+	rows, err := sc.QueryContext(ctx, copyCommand, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return readCopyLoadResults(rows)
+}
+
+func readCopyResult(rows driver.Rows) (*CopyResult, error) {
+	loadResults, err := readCopyLoadResults(rows)
+	if err != nil {
+		return nil, err
+	}
+	result := &CopyResult{}
+	for _, loadResult := range loadResults {
+		result.RowsParsed += loadResult.RowsParsed
+		result.RowsLoaded += loadResult.RowsLoaded
+		result.ErrorsSeen += loadResult.ErrorsSeen
+		if result.FirstError == "" {
+			result.FirstError = loadResult.FirstError
+		}
+	}
+	return result, nil
+}
+
+func readCopyLoadResults(rows driver.Rows) ([]CopyLoadResult, error) {
+	columns := rows.Columns()
+	dest := make([]driver.Value, len(columns))
+	results := make([]CopyLoadResult, 0)
+	for {
+		if err := rows.Next(dest); err == io.EOF {
+			return results, nil
+		} else if err != nil {
+			return nil, err
+		}
+		var result CopyLoadResult
+		for i, column := range columns {
+			switch strings.ToLower(column) {
+			case "file":
+				result.File, _ = dest[i].(string)
+			case "status":
+				result.Status, _ = dest[i].(string)
+			case "rows_parsed":
+				result.RowsParsed = copyResultInt(dest[i])
+			case "rows_loaded":
+				result.RowsLoaded = copyResultInt(dest[i])
+			case "errors_seen":
+				result.ErrorsSeen = copyResultInt(dest[i])
+			case "first_error":
+				result.FirstError, _ = dest[i].(string)
+			case "first_error_line":
+				result.FirstErrorLine = copyResultInt(dest[i])
+			}
+		}
+		results = append(results, result)
+	}
+}
+
+func copyResultInt(value driver.Value) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case string:
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0
+		}
+		return i
+	default:
+		return 0
+	}
+}
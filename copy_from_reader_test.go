@@ -0,0 +1,192 @@
+package gosnowflake
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCopyFromReader(t *testing.T) {
+	stageDir := t.TempDir()
+	var queries []string
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+		_ *url.Values, _ map[string]string, body []byte, _ time.Duration,
+		_ UUID, _ *Config) (*execResponse, error) {
+		var req execRequest
+		assertNilF(t, json.Unmarshal(body, &req))
+		queries = append(queries, req.SQLText)
+		if strings.HasPrefix(req.SQLText, "PUT") {
+			return &execResponse{
+				Data: execResponseData{
+					Command:           string(uploadCommand),
+					SrcLocations:      []string{"/tmp/placeholder/data"},
+					SourceCompression: "auto_detect",
+					Overwrite:         true,
+					AutoCompress:      strings.Contains(req.SQLText, "AUTO_COMPRESS = TRUE"),
+					StageInfo: execResponseStageInfo{
+						LocationType: string(local),
+						Location:     stageDir,
+					},
+				},
+				Code:    "0",
+				Success: true,
+			}, nil
+		}
+		// the mock stage loads every staged file
+		var loaded int64
+		entries, err := os.ReadDir(stageDir)
+		assertNilF(t, err)
+		for _, entry := range entries {
+			f, err := os.Open(filepath.Join(stageDir, entry.Name()))
+			assertNilF(t, err)
+			gz, err := gzip.NewReader(f)
+			assertNilF(t, err)
+			scanner := bufio.NewScanner(gz)
+			for scanner.Scan() {
+				loaded++
+			}
+			assertNilF(t, f.Close())
+		}
+		rowsLoaded := strconv.FormatInt(loaded, 10)
+		status := "LOADED"
+		errorsSeen := "0"
+		return &execResponse{
+			Data: execResponseData{
+				RowType: []execResponseRowType{
+					{Name: "status", Type: "text"},
+					{Name: "rows_parsed", Type: "fixed"},
+					{Name: "rows_loaded", Type: "fixed"},
+					{Name: "errors_seen", Type: "fixed"},
+					{Name: "first_error", Type: "text", Nullable: true},
+				},
+				RowSet:            [][]*string{{&status, &rowsLoaded, &rowsLoaded, &errorsSeen, nil}},
+				QueryResultFormat: "json",
+				StatementTypeID:   statementTypeIDDml,
+			},
+			Code:    "0",
+			Success: true,
+		}, nil
+	}
+	sc := &snowflakeConn{
+		cfg:                 &Config{Params: map[string]*string{}, TmpDirPath: t.TempDir()},
+		rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+	}
+
+	csv := "1,a\n2,b\n3,c\n"
+	result, err := sc.CopyFromReader(context.Background(), "my_table", strings.NewReader(csv),
+		&CopyFromReaderOptions{CopyOptions: "ON_ERROR = CONTINUE"})
+	assertNilF(t, err)
+	assertEqualE(t, result.RowsLoaded, int64(3))
+	assertEqualE(t, result.RowsParsed, int64(3))
+	assertEqualE(t, result.ErrorsSeen, int64(0))
+	assertEqualE(t, result.FirstError, "")
+
+	assertEqualF(t, len(queries), 2)
+	assertHasPrefixE(t, queries[0], "PUT 'file:///tmp/placeholder/data' '@~/gosnowflake_copy/")
+	assertHasPrefixE(t, queries[1], "COPY INTO my_table FROM '@~/gosnowflake_copy/")
+	assertTrueE(t, strings.HasSuffix(queries[1], "FILE_FORMAT = (TYPE = CSV) PURGE = TRUE ON_ERROR = CONTINUE"), queries[1])
+}
+
+func TestCopyIntoReturnsLoadResultPerFile(t *testing.T) {
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+		_ *url.Values, _ map[string]string, _ []byte, _ time.Duration,
+		_ UUID, _ *Config) (*execResponse, error) {
+		str := func(s string) *string { return &s }
+		return &execResponse{
+			Data: execResponseData{
+				RowType: []execResponseRowType{
+					{Name: "file", Type: "text"},
+					{Name: "status", Type: "text"},
+					{Name: "rows_parsed", Type: "fixed"},
+					{Name: "rows_loaded", Type: "fixed"},
+					{Name: "error_limit", Type: "fixed"},
+					{Name: "errors_seen", Type: "fixed"},
+					{Name: "first_error", Type: "text", Nullable: true},
+					{Name: "first_error_line", Type: "fixed", Nullable: true},
+				},
+				RowSet: [][]*string{
+					{str("s3://bucket/ok.csv"), str("LOADED"), str("3"), str("3"), str("3"), str("0"), nil, nil},
+					{str("s3://bucket/bad.csv"), str("PARTIALLY_LOADED"), str("4"), str("3"), str("4"), str("1"),
+						str("Numeric value 'abc' is not recognized"), str("2")},
+				},
+				QueryResultFormat: "json",
+				StatementTypeID:   statementTypeIDDml,
+			},
+			Code:    "0",
+			Success: true,
+		}, nil
+	}
+	sc := &snowflakeConn{
+		cfg:                 &Config{Params: map[string]*string{}},
+		rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+	}
+
+	results, err := sc.CopyInto(context.Background(), "COPY INTO my_table FROM @my_stage ON_ERROR = CONTINUE")
+	assertNilF(t, err)
+	assertDeepEqualE(t, results, []CopyLoadResult{
+		{File: "s3://bucket/ok.csv", Status: "LOADED", RowsParsed: 3, RowsLoaded: 3},
+		{File: "s3://bucket/bad.csv", Status: "PARTIALLY_LOADED", RowsParsed: 4, RowsLoaded: 3, ErrorsSeen: 1,
+			FirstError: "Numeric value 'abc' is not recognized", FirstErrorLine: 2},
+	})
+}
+
+func TestCopyFromReaderRemovesStagedFileWhenCopyFails(t *testing.T) {
+	var queries []string
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+		_ *url.Values, _ map[string]string, body []byte, _ time.Duration,
+		_ UUID, _ *Config) (*execResponse, error) {
+		var req execRequest
+		assertNilF(t, json.Unmarshal(body, &req))
+		queries = append(queries, req.SQLText)
+		switch {
+		case strings.HasPrefix(req.SQLText, "PUT"):
+			return &execResponse{
+				Data: execResponseData{
+					Command:           string(uploadCommand),
+					SrcLocations:      []string{"/tmp/placeholder/data"},
+					SourceCompression: "auto_detect",
+					Overwrite:         true,
+					StageInfo: execResponseStageInfo{
+						LocationType: string(local),
+						Location:     t.TempDir(),
+					},
+				},
+				Code:    "0",
+				Success: true,
+			}, nil
+		case strings.HasPrefix(req.SQLText, "COPY"):
+			return &execResponse{
+				Data:    execResponseData{SQLState: "42S02"},
+				Code:    "002003",
+				Message: "Table 'MISSING' does not exist",
+				Success: false,
+			}, nil
+		default:
+			return &execResponse{Code: "0", Success: true}, nil
+		}
+	}
+	sc := &snowflakeConn{
+		cfg:                 &Config{Params: map[string]*string{}, TmpDirPath: t.TempDir()},
+		rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+	}
+
+	_, err := sc.CopyFromReader(context.Background(), "missing", strings.NewReader("1,a\n"), nil)
+	assertNotNilF(t, err)
+	assertEqualF(t, len(queries), 3)
+	stageLocation := strings.TrimSuffix(strings.TrimPrefix(queries[1], "COPY INTO missing FROM '"), "' FILE_FORMAT = (TYPE = CSV) PURGE = TRUE")
+	assertEqualE(t, queries[2], "REMOVE '"+stageLocation+"'")
+}
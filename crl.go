@@ -1,40 +1,68 @@
 package gosnowflake
 
 import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto"
+	"crypto/tls"
 	"crypto/x509"
 	"encoding/asn1"
+	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 var idpOID = asn1.ObjectIdentifier{2, 5, 29, 28}
 
+// certificateIssuerOID identifies the CRL entry extension naming the issuer of the revoked
+// certificate in indirect CRLs, for this entry and the following ones.
+var certificateIssuerOID = asn1.ObjectIdentifier{2, 5, 29, 29}
+
+// directoryNameTag is the tag of the directoryName choice of GeneralName.
+const directoryNameTag = 4
+
 type distributionPointName struct {
 	FullName []asn1.RawValue `asn1:"optional,tag:0"`
 }
 
 type issuingDistributionPoint struct {
-	DistributionPoint distributionPointName `asn1:"optional,tag:0"`
+	DistributionPoint          distributionPointName `asn1:"optional,tag:0"`
+	OnlyContainsUserCerts      bool                  `asn1:"optional,tag:1"`
+	OnlyContainsCACerts        bool                  `asn1:"optional,tag:2"`
+	OnlySomeReasons            asn1.BitString        `asn1:"optional,tag:3"`
+	IndirectCRL                bool                  `asn1:"optional,tag:4"`
+	OnlyContainsAttributeCerts bool                  `asn1:"optional,tag:5"`
 }
 
 type crlValidator struct {
+	*crlCache
 	certRevocationCheckMode        CertRevocationCheckMode
 	allowCertificatesWithoutCrlURL bool
 	cacheValidityTime              time.Duration
 	inMemoryCacheDisabled          bool
-	inMemoryCache                  map[string]*crlInMemoryCacheValueType
-	inMemoryCacheMutex             sync.Mutex
 	onDiskCacheDisabled            bool
 	onDiskCacheDir                 string
 	onDiskCacheRemovalDelay        time.Duration
-	crlURLMutexes                  map[string]*sync.Mutex
 	httpClient                     *http.Client
+	structuredLogger               *slog.Logger                          // optional, receives a record of each CRL download
+	minimumSignatureHash           crypto.Hash                           // CRLs signed with a weaker hash are not trusted, 0 accepts any
+	preloadedCrls                  map[string]*crlInMemoryCacheValueType // by raw issuer, see preloadCrls
+	downloadDisabled               bool                                  // only the cached and preloaded CRLs are used
+	checkAllChains                 bool                                  // check every chain for auditing instead of stopping at the first unrevoked one
+	downloadRetries                int                                   // retries of a CRL download failing with a network or server error
+	downloadRetryBackoff           time.Duration                         // wait before the first retry, doubled for each next one
+	auditSink                      func([]CrlChainAudit)                 // optional, receives the verdicts of the checked chains of each verification
 	cleanupStopChan                chan struct{}
 	cleanupDoneChan                chan struct{}
 }
@@ -44,27 +72,309 @@ type crlInMemoryCacheValueType struct {
 	downloadTime *time.Time
 }
 
-func newCrlValidator(certRevocationCheckMode CertRevocationCheckMode, allowCertificatesWithoutCrlURL bool, cacheValidityTime time.Duration, inMemoryCacheDisabled, onDiskCacheDisabled bool, onDiskCacheDir string, httpClient *http.Client) *crlValidator {
+const (
+	// defaultCrlDownloadRetries bounds the retries of the CRL downloads, which delay the handshake.
+	defaultCrlDownloadRetries      = 2
+	defaultCrlDownloadRetryBackoff = 100 * time.Millisecond
+)
+
+// defaultCrlInMemoryCacheMaxEntries bounds the CRLs kept in memory, the least recently used
+// ones being evicted. The evicted CRLs are promoted again from the on-disk cache when needed.
+const defaultCrlInMemoryCacheMaxEntries = 100
+
+// crlCache holds the CRLs kept in memory by a validator and serializes their downloads.
+// Validators created with newSharedCacheCrlValidator share it with the other validators
+// using the same on-disk cache directory.
+type crlCache struct {
+	inMemoryCache      map[string]*crlInMemoryCacheValueType
+	inMemoryCacheMutex sync.Mutex
+	crlURLMutexes      map[string]*sync.Mutex
+	maxEntries         int                      // 0 keeps every CRL in memory
+	order              *list.List               // CRL URLs, the most recently used first
+	orderElements      map[string]*list.Element // by CRL URL
+}
+
+func newCrlCache(inMemoryCacheDisabled bool) *crlCache {
 	var inMemoryCache map[string]*crlInMemoryCacheValueType
 	if !inMemoryCacheDisabled {
 		inMemoryCache = make(map[string]*crlInMemoryCacheValueType)
 	}
+	return &crlCache{
+		inMemoryCache: inMemoryCache,
+		crlURLMutexes: make(map[string]*sync.Mutex),
+		maxEntries:    defaultCrlInMemoryCacheMaxEntries,
+		order:         list.New(),
+		orderElements: make(map[string]*list.Element),
+	}
+}
+
+// setMaxEntries changes the number of CRLs kept in memory, evicting the least recently used ones if needed.
+func (c *crlCache) setMaxEntries(maxEntries int) {
+	c.inMemoryCacheMutex.Lock()
+	defer c.inMemoryCacheMutex.Unlock()
+	c.maxEntries = maxEntries
+	c.evictLocked()
+}
+
+// getLocked returns the CRL of the URL and marks it as the most recently used.
+// The inMemoryCacheMutex must be held.
+func (c *crlCache) getLocked(crlURL string) (*crlInMemoryCacheValueType, bool) {
+	value, ok := c.inMemoryCache[crlURL]
+	if ok {
+		c.touchLocked(crlURL)
+	}
+	return value, ok
+}
+
+// storeLocked caches the CRL of the URL in memory, evicting the least recently used CRLs
+// beyond maxEntries. The inMemoryCacheMutex must be held.
+func (c *crlCache) storeLocked(crlURL string, value *crlInMemoryCacheValueType) {
+	c.inMemoryCache[crlURL] = value
+	c.touchLocked(crlURL)
+	c.evictLocked()
+}
+
+// deleteLocked drops the CRL of the URL from memory. The inMemoryCacheMutex must be held.
+func (c *crlCache) deleteLocked(crlURL string) {
+	delete(c.inMemoryCache, crlURL)
+	if elem, ok := c.orderElements[crlURL]; ok {
+		c.order.Remove(elem)
+		delete(c.orderElements, crlURL)
+	}
+}
+
+func (c *crlCache) touchLocked(crlURL string) {
+	if elem, ok := c.orderElements[crlURL]; ok {
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.orderElements[crlURL] = c.order.PushFront(crlURL)
+}
+
+func (c *crlCache) evictLocked() {
+	for c.maxEntries > 0 && len(c.inMemoryCache) > c.maxEntries && c.order.Len() > 0 {
+		oldest := c.order.Back().Value.(string)
+		logger.Debugf("evicting the least recently used CRL for %v from the in-memory cache", oldest)
+		c.deleteLocked(oldest)
+	}
+}
+
+// sharedCrlCaches maps the cleaned on-disk cache directories to the CRL caches shared in the process.
+var sharedCrlCaches sync.Map
+
+func newCrlValidator(certRevocationCheckMode CertRevocationCheckMode, allowCertificatesWithoutCrlURL bool, cacheValidityTime time.Duration, inMemoryCacheDisabled, onDiskCacheDisabled bool, onDiskCacheDir string, httpClient *http.Client, structuredLogger *slog.Logger) *crlValidator {
 	return &crlValidator{
 		certRevocationCheckMode:        certRevocationCheckMode,
 		allowCertificatesWithoutCrlURL: allowCertificatesWithoutCrlURL,
 		cacheValidityTime:              cacheValidityTime,
 		inMemoryCacheDisabled:          inMemoryCacheDisabled,
-		inMemoryCache:                  inMemoryCache,
+		crlCache:                       newCrlCache(inMemoryCacheDisabled),
 		onDiskCacheDisabled:            onDiskCacheDisabled,
 		onDiskCacheDir:                 onDiskCacheDir,
 		onDiskCacheRemovalDelay:        7 * 24 * time.Hour, // 7 days
-		crlURLMutexes:                  make(map[string]*sync.Mutex),
+		minimumSignatureHash:           crypto.SHA256,
+		downloadRetries:                defaultCrlDownloadRetries,
+		downloadRetryBackoff:           defaultCrlDownloadRetryBackoff,
 		httpClient:                     httpClient,
+		structuredLogger:               structuredLogger,
 		cleanupStopChan:                make(chan struct{}),
 		cleanupDoneChan:                make(chan struct{}),
 	}
 }
 
+// newSharedCacheCrlValidator creates a validator sharing its in-memory cache with the other
+// validators of the process using the same on-disk cache directory, so the connection pools
+// of different sql.DB handles download each CRL once. The validators keep their own check mode,
+// cache validity time and HTTP client.
+func newSharedCacheCrlValidator(certRevocationCheckMode CertRevocationCheckMode, allowCertificatesWithoutCrlURL bool, cacheValidityTime time.Duration, inMemoryCacheDisabled, onDiskCacheDisabled bool, onDiskCacheDir string, httpClient *http.Client, structuredLogger *slog.Logger) *crlValidator {
+	cv := newCrlValidator(certRevocationCheckMode, allowCertificatesWithoutCrlURL, cacheValidityTime, inMemoryCacheDisabled, onDiskCacheDisabled, onDiskCacheDir, httpClient, structuredLogger)
+	cache, _ := sharedCrlCaches.LoadOrStore(filepath.Clean(onDiskCacheDir), newCrlCache(false))
+	cv.crlCache = cache.(*crlCache)
+	return cv
+}
+
+// crlTLSTransports caches the transports downloading the CRLs with CrlTLSConfig.
+var crlTLSTransports = newTransportCache(maxCachedTransports)
+
+// withCrlTLSConfig returns a copy of the transport downloading the CRLs with their own TLS
+// configuration instead of the one of the connections to Snowflake, e.g. to present a client
+// certificate to a mutually authenticated distribution point. The transport is otherwise kept,
+// as the CRL downloads do not check the revocation themselves.
+func withCrlTLSConfig(rt http.RoundTripper, tlsConfig *tls.Config) http.RoundTripper {
+	transport, ok := rt.(*http.Transport)
+	if !ok || tlsConfig == nil {
+		return rt
+	}
+	return crlTLSTransports.getOrCreate(transport, tlsConfig, nil, func() *http.Transport {
+		custom := transport.Clone()
+		custom.TLSClientConfig = tlsConfig.Clone()
+		return custom
+	})
+}
+
+const (
+	defaultCrlCacheValidityTime    = 24 * time.Hour
+	defaultCrlHTTPClientTimeout    = 10 * time.Second
+	defaultCrlCacheCleanupTickRate = time.Hour
+)
+
+// crlCacheSettings are the settings of the CRLs cached and preloaded for the configs.
+type crlCacheSettings struct {
+	cacheValidityTime     time.Duration
+	inMemoryCacheDisabled bool
+	onDiskCacheDisabled   bool
+	onDiskCacheDir        string
+	preloadDir            string
+	sharedCache           bool
+	inMemoryMaxEntries    int
+}
+
+// crlCacheOwners holds, per cache settings, the validator owning the CRLs cached and preloaded
+// for the configs with these settings and cleaning up their cache, so that the connections
+// with the same settings share the CRLs and a single cache cleanup.
+var (
+	crlCacheOwners      = make(map[crlCacheSettings]*crlValidator)
+	crlCacheOwnersMutex sync.Mutex
+)
+
+// crlCacheOwner returns the validator owning the CRLs cached and preloaded with the settings.
+func crlCacheOwner(settings crlCacheSettings) (*crlValidator, error) {
+	crlCacheOwnersMutex.Lock()
+	defer crlCacheOwnersMutex.Unlock()
+	if owner, ok := crlCacheOwners[settings]; ok {
+		return owner, nil
+	}
+	newValidator := newCrlValidator
+	if settings.sharedCache {
+		newValidator = newSharedCacheCrlValidator
+	}
+	// the owner does not check any certificate
+	owner := newValidator(CertRevocationCheckDisabled, false, settings.cacheValidityTime,
+		settings.inMemoryCacheDisabled, settings.onDiskCacheDisabled, settings.onDiskCacheDir, nil, nil)
+	// a shared in-memory cache keeps the limit of the last settings it was shared with
+	owner.setMaxEntries(settings.inMemoryMaxEntries)
+	if settings.preloadDir != "" {
+		if err := owner.preloadCrls(settings.preloadDir); err != nil {
+			return nil, err
+		}
+	}
+	if !owner.inMemoryCacheDisabled || !owner.onDiskCacheDisabled {
+		owner.startPeriodicCacheCleanup(defaultCrlCacheCleanupTickRate)
+	}
+	crlCacheOwners[settings] = owner
+	return owner, nil
+}
+
+// crlValidatorFromConfig returns a validator checking the revocation of the certificates presented
+// to the connections of the config. It shares the cached and preloaded CRLs with the validators
+// of the configs with the same cache settings. The CRLs are downloaded through the proxy and dial
+// settings of the config, without OCSP checks, with either CrlTLSConfig or the TLS settings of the config.
+func crlValidatorFromConfig(cfg *Config) (*crlValidator, error) {
+	settings := crlCacheSettings{
+		cacheValidityTime:     cfg.CrlCacheValidityTime,
+		inMemoryCacheDisabled: cfg.CrlInMemoryCacheDisabled,
+		onDiskCacheDisabled:   cfg.CrlOnDiskCacheDisabled,
+		onDiskCacheDir:        cfg.CrlOnDiskCacheDir,
+		preloadDir:            cfg.CrlPreloadDir,
+		sharedCache:           cfg.CrlSharedCache,
+		inMemoryMaxEntries:    cfg.CrlInMemoryCacheMaxEntries,
+	}
+	if settings.cacheValidityTime <= 0 {
+		settings.cacheValidityTime = defaultCrlCacheValidityTime
+	}
+	if settings.inMemoryMaxEntries <= 0 {
+		settings.inMemoryMaxEntries = defaultCrlInMemoryCacheMaxEntries
+	}
+	if settings.onDiskCacheDir == "" {
+		settings.onDiskCacheDir = defaultCrlOnDiskCacheDir()
+	}
+	owner, err := crlCacheOwner(settings)
+	if err != nil {
+		return nil, err
+	}
+	httpClientTimeout := cfg.CrlHTTPClientTimeout
+	if httpClientTimeout <= 0 {
+		httpClientTimeout = defaultCrlHTTPClientTimeout
+	}
+	httpTransport := withProxy(withDialSettings(withTLSSettings(snowflakeNoOcspTransport, cfg), cfg), cfg)
+	httpClient := &http.Client{Timeout: httpClientTimeout, Transport: withCrlTLSConfig(httpTransport, cfg.CrlTLSConfig)}
+	cv := newCrlValidator(cfg.CertRevocationCheckMode, cfg.CrlAllowCertificatesWithoutCrlURL, settings.cacheValidityTime,
+		settings.inMemoryCacheDisabled, settings.onDiskCacheDisabled, settings.onDiskCacheDir, httpClient, cfg.Logger)
+	cv.crlCache = owner.crlCache
+	cv.preloadedCrls = owner.preloadedCrls
+	cv.downloadDisabled = cfg.CrlDownloadDisabled
+	cv.checkAllChains = cfg.CrlCheckAllChains
+	cv.auditSink = cfg.CrlAuditSink
+	if cfg.CrlDownloadRetries > 0 {
+		cv.downloadRetries = cfg.CrlDownloadRetries
+	}
+	if cfg.CrlDownloadRetryBackoff > 0 {
+		cv.downloadRetryBackoff = cfg.CrlDownloadRetryBackoff
+	}
+	if cfg.CrlMinimumSignatureHash != 0 {
+		cv.minimumSignatureHash = cfg.CrlMinimumSignatureHash
+	}
+	return cv, nil
+}
+
+// defaultCrlOnDiskCacheDir returns the crls directory in the cache directory of the user,
+// or in the temporary directory if the user has none.
+func defaultCrlOnDiskCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		logger.Debugf("failed to get the user cache directory, caching CRLs in the temporary directory: %v", err)
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "snowflake", "crls")
+}
+
+// crlTransports caches the transports with a CRL based revocation check.
+var crlTransports = newTransportCache(maxCachedTransports)
+
+// crlTransportSettings are the settings of the config the transports with a CRL based revocation
+// check are cached for, besides the root CAs compared by content.
+type crlTransportSettings struct {
+	values           string
+	crlTLSConfig     *tls.Config
+	structuredLogger *slog.Logger
+}
+
+// withCrlSettings returns a copy of the transport which checks the revocation of the certificates
+// with the CRLs of a validator built from the config.
+func withCrlSettings(rt http.RoundTripper, cfg *Config) (http.RoundTripper, error) {
+	transport, ok := rt.(*http.Transport)
+	if !ok {
+		return rt, nil
+	}
+	cv, err := crlValidatorFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	settings := crlTransportSettings{
+		values: fmt.Sprintf("%v/%v/%v/%v/%v/%q/%v/%q/%v/%v/%v/%v/%v/%v/%v/%q/%v/%q/%v/%v",
+			cfg.CertRevocationCheckMode, cfg.CrlAllowCertificatesWithoutCrlURL, cfg.CrlCacheValidityTime,
+			cfg.CrlInMemoryCacheDisabled, cfg.CrlOnDiskCacheDisabled, cfg.CrlOnDiskCacheDir, cfg.CrlHTTPClientTimeout,
+			cfg.CrlPreloadDir, cfg.CrlDownloadDisabled, cfg.CrlSharedCache, cfg.CrlMinimumSignatureHash, cfg.CrlInMemoryCacheMaxEntries,
+			cfg.CrlCheckAllChains, cfg.CrlDownloadRetries, cfg.CrlDownloadRetryBackoff,
+			cfg.Proxy, cfg.DialTimeout, cfg.DialNetwork, cfg.TLSMinVersion, cfg.TLSCipherSuites),
+		crlTLSConfig:     cfg.CrlTLSConfig,
+		structuredLogger: cfg.Logger,
+	}
+	create := func() *http.Transport {
+		custom := transport.Clone()
+		if custom.TLSClientConfig == nil {
+			custom.TLSClientConfig = &tls.Config{}
+		}
+		custom.TLSClientConfig.VerifyPeerCertificate = cv.verifyPeerCertificates
+		return custom
+	}
+	if cfg.CrlAuditSink != nil {
+		// the sinks cannot be compared, so the transports auditing the checks are not shared
+		return create(), nil
+	}
+	return crlTransports.getOrCreate(transport, settings, cfg.RootCAs, create), nil
+}
+
 // CertRevocationCheckMode defines the modes for certificate revocation checks.
 type CertRevocationCheckMode int
 
@@ -78,16 +388,77 @@ const (
 	CertRevocationCheckEnabled
 )
 
+var certRevocationCheckModeNames = map[CertRevocationCheckMode]string{
+	CertRevocationCheckDisabled: "disabled",
+	CertRevocationCheckAdvisory: "advisory",
+	CertRevocationCheckEnabled:  "enabled",
+}
+
+// crlAdvisoryAcceptances counts the certificate chains accepted in advisory mode despite CRL errors.
+var crlAdvisoryAcceptances atomic.Int64
+
+// CrlStatistics describes the certificate revocation checks of every connection since the process started.
+type CrlStatistics struct {
+	// AdvisoryAcceptances is the number of TLS handshakes accepted in advisory mode although the revocation
+	// status of the certificates could not be checked.
+	AdvisoryAcceptances int64
+}
+
+// CrlStats returns the statistics of the certificate revocation checks.
+func CrlStats() CrlStatistics {
+	return CrlStatistics{
+		AdvisoryAcceptances: crlAdvisoryAcceptances.Load(),
+	}
+}
+
 func (m CertRevocationCheckMode) String() string {
-	switch m {
-	case CertRevocationCheckDisabled:
-		return "CERT_REVOCATION_CHECK_DISABLED"
-	case CertRevocationCheckAdvisory:
-		return "CERT_REVOCATION_CHECK_ADVISORY"
-	case CertRevocationCheckEnabled:
-		return "CERT_REVOCATION_CHECK_ENABLED"
-	default:
-		return fmt.Sprintf("unknown CertRevocationCheckMode: %d", m)
+	if name, ok := certRevocationCheckModeNames[m]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", int(m))
+}
+
+// MarshalJSON encodes the mode as its name, e.g. "advisory".
+func (m CertRevocationCheckMode) MarshalJSON() ([]byte, error) {
+	if _, ok := certRevocationCheckModeNames[m]; !ok {
+		return nil, fmt.Errorf("cannot marshal certificate revocation check mode %v", m)
+	}
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON decodes the mode from its name, case-insensitively, or from its numeric value.
+func (m *CertRevocationCheckMode) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		var value int
+		if err = json.Unmarshal(data, &value); err != nil {
+			return fmt.Errorf("certificate revocation check mode must be a string or a number, got %s", data)
+		}
+		if _, ok := certRevocationCheckModeNames[CertRevocationCheckMode(value)]; !ok {
+			return fmt.Errorf("unknown certificate revocation check mode: %d", value)
+		}
+		*m = CertRevocationCheckMode(value)
+		return nil
+	}
+	mode, err := parseCertRevocationCheckMode(name)
+	if err != nil {
+		return err
+	}
+	*m = mode
+	return nil
+}
+
+// parseCertRevocationCheckMode returns the mode of the name, matched case-insensitively.
+func parseCertRevocationCheckMode(name string) (CertRevocationCheckMode, error) {
+	for mode, modeName := range certRevocationCheckModeNames {
+		if strings.EqualFold(name, modeName) {
+			return mode, nil
+		}
+	}
+	return CertRevocationCheckDisabled, &SnowflakeError{
+		Number:      ErrCodeInvalidCertRevocationCheckMode,
+		Message:     errMsgInvalidCertRevocationCheckMode,
+		MessageArgs: []interface{}{name},
 	}
 }
 
@@ -107,16 +478,60 @@ const (
 	certError
 )
 
+// CrlVerdict is the outcome of the revocation check of a certificate chain, or of a certificate against a CRL.
+type CrlVerdict int
+
+// The verdicts follow the order of crlValidationResult and certValidationResult, which are converted to them.
+const (
+	// CrlVerdictRevoked means that a certificate is revoked.
+	CrlVerdictRevoked CrlVerdict = iota
+	// CrlVerdictUnrevoked means that no certificate is revoked.
+	CrlVerdictUnrevoked
+	// CrlVerdictError means that the revocation could not be checked.
+	CrlVerdictError
+)
+
+var crlVerdictNames = map[CrlVerdict]string{
+	CrlVerdictRevoked:   "revoked",
+	CrlVerdictUnrevoked: "unrevoked",
+	CrlVerdictError:     "error",
+}
+
+func (v CrlVerdict) String() string {
+	if name, ok := crlVerdictNames[v]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", int(v))
+}
+
+// CrlChainAudit records how the revocation of a certificate chain was checked, for audits.
+// Config.CrlAuditSink receives one per checked chain.
+type CrlChainAudit struct {
+	Subjects []string      // of the certificates, leaf first
+	Crls     []CrlURLAudit // in the order they were consulted
+	Verdict  CrlVerdict
+}
+
+// CrlURLAudit records the check of a certificate against the CRL of a distribution point.
+type CrlURLAudit struct {
+	URL      string
+	CacheHit bool // the CRL was cached or preloaded rather than downloaded
+	Verdict  CrlVerdict
+}
+
 // TODO in following commits:
-// - clean up in memory cache and on-disk cache
 // - telemetry
-// - initialize into the main flow
 func (cv *crlValidator) verifyPeerCertificates(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+	// the handshake does not pass its context to the verification
+	return cv.verifyChains(context.Background(), verifiedChains)
+}
+
+func (cv *crlValidator) verifyChains(ctx context.Context, verifiedChains [][]*x509.Certificate) error {
 	if cv.certRevocationCheckMode == CertRevocationCheckDisabled {
 		logger.Debug("certificate revocation check is disabled, skipping CRL validation")
 		return nil
 	}
-	crlValidationResults := cv.validateChains(verifiedChains)
+	crlValidationResults, crlErrors := cv.validateChains(ctx, verifiedChains)
 
 	allRevoked := true
 	for _, result := range crlValidationResults {
@@ -135,19 +550,44 @@ func (cv *crlValidator) verifyPeerCertificates(rawCerts [][]byte, verifiedChains
 
 	logger.Warn("some certificate chains didn't pass or driver wasn't able to peform the checks")
 	if cv.certRevocationCheckMode == CertRevocationCheckAdvisory {
-		logger.Warn("certificate revocation check is set to CERT_REVOCATION_CHECK_ADVISORY, so assuming that certificates are not revoked")
+		crlAdvisoryAcceptances.Add(1)
+		for _, err := range crlErrors {
+			logger.Warnf("certificate revocation check mode is %v, so assuming that certificates are not revoked despite: %v", cv.certRevocationCheckMode, err)
+			if cv.structuredLogger != nil {
+				cv.structuredLogger.Warn("certificate chain accepted despite CRL error", slog.String(slogErrorKey, err.Error()))
+			}
+		}
 		return nil
 	}
 	return fmt.Errorf("certificate revocation check failed")
 }
 
-func (cv *crlValidator) validateChains(chains [][]*x509.Certificate) []crlValidationResult {
+// validateChains returns the result of each chain and the errors which prevented validating them.
+func (cv *crlValidator) validateChains(ctx context.Context, chains [][]*x509.Certificate) ([]crlValidationResult, []error) {
 	crlValidationResults := make([]crlValidationResult, len(chains))
+	var crlErrors []error
+	var audits []CrlChainAudit
+	if cv.auditSink != nil {
+		defer func() {
+			for i := range audits {
+				audits[i].Verdict = CrlVerdict(crlValidationResults[i])
+			}
+			cv.auditSink(audits)
+		}()
+	}
 	for i, chain := range chains {
 		crlValidationResults[i] = crlUnrevoked
+		var audit *CrlChainAudit
+		if cv.auditSink != nil {
+			audits = append(audits, CrlChainAudit{})
+			audit = &audits[len(audits)-1]
+		}
 		chainStr := ""
 		for _, cert := range chain {
 			chainStr += fmt.Sprintf("%v -> ", cert.Subject)
+			if audit != nil {
+				audit.Subjects = append(audit.Subjects, cert.Subject.String())
+			}
 		}
 		logger.Debugf("validating certificate chain %d: %s", i, chainStr)
 		for j, cert := range chain {
@@ -168,10 +608,11 @@ func (cv *crlValidator) validateChains(chains [][]*x509.Certificate) []crlValida
 				}
 				logger.Warnf("certificate %v has no CRL distribution points, skipping CRL validation, but marking as error", cert.Subject)
 				crlValidationResults[i] = crlError
+				crlErrors = append(crlErrors, fmt.Errorf("certificate %v has no CRL distribution points", cert.Subject))
 				continue
 			}
 
-			certStatus := cv.validateCertificate(cert, chain[j+1])
+			certStatus, err := cv.validateCertificate(ctx, cert, chain[j+1], chain, audit)
 			if certStatus == certRevoked {
 				crlValidationResults[i] = crlRevoked
 				break
@@ -179,44 +620,66 @@ func (cv *crlValidator) validateChains(chains [][]*x509.Certificate) []crlValida
 
 			if certStatus == certError {
 				crlValidationResults[i] = crlError
+				crlErrors = append(crlErrors, err)
 				continue
 			}
 		}
 
-		if crlValidationResults[i] == crlUnrevoked {
+		if crlValidationResults[i] == crlUnrevoked && !cv.checkAllChains {
 			logger.Debugf("certificate chain %d is unrevoked, skipping remaining chains", i)
 			break
 		}
 	}
 
-	return crlValidationResults
+	return crlValidationResults, crlErrors
 }
 
-func (cv *crlValidator) validateCertificate(cert *x509.Certificate, parent *x509.Certificate) certValidationResult {
+func (cv *crlValidator) validateCertificate(ctx context.Context, cert *x509.Certificate, parent *x509.Certificate, chain []*x509.Certificate, audit *CrlChainAudit) (certValidationResult, error) {
 	for _, crlURL := range cert.CRLDistributionPoints {
-		result := cv.validateCrlAgainstCrlURL(cert, crlURL, parent)
+		result, err := cv.validateCrlAgainstCrlURL(ctx, cert, crlURL, parent, chain, audit)
 		if result == certRevoked || result == certError {
-			return result
+			return result, err
 		}
 	}
-	return certUnrevoked
+	return certUnrevoked, nil
 }
 
-func (cv *crlValidator) validateCrlAgainstCrlURL(cert *x509.Certificate, crlURL string, parent *x509.Certificate) certValidationResult {
+// validateCrlAgainstCrlURL checks the certificate against the CRL of the URL. The error tells
+// why the check failed for certError. The check is recorded in the audit if not nil.
+func (cv *crlValidator) validateCrlAgainstCrlURL(ctx context.Context, cert *x509.Certificate, crlURL string, parent *x509.Certificate, chain []*x509.Certificate, audit *CrlChainAudit) (result certValidationResult, err error) {
 	now := time.Now()
+	cacheHit := false
+	if audit != nil {
+		defer func() {
+			audit.Crls = append(audit.Crls, CrlURLAudit{URL: crlURL, CacheHit: cacheHit, Verdict: CrlVerdict(result)})
+		}()
+	}
 
 	mu := cv.getOrCreateMutex(crlURL)
 	mu.Lock()
 	defer mu.Unlock()
 
 	crl, downloadTime := cv.getFromCache(crlURL)
+	if crl == nil {
+		crl, downloadTime = cv.getPreloaded(cert)
+	}
 	needsFreshCrl := crl == nil || crl.NextUpdate.Before(now) || downloadTime.Add(cv.cacheValidityTime).Before(now)
 	shouldUpdateCrl := false
 
+	if needsFreshCrl && cv.downloadDisabled {
+		if crl == nil || crl.NextUpdate.Before(now) {
+			logger.Warnf("CRL for %v is not available or outdated and downloading CRLs is disabled", crlURL)
+			return certError, fmt.Errorf("CRL for %v is not available or outdated and downloading CRLs is disabled", crlURL)
+		}
+		logger.Debugf("downloading CRLs is disabled, using the available CRL for %v", crlURL)
+		needsFreshCrl = false
+	}
+	cacheHit = !needsFreshCrl
+
 	if needsFreshCrl {
-		newCrl, newDownloadTime, err := cv.downloadCrl(crlURL)
-		if err != nil {
-			logger.Warnf("failed to download CRL from %v: %v", crlURL, err)
+		newCrl, newDownloadTime, downloadErr := cv.downloadCrl(ctx, crlURL)
+		if downloadErr != nil {
+			logger.Warnf("failed to download CRL from %v: %v", crlURL, downloadErr)
 		}
 		shouldUpdateCrl = newCrl != nil && (crl == nil || newCrl.ThisUpdate.After(crl.ThisUpdate))
 		if shouldUpdateCrl {
@@ -228,14 +691,21 @@ func (cv *crlValidator) validateCrlAgainstCrlURL(cert *x509.Certificate, crlURL
 				logger.Debugf("CRL for %v is up-to-date, using cached version", crlURL)
 			} else {
 				logger.Warnf("CRL for %v is not available or outdated", crlURL)
-				return certError
+				if downloadErr != nil {
+					return certError, fmt.Errorf("CRL for %v is not available or outdated: %w", crlURL, downloadErr)
+				}
+				return certError, fmt.Errorf("CRL for %v is not available or outdated", crlURL)
 			}
 		}
 	}
 
 	logger.Debugf("CRL has %v entries, next update at %v", len(crl.RevokedCertificateEntries), crl.NextUpdate)
-	if err := cv.validateCrl(crl, parent, crlURL); err != nil {
-		return certError
+	indirect, err := isIndirectCrl(crl)
+	if err != nil {
+		return certError, fmt.Errorf("CRL for %v is not valid: %w", crlURL, err)
+	}
+	if err := cv.validateCrl(crl, crlSigner(crl, parent, chain, indirect), crlURL); err != nil {
+		return certError, fmt.Errorf("CRL for %v is not valid: %w", crlURL, err)
 	}
 
 	if shouldUpdateCrl {
@@ -243,23 +713,134 @@ func (cv *crlValidator) validateCrlAgainstCrlURL(cert *x509.Certificate, crlURL
 		cv.updateCache(crlURL, crl, downloadTime)
 	}
 
+	certificateIssuer := crl.RawIssuer
 	for _, rce := range crl.RevokedCertificateEntries {
+		if indirect {
+			if entryIssuer := entryCertificateIssuer(rce); entryIssuer != nil {
+				certificateIssuer = entryIssuer
+			}
+			if !bytes.Equal(certificateIssuer, cert.RawIssuer) {
+				continue
+			}
+		}
 		if cert.SerialNumber.Cmp(rce.SerialNumber) == 0 {
 			logger.Warnf("certificate for %v (serial number %v) has been revoked at %v, reason: %v", cert.Subject, rce.SerialNumber, rce.RevocationTime, rce.ReasonCode)
-			return certRevoked
+			return certRevoked, nil
 		}
 	}
 
-	return certUnrevoked
+	return certUnrevoked, nil
 }
 
-func (cv *crlValidator) validateCrl(crl *x509.RevocationList, parent *x509.Certificate, crlURL string) error {
-	if crl.Issuer.String() != parent.Subject.String() {
-		err := fmt.Errorf("CRL issuer %v does not match parent certificate subject %v for %v", crl.Issuer, parent.Subject, crlURL)
+// preloadCrls loads the CRLs of the directory, in DER or PEM format, for the air-gapped environments
+// which cannot reach the CRL distribution points. They are used for the certificates of their issuer
+// when no CRL is cached for the distribution point, until their NextUpdate. With downloadDisabled,
+// they are the only source of CRLs beside the cache. It must be called before the validator is used.
+func (cv *crlValidator) preloadCrls(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read the CRL preload directory: %w", err)
+	}
+	now := time.Now()
+	if cv.preloadedCrls == nil {
+		cv.preloadedCrls = make(map[string]*crlInMemoryCacheValueType)
+	}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		crlBytes, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read the preloaded CRL %v: %w", path, err)
+		}
+		crl, err := parseCrl(crlBytes)
+		if err != nil {
+			return fmt.Errorf("failed to parse the preloaded CRL %v: %w", path, err)
+		}
+		issuer := string(crl.RawIssuer)
+		if existing, ok := cv.preloadedCrls[issuer]; ok && !crl.ThisUpdate.After(existing.crl.ThisUpdate) {
+			continue
+		}
+		logger.Debugf("preloaded CRL of %v from %v, next update at %v", crl.Issuer, path, crl.NextUpdate)
+		cv.preloadedCrls[issuer] = &crlInMemoryCacheValueType{crl: crl, downloadTime: &now}
+	}
+	return nil
+}
+
+func (cv *crlValidator) getPreloaded(cert *x509.Certificate) (*x509.RevocationList, *time.Time) {
+	preloaded, ok := cv.preloadedCrls[string(cert.RawIssuer)]
+	if !ok {
+		return nil, nil
+	}
+	logger.Debugf("using the preloaded CRL of %v", cert.Issuer)
+	return preloaded.crl, preloaded.downloadTime
+}
+
+// isIndirectCrl tells whether the IDP extension marks the CRL as indirect, i.e. signed by a CRL
+// issuer other than the issuer of the certificates and listing their issuer in its entries.
+func isIndirectCrl(crl *x509.RevocationList) (bool, error) {
+	for _, ext := range append(crl.Extensions, crl.ExtraExtensions...) {
+		if ext.Id.Equal(idpOID) {
+			var idp issuingDistributionPoint
+			if _, err := asn1.Unmarshal(ext.Value, &idp); err != nil {
+				return false, fmt.Errorf("failed to unmarshal IDP extension: %w", err)
+			}
+			return idp.IndirectCRL, nil
+		}
+	}
+	return false, nil
+}
+
+// crlSigner returns the certificate expected to sign the CRL: the parent for direct CRLs, and the
+// certificate of the chain named as the CRL issuer for indirect ones, or the parent if none is found.
+func crlSigner(crl *x509.RevocationList, parent *x509.Certificate, chain []*x509.Certificate, indirect bool) *x509.Certificate {
+	if !indirect {
+		return parent
+	}
+	for _, cert := range chain {
+		if bytes.Equal(cert.RawSubject, crl.RawIssuer) {
+			logger.Debugf("indirect CRL is issued by %v", cert.Subject)
+			return cert
+		}
+	}
+	logger.Warnf("issuer %v of the indirect CRL is not in the certificate chain", crl.Issuer)
+	return parent
+}
+
+// entryCertificateIssuer returns the DER encoded issuer named by the certificate issuer extension
+// of the entry, or nil when the entry has none.
+func entryCertificateIssuer(rce x509.RevocationListEntry) []byte {
+	for _, ext := range rce.Extensions {
+		if !ext.Id.Equal(certificateIssuerOID) {
+			continue
+		}
+		var names []asn1.RawValue
+		if _, err := asn1.Unmarshal(ext.Value, &names); err != nil {
+			logger.Warnf("failed to unmarshal the certificate issuer of CRL entry %v: %v", rce.SerialNumber, err)
+			return nil
+		}
+		for _, name := range names {
+			if name.Class == asn1.ClassContextSpecific && name.Tag == directoryNameTag {
+				return name.Bytes
+			}
+		}
+	}
+	return nil
+}
+
+// validateCrl checks that the CRL is issued and signed by the issuer certificate for the URL.
+func (cv *crlValidator) validateCrl(crl *x509.RevocationList, issuer *x509.Certificate, crlURL string) error {
+	if crl.Issuer.String() != issuer.Subject.String() {
+		err := fmt.Errorf("CRL issuer %v does not match the subject of its issuer certificate %v for %v", crl.Issuer, issuer.Subject, crlURL)
 		logger.Warn(err)
 		return err
 	}
-	if err := crl.CheckSignatureFrom(parent); err != nil {
+	if err := cv.checkSignatureStrength(crl); err != nil {
+		logger.Warnf("CRL signature algorithm check failed for %v: %v", crlURL, err)
+		return err
+	}
+	if err := crl.CheckSignatureFrom(issuer); err != nil {
 		logger.Warnf("CRL signature verification failed for %v: %v", crlURL, err)
 		return err
 	}
@@ -270,12 +851,69 @@ func (cv *crlValidator) validateCrl(crl *x509.RevocationList, parent *x509.Certi
 	return nil
 }
 
+// crlSignatureHashes maps the signature algorithms to the hash they sign. Ed25519 signs the
+// whole message with SHA-512.
+var crlSignatureHashes = map[x509.SignatureAlgorithm]crypto.Hash{
+	x509.MD5WithRSA:       crypto.MD5,
+	x509.SHA1WithRSA:      crypto.SHA1,
+	x509.DSAWithSHA1:      crypto.SHA1,
+	x509.ECDSAWithSHA1:    crypto.SHA1,
+	x509.SHA256WithRSA:    crypto.SHA256,
+	x509.DSAWithSHA256:    crypto.SHA256,
+	x509.ECDSAWithSHA256:  crypto.SHA256,
+	x509.SHA256WithRSAPSS: crypto.SHA256,
+	x509.SHA384WithRSA:    crypto.SHA384,
+	x509.ECDSAWithSHA384:  crypto.SHA384,
+	x509.SHA384WithRSAPSS: crypto.SHA384,
+	x509.SHA512WithRSA:    crypto.SHA512,
+	x509.ECDSAWithSHA512:  crypto.SHA512,
+	x509.SHA512WithRSAPSS: crypto.SHA512,
+	x509.PureEd25519:      crypto.SHA512,
+}
+
+// isCrlSignatureHash tells whether the hash is signed by one of the signature algorithms of crlSignatureHashes.
+func isCrlSignatureHash(hash crypto.Hash) bool {
+	for _, signatureHash := range crlSignatureHashes {
+		if signatureHash == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCrlSignatureHash returns the hash of the name, e.g. SHA-384, matched case-insensitively
+// and with or without the dash.
+func parseCrlSignatureHash(name string) (crypto.Hash, error) {
+	for _, hash := range crlSignatureHashes {
+		if strings.EqualFold(name, hash.String()) || strings.EqualFold(name, strings.ReplaceAll(hash.String(), "-", "")) {
+			return hash, nil
+		}
+	}
+	return 0, &SnowflakeError{
+		Number:      ErrCodeInvalidCrlSetting,
+		Message:     errMsgInvalidCrlMinimumSignatureHash,
+		MessageArgs: []interface{}{name},
+	}
+}
+
+// checkSignatureStrength rejects the CRLs whose signature algorithm uses a weaker hash than minimumSignatureHash.
+func (cv *crlValidator) checkSignatureStrength(crl *x509.RevocationList) error {
+	if cv.minimumSignatureHash == 0 {
+		return nil
+	}
+	hash, ok := crlSignatureHashes[crl.SignatureAlgorithm]
+	if !ok || hash.Size() < cv.minimumSignatureHash.Size() {
+		return fmt.Errorf("CRL signature algorithm %v is weaker than the minimum %v", crl.SignatureAlgorithm, cv.minimumSignatureHash)
+	}
+	return nil
+}
+
 func (cv *crlValidator) getFromCache(crlURL string) (*x509.RevocationList, *time.Time) {
 	if cv.inMemoryCacheDisabled {
 		logger.Debugf("in-memory cache is disabled")
 	} else {
 		cv.inMemoryCacheMutex.Lock()
-		cacheValue, exists := cv.inMemoryCache[crlURL]
+		cacheValue, exists := cv.getLocked(crlURL)
 		cv.inMemoryCacheMutex.Unlock()
 		if exists {
 			logger.Debugf("found CRL in cache for %v", crlURL)
@@ -317,12 +955,12 @@ func (cv *crlValidator) getFromCache(crlURL string) (*x509.RevocationList, *time
 	if !cv.inMemoryCacheDisabled {
 		// promote CRL to in-memory cache
 		cv.inMemoryCacheMutex.Lock()
-		cv.inMemoryCache[crlURL] = &crlInMemoryCacheValueType{
+		cv.storeLocked(crlURL, &crlInMemoryCacheValueType{
 			crl: crl,
 			// modTime is not the exact time the CRL was downloaded, but rather the last modification time of the file
 			// still, it is good enough for our purposes
 			downloadTime: &modTime,
-		}
+		})
 		cv.inMemoryCacheMutex.Unlock()
 	}
 	return crl, &modTime
@@ -333,10 +971,10 @@ func (cv *crlValidator) updateCache(crlURL string, crl *x509.RevocationList, dow
 		logger.Debugf("in-memory cache is disabled, not updating")
 	} else {
 		cv.inMemoryCacheMutex.Lock()
-		cv.inMemoryCache[crlURL] = &crlInMemoryCacheValueType{
+		cv.storeLocked(crlURL, &crlInMemoryCacheValueType{
 			crl:          crl,
 			downloadTime: downloadTime,
-		}
+		})
 		cv.inMemoryCacheMutex.Unlock()
 	}
 	if cv.onDiskCacheDisabled {
@@ -353,27 +991,66 @@ func (cv *crlValidator) updateCache(crlURL string, crl *x509.RevocationList, dow
 	}
 }
 
-func (cv *crlValidator) downloadCrl(crlURL string) (*x509.RevocationList, *time.Time, error) {
+// downloadCrl downloads the CRL, retrying the network errors and the server errors
+// up to downloadRetries times unless the context is done.
+func (cv *crlValidator) downloadCrl(ctx context.Context, crlURL string) (*x509.RevocationList, *time.Time, error) {
+	backoff := cv.downloadRetryBackoff
+	for attempt := 0; ; attempt++ {
+		crl, downloadTime, retryable, err := cv.downloadCrlOnce(ctx, crlURL)
+		if err == nil || !retryable || attempt >= cv.downloadRetries {
+			return crl, downloadTime, err
+		}
+		logger.Debugf("retrying the download of CRL %v in %v after: %v", crlURL, backoff, err)
+		select {
+		case <-ctx.Done():
+			return nil, nil, fmt.Errorf("%w, retry interrupted: %w", err, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (cv *crlValidator) downloadCrlOnce(ctx context.Context, crlURL string) (crl *x509.RevocationList, downloadTime *time.Time, retryable bool, err error) {
 	logger.Debugf("downloading CRL from %v", crlURL)
 	now := time.Now()
-	resp, err := cv.httpClient.Get(crlURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, crlURL, nil)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	resp, err := cv.httpClient.Do(req)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, ctx.Err() == nil, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode >= 400 {
-		return nil, nil, fmt.Errorf("failed to download CRL from %v, status code: %v", crlURL, resp.StatusCode)
+		retryable = resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return nil, nil, retryable, fmt.Errorf("failed to download CRL from %v, status code: %v", crlURL, resp.StatusCode)
 	}
 	crlBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, ctx.Err() == nil, err
 	}
 	logger.Debugf("downloaded %v bytes for CRL %v", len(crlBytes), crlURL)
-	crl, err := x509.ParseRevocationList(crlBytes)
+	if cv.structuredLogger != nil {
+		cv.structuredLogger.Info("CRL downloaded",
+			slog.String("url", crlURL), slog.Int("bytes", len(crlBytes)), slog.Duration(slogDurationKey, time.Since(now)))
+	}
+	crl, err = parseCrl(crlBytes)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, false, err
+	}
+	return crl, &now, false, nil
+}
+
+// parseCrl parses a DER CRL, or a PEM one as some distribution points serve them.
+func parseCrl(crlBytes []byte) (*x509.RevocationList, error) {
+	if block, _ := pem.Decode(crlBytes); block != nil {
+		if block.Type != "X509 CRL" {
+			return nil, fmt.Errorf("unexpected PEM block type %v, expected X509 CRL", block.Type)
+		}
+		crlBytes = block.Bytes
 	}
-	return crl, &now, err
+	return x509.ParseRevocationList(crlBytes)
 }
 
 func (cv *crlValidator) crlURLToPath(crlURL string) string {
@@ -468,7 +1145,7 @@ func (cv *crlValidator) cleanupInMemoryCache() {
 		evicted := v.downloadTime.Add(cv.cacheValidityTime).Before(now)
 		logger.Debugf("testing CRL for %v (nextUpdate=%v, downloadTime=%v) from in-memory cache (expired: %v, evicted: %v)", k, v.crl.NextUpdate, v.downloadTime, expired, evicted)
 		if expired || evicted {
-			delete(cv.inMemoryCache, k)
+			cv.deleteLocked(k)
 		}
 	}
 	cv.inMemoryCacheMutex.Unlock()
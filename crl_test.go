@@ -1,19 +1,34 @@
 package gosnowflake
 
 import (
+	"bytes"
 	"cmp"
 	"context"
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/asn1"
 	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"math/big"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -59,7 +74,43 @@ func newTestCrlValidator(t *testing.T, checkMode CertRevocationCheckMode, args .
 		}
 	}
 	cacheDir := t.TempDir()
-	return newCrlValidator(checkMode, allowCertificatesWithoutCrlURL, cacheValidityTime, inMemoryCacheDisabled, onDiskCacheDisabled, cacheDir, httpClient)
+	return newCrlValidator(checkMode, allowCertificatesWithoutCrlURL, cacheValidityTime, inMemoryCacheDisabled, onDiskCacheDisabled, cacheDir, httpClient, nil)
+}
+
+func TestCertRevocationCheckModeStringAndJSON(t *testing.T) {
+	for _, tc := range []struct {
+		mode CertRevocationCheckMode
+		name string
+	}{
+		{CertRevocationCheckDisabled, "disabled"},
+		{CertRevocationCheckAdvisory, "advisory"},
+		{CertRevocationCheckEnabled, "enabled"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assertEqualE(t, tc.mode.String(), tc.name)
+			assertEqualE(t, fmt.Sprintf("%v", tc.mode), tc.name)
+			data, err := json.Marshal(tc.mode)
+			assertNilF(t, err)
+			assertEqualE(t, string(data), `"`+tc.name+`"`)
+			var decoded CertRevocationCheckMode
+			assertNilF(t, json.Unmarshal(data, &decoded))
+			assertEqualE(t, decoded, tc.mode)
+			assertNilF(t, json.Unmarshal([]byte(`"`+strings.ToUpper(tc.name)+`"`), &decoded))
+			assertEqualE(t, decoded, tc.mode)
+			assertNilF(t, json.Unmarshal([]byte(strconv.Itoa(int(tc.mode))), &decoded))
+			assertEqualE(t, decoded, tc.mode)
+		})
+	}
+
+	t.Run("out of range", func(t *testing.T) {
+		mode := CertRevocationCheckMode(7)
+		assertEqualE(t, mode.String(), "unknown(7)")
+		_, err := json.Marshal(mode)
+		assertNotNilE(t, err)
+		var decoded CertRevocationCheckMode
+		assertNotNilE(t, json.Unmarshal([]byte(`"sometimes"`), &decoded))
+		assertNotNilE(t, json.Unmarshal([]byte(`7`), &decoded))
+	})
 }
 
 func TestCrlCheckModeDisabled_NoHttpCall(t *testing.T) {
@@ -693,6 +744,22 @@ func (m *malformedCrlRoundTripper) RoundTrip(req *http.Request) (*http.Response,
 	return &response, nil
 }
 
+// pemCrlRoundTripper serves the CRL PEM-encoded for every request.
+type pemCrlRoundTripper struct {
+	crl      *x509.RevocationList
+	requests int
+}
+
+func (p *pemCrlRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	p.requests++
+	body := pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: p.crl.Raw})
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
 func createCa(t *testing.T, issuerCert *x509.Certificate, issuerPrivateKey *rsa.PrivateKey, cn string, crlEndpoint string) (*rsa.PrivateKey, *x509.Certificate) {
 	caTemplate := &x509.Certificate{
 		SerialNumber: big.NewInt(1),
@@ -757,6 +824,7 @@ func createCrl(t *testing.T, issuerCert *x509.Certificate, issuerPrivateKey *rsa
 	var extensions []pkix.Extension
 	thisUpdate := time.Now().Add(-time.Hour)
 	nextUpdate := time.Now().Add(time.Hour)
+	signatureAlgorithm := x509.UnknownSignatureAlgorithm // the default of the issuer key
 	for _, arg := range args {
 		switch v := arg.(type) {
 		case revokedCert:
@@ -770,11 +838,14 @@ func createCrl(t *testing.T, issuerCert *x509.Certificate, issuerPrivateKey *rsa
 			thisUpdate = time.Time(v)
 		case nextUpdateType:
 			nextUpdate = time.Time(v)
+		case x509.SignatureAlgorithm:
+			signatureAlgorithm = v
 		default:
 			t.Fatalf("unexpected argument type: %T", arg)
 		}
 	}
 	crlTemplate := &x509.RevocationList{
+		SignatureAlgorithm:        signatureAlgorithm,
 		Number:                    big.NewInt(1),
 		RevokedCertificateEntries: revokedCertEntries,
 		ExtraExtensions:           extensions,
@@ -813,8 +884,11 @@ func createCrlServer(t *testing.T, endpointDefs ...*crlEndpointDef) *http.Server
 		Addr:    fmt.Sprintf(":%v", testCrlServerPort),
 		Handler: mux,
 	}
+	// listen before returning, so the CRLs can be downloaded right away
+	listener, err := net.Listen("tcp", server.Addr)
+	assertNilF(t, err)
 	go func() {
-		err := server.ListenAndServe()
+		err := server.Serve(listener)
 		assertErrIsF(t, err, http.ErrServerClosed)
 	}()
 	return server
@@ -828,3 +902,719 @@ func closeServer(t *testing.T, server *http.Server) {
 	err := server.Shutdown(context.Background())
 	assertNilF(t, err)
 }
+
+func TestCrlCacheWarmedByFirstConnection(t *testing.T) {
+	caPrivateKey, caCert := createCa(t, nil, nil, "root CA", "")
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(1000),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+	}
+	leafPrivateKey, leafCert := createCert(t, leafTemplate, caCert, caPrivateKey, "/rootCrl")
+	crl := createCrl(t, caCert, caPrivateKey)
+
+	var crlDownloads atomic.Int32
+	crlServer := &http.Server{
+		Addr: fmt.Sprintf(":%v", testCrlServerPort),
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			crlDownloads.Add(1)
+			_, err := w.Write(crl.Raw)
+			assertNilE(t, err)
+		}),
+	}
+	listener, err := net.Listen("tcp", crlServer.Addr)
+	assertNilF(t, err)
+	go func() {
+		assertErrIsE(t, crlServer.Serve(listener), http.ErrServerClosed)
+	}()
+	defer closeServer(t, crlServer)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{{
+		Certificate: [][]byte{leafCert.Raw},
+		PrivateKey:  leafPrivateKey,
+	}}}
+	server.StartTLS()
+	defer server.Close()
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	assertNilF(t, err)
+	serverURL := "https://" + net.JoinHostPort("localhost", port)
+
+	// each connection parses its own config, as sql.DB does with a DSN, and logs with its own logger
+	cacheDir := t.TempDir()
+	newConnection := func() *snowflakeConn {
+		rootCAs := x509.NewCertPool()
+		rootCAs.AddCert(caCert)
+		sc, err := buildSnowflakeConn(context.Background(), Config{
+			Account:                 "a",
+			User:                    "u",
+			Password:                "p",
+			RootCAs:                 rootCAs,
+			CertRevocationCheckMode: CertRevocationCheckEnabled,
+			CrlOnDiskCacheDisabled:  true,
+			CrlOnDiskCacheDir:       cacheDir,
+			Logger:                  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		})
+		assertNilF(t, err)
+		return sc
+	}
+	handshake := func(sc *snowflakeConn) {
+		resp, err := sc.rest.Client.Get(serverURL)
+		assertNilF(t, err)
+		assertNilF(t, resp.Body.Close())
+		assertEqualE(t, resp.StatusCode, http.StatusOK)
+		// the next request has to make a new handshake
+		sc.rest.Client.CloseIdleConnections()
+	}
+
+	handshake(newConnection())
+	assertEqualE(t, crlDownloads.Load(), int32(1), "the first connection should download the CRL")
+	crlCacheOwnersMutex.Lock()
+	owners := len(crlCacheOwners)
+	crlCacheOwnersMutex.Unlock()
+	handshake(newConnection())
+	assertEqualE(t, crlDownloads.Load(), int32(1), "later connections should use the cached CRL")
+	crlCacheOwnersMutex.Lock()
+	defer crlCacheOwnersMutex.Unlock()
+	assertEqualE(t, len(crlCacheOwners), owners, "the configs with the same settings should share the cached CRLs and their cleanup")
+}
+
+func TestCrlValidatorsSharingCache(t *testing.T) {
+	caPrivateKey, caCert := createCa(t, nil, nil, "root CA", "/rootCrl")
+	_, leafCert := createLeafCert(t, caCert, caPrivateKey, "/rootCrl")
+	crl := createCrl(t, caCert, caPrivateKey)
+
+	server := createCrlServer(t, newCrlEndpointDef("/rootCrl", crl))
+	defer closeServer(t, server)
+
+	cacheDir := t.TempDir()
+	newValidator := func(shared bool, crt *countingRoundTripper) *crlValidator {
+		// the on-disk cache is disabled to check that the in-memory cache is shared
+		if shared {
+			return newSharedCacheCrlValidator(CertRevocationCheckEnabled, false, 5*time.Minute, false, true, cacheDir, &http.Client{Transport: crt}, nil)
+		}
+		return newCrlValidator(CertRevocationCheckEnabled, false, 5*time.Minute, false, true, cacheDir, &http.Client{Transport: crt}, nil)
+	}
+	chains := [][]*x509.Certificate{{leafCert, caCert}}
+
+	firstCrt := newCountingRoundTripper(snowflakeNoOcspTransport)
+	first := newValidator(true, firstCrt)
+	assertNilF(t, first.verifyPeerCertificates(nil, chains))
+	assertEqualE(t, firstCrt.totalRequests(), 1)
+
+	secondCrt := newCountingRoundTripper(snowflakeNoOcspTransport)
+	second := newValidator(true, secondCrt)
+	assertNilF(t, second.verifyPeerCertificates(nil, chains))
+	assertEqualE(t, secondCrt.totalRequests(), 0, "the CRL downloaded by the first validator should be reused")
+	assertTrueE(t, first.crlCache == second.crlCache)
+
+	unsharedCrt := newCountingRoundTripper(snowflakeNoOcspTransport)
+	unshared := newValidator(false, unsharedCrt)
+	assertNilF(t, unshared.verifyPeerCertificates(nil, chains))
+	assertEqualE(t, unsharedCrt.totalRequests(), 1, "validators not sharing the cache download the CRL")
+
+	other := newSharedCacheCrlValidator(CertRevocationCheckEnabled, false, 5*time.Minute, false, true, t.TempDir(), &http.Client{}, nil)
+	assertFalseE(t, other.crlCache == first.crlCache, "validators with different cache directories should not share the cache")
+}
+
+func TestCrlSharedCacheFromConfig(t *testing.T) {
+	caPrivateKey, caCert := createCa(t, nil, nil, "root CA", "/rootCrl")
+	_, leafCert := createLeafCert(t, caCert, caPrivateKey, "/rootCrl")
+	crl := createCrl(t, caCert, caPrivateKey)
+	server := createCrlServer(t, newCrlEndpointDef("/rootCrl", crl))
+	defer closeServer(t, server)
+
+	crt := newCountingRoundTripper(snowflakeNoOcspTransport)
+	originalNoOcspTransport := snowflakeNoOcspTransport
+	snowflakeNoOcspTransport = crt
+	defer func() {
+		snowflakeNoOcspTransport = originalNoOcspTransport
+	}()
+	chains := [][]*x509.Certificate{{leafCert, caCert}}
+	cacheDir := t.TempDir()
+	// the on-disk cache is disabled to check that the in-memory cache is shared,
+	// the validity times differ so that the configs do not share the CRLs otherwise
+	newValidator := func(shared bool, cacheValidityTime time.Duration) *crlValidator {
+		cv, err := crlValidatorFromConfig(&Config{
+			CertRevocationCheckMode: CertRevocationCheckEnabled,
+			CrlOnDiskCacheDisabled:  true,
+			CrlOnDiskCacheDir:       cacheDir,
+			CrlCacheValidityTime:    cacheValidityTime,
+			CrlSharedCache:          shared,
+		})
+		assertNilF(t, err)
+		return cv
+	}
+
+	first := newValidator(true, time.Hour)
+	assertNilF(t, first.verifyPeerCertificates(nil, chains))
+	assertEqualE(t, crt.totalRequests(), 1)
+	second := newValidator(true, 2*time.Hour)
+	assertTrueE(t, first != second)
+	assertNilF(t, second.verifyPeerCertificates(nil, chains))
+	assertEqualE(t, crt.totalRequests(), 1, "the CRL downloaded for the first connector should be reused")
+
+	unshared := newValidator(false, 3*time.Hour)
+	assertNilF(t, unshared.verifyPeerCertificates(nil, chains))
+	assertEqualE(t, crt.totalRequests(), 2, "connectors not sharing the cache download the CRL")
+}
+
+func TestCrlWithWeakSignatureAlgorithm(t *testing.T) {
+	caPrivateKey, caCert := createCa(t, nil, nil, "root CA", "/rootCrl")
+	_, leafCert := createLeafCert(t, caCert, caPrivateKey, "/rootCrl")
+	crl := createCrl(t, caCert, caPrivateKey, x509.SHA1WithRSA)
+	assertEqualF(t, crl.SignatureAlgorithm, x509.SHA1WithRSA)
+
+	server := createCrlServer(t, newCrlEndpointDef("/rootCrl", crl))
+	defer closeServer(t, server)
+	chains := [][]*x509.Certificate{{leafCert, caCert}}
+
+	t.Run("enabled", func(t *testing.T) {
+		cv := newTestCrlValidator(t, CertRevocationCheckEnabled)
+		err := cv.verifyPeerCertificates(nil, chains)
+		assertNotNilF(t, err)
+		assertEqualE(t, err.Error(), "certificate revocation check failed")
+		_, err = os.Stat(cv.crlURLToPath(fullCrlURL("/rootCrl")))
+		assertTrueE(t, os.IsNotExist(err), "a CRL with a weak signature should not be cached")
+	})
+
+	t.Run("advisory", func(t *testing.T) {
+		cv := newTestCrlValidator(t, CertRevocationCheckAdvisory)
+		assertNilE(t, cv.verifyPeerCertificates(nil, chains))
+	})
+
+	t.Run("minimum lowered", func(t *testing.T) {
+		cv := newTestCrlValidator(t, CertRevocationCheckEnabled)
+		cv.minimumSignatureHash = crypto.SHA1
+		assertNilE(t, cv.verifyPeerCertificates(nil, chains))
+	})
+
+	t.Run("minimum lowered in the config", func(t *testing.T) {
+		cfg, err := ParseDSN("u:p@a?certRevocationCheckMode=enabled&crlOnDiskCacheDisabled=true&crlMinimumSignatureHash=sha1&crlOnDiskCacheDir=" + url.QueryEscape(t.TempDir()))
+		assertNilF(t, err)
+		assertEqualE(t, cfg.CrlMinimumSignatureHash, crypto.SHA1)
+		cv, err := crlValidatorFromConfig(cfg)
+		assertNilF(t, err)
+		assertNilE(t, cv.verifyPeerCertificates(nil, chains))
+	})
+}
+
+func TestCrlPreload(t *testing.T) {
+	caPrivateKey, caCert := createCa(t, nil, nil, "root CA", "/rootCrl")
+	_, leafCert := createLeafCert(t, caCert, caPrivateKey, "/rootCrl")
+	_, revokedLeafCert := createLeafCert(t, caCert, caPrivateKey, "/rootCrl")
+	chains := func(leaf *x509.Certificate) [][]*x509.Certificate {
+		return [][]*x509.Certificate{{leaf, caCert}}
+	}
+	preloadDir := func(crl *x509.RevocationList) string {
+		dir := t.TempDir()
+		assertNilF(t, os.WriteFile(filepath.Join(dir, "root.crl"), crl.Raw, 0600))
+		return dir
+	}
+	// no CRL server is started, every download fails
+	crl := createCrl(t, caCert, caPrivateKey, revokedCert(revokedLeafCert))
+
+	t.Run("used without network call", func(t *testing.T) {
+		crt := newCountingRoundTripper(snowflakeNoOcspTransport)
+		cv := newTestCrlValidator(t, CertRevocationCheckEnabled, &http.Client{Transport: crt})
+		assertNilF(t, cv.preloadCrls(preloadDir(crl)))
+		assertNilE(t, cv.verifyPeerCertificates(nil, chains(leafCert)))
+		assertEqualE(t, cv.verifyPeerCertificates(nil, chains(revokedLeafCert)).Error(), "every verified certificate chain contained revoked certificates")
+		assertEqualE(t, crt.totalRequests(), 0)
+	})
+
+	t.Run("PEM", func(t *testing.T) {
+		dir := t.TempDir()
+		assertNilF(t, os.WriteFile(filepath.Join(dir, "root.pem"), pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: crl.Raw}), 0600))
+		cv := newTestCrlValidator(t, CertRevocationCheckEnabled)
+		cv.downloadDisabled = true
+		assertNilF(t, cv.preloadCrls(dir))
+		assertNilE(t, cv.verifyPeerCertificates(nil, chains(leafCert)))
+	})
+
+	t.Run("stale with download disabled", func(t *testing.T) {
+		staleCrl := createCrl(t, caCert, caPrivateKey, thisUpdateType(time.Now().Add(-2*time.Hour)), nextUpdateType(time.Now().Add(-time.Hour)))
+		crt := newCountingRoundTripper(snowflakeNoOcspTransport)
+		cv := newTestCrlValidator(t, CertRevocationCheckEnabled, &http.Client{Transport: crt})
+		cv.downloadDisabled = true
+		assertNilF(t, cv.preloadCrls(preloadDir(staleCrl)))
+		assertEqualE(t, cv.verifyPeerCertificates(nil, chains(leafCert)).Error(), "certificate revocation check failed")
+		assertEqualE(t, crt.totalRequests(), 0)
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		dir := t.TempDir()
+		assertNilF(t, os.WriteFile(filepath.Join(dir, "root.crl"), []byte("not a CRL"), 0600))
+		cv := newTestCrlValidator(t, CertRevocationCheckEnabled)
+		assertNotNilE(t, cv.preloadCrls(dir))
+	})
+}
+
+func TestCrlPreloadFromConfig(t *testing.T) {
+	caPrivateKey, caCert := createCa(t, nil, nil, "root CA", "/rootCrl")
+	_, leafCert := createLeafCert(t, caCert, caPrivateKey, "/rootCrl")
+	_, revokedLeafCert := createLeafCert(t, caCert, caPrivateKey, "/rootCrl")
+	preloadDir := t.TempDir()
+	crl := createCrl(t, caCert, caPrivateKey, revokedCert(revokedLeafCert))
+	assertNilF(t, os.WriteFile(filepath.Join(preloadDir, "root.crl"), crl.Raw, 0600))
+	// no CRL server is started, the preloaded CRL is the only source
+	cfg := Config{
+		Account:                 "a",
+		User:                    "u",
+		Password:                "p",
+		CertRevocationCheckMode: CertRevocationCheckEnabled,
+		CrlOnDiskCacheDisabled:  true,
+		CrlOnDiskCacheDir:       t.TempDir(),
+		CrlPreloadDir:           preloadDir,
+		CrlDownloadDisabled:     true,
+	}
+
+	sc, err := buildSnowflakeConn(context.Background(), cfg)
+	assertNilF(t, err)
+	transport, ok := sc.rest.Client.Transport.(*http.Transport)
+	assertTrueF(t, ok, fmt.Sprintf("unexpected transport %T", sc.rest.Client.Transport))
+	verify := transport.TLSClientConfig.VerifyPeerCertificate
+	assertNotNilF(t, verify)
+	assertNilE(t, verify(nil, [][]*x509.Certificate{{leafCert, caCert}}))
+	assertEqualE(t, verify(nil, [][]*x509.Certificate{{revokedLeafCert, caCert}}).Error(), "every verified certificate chain contained revoked certificates")
+	assertTrueE(t, getTransport(&cfg) == transport, "the cloud storage should use the same transport")
+
+	cv, err := crlValidatorFromConfig(&cfg)
+	assertNilF(t, err)
+	assertTrueE(t, cv.downloadDisabled)
+	assertEqualE(t, cv.cacheValidityTime, defaultCrlCacheValidityTime)
+	assertEqualE(t, cv.httpClient.Timeout, defaultCrlHTTPClientTimeout)
+	other, err := crlValidatorFromConfig(&cfg)
+	assertNilF(t, err)
+	assertTrueE(t, cv.crlCache == other.crlCache, "connections with the same settings should share the cached CRLs")
+	otherCfg := cfg
+	otherCfg.CrlCacheValidityTime = time.Hour
+	other, err = crlValidatorFromConfig(&otherCfg)
+	assertNilF(t, err)
+	assertFalseE(t, cv.crlCache == other.crlCache, "connections with other cache settings should not share the cached CRLs")
+	assertEqualE(t, len(other.preloadedCrls), 1)
+
+	t.Run("invalid preload directory", func(t *testing.T) {
+		invalid := cfg
+		invalid.CrlPreloadDir = filepath.Join(t.TempDir(), "missing")
+		_, err := buildSnowflakeConn(context.Background(), invalid)
+		assertNotNilE(t, err)
+	})
+
+	t.Run("OCSP checks disabled", func(t *testing.T) {
+		disabled := cfg
+		disabled.DisableOCSPChecks = true
+		sc, err := buildSnowflakeConn(context.Background(), disabled)
+		assertNilF(t, err)
+		assertTrueE(t, sc.rest.Client.Transport == snowflakeNoOcspTransport)
+	})
+}
+
+func TestCrlAdvisoryAcceptance(t *testing.T) {
+	caPrivateKey, caCert := createCa(t, nil, nil, "root CA", "/rootCrl")
+	_, leafCert := createLeafCert(t, caCert, caPrivateKey, "/missingCrl")
+	crl := createCrl(t, caCert, caPrivateKey)
+	server := createCrlServer(t, newCrlEndpointDef("/rootCrl", crl))
+	defer closeServer(t, server)
+
+	logger := GetLogger().(*defaultLogger)
+	initialOutput := logger.inner.Out
+	defer logger.SetOutput(initialOutput)
+	level := logger.GetLogLevel()
+	_ = logger.SetLogLevel("warn")
+	defer func() {
+		_ = logger.SetLogLevel(level)
+	}()
+	var buf bytes.Buffer
+	logger.SetOutput(&buf)
+	var structuredBuf bytes.Buffer
+
+	cv, err := crlValidatorFromConfig(&Config{
+		CertRevocationCheckMode: CertRevocationCheckAdvisory,
+		CrlOnDiskCacheDisabled:  true,
+		CrlOnDiskCacheDir:       t.TempDir(),
+		Logger:                  slog.New(slog.NewJSONHandler(&structuredBuf, nil)),
+	})
+	assertNilF(t, err)
+	initialAcceptances := CrlStats().AdvisoryAcceptances
+	chains := [][]*x509.Certificate{{leafCert, caCert}}
+	assertNilF(t, cv.verifyPeerCertificates(nil, chains))
+	assertEqualE(t, CrlStats().AdvisoryAcceptances, initialAcceptances+1)
+	assertStringContainsE(t, buf.String(), "assuming that certificates are not revoked despite: CRL for "+fullCrlURL("/missingCrl"))
+	assertStringContainsE(t, structuredBuf.String(), "certificate chain accepted despite CRL error")
+	assertStringContainsE(t, structuredBuf.String(), fullCrlURL("/missingCrl"))
+
+	assertNilF(t, cv.verifyPeerCertificates(nil, chains))
+	assertEqualE(t, CrlStats().AdvisoryAcceptances, initialAcceptances+2)
+
+	_, validLeafCert := createLeafCert(t, caCert, caPrivateKey, "/rootCrl")
+	assertNilF(t, cv.verifyPeerCertificates(nil, [][]*x509.Certificate{{validLeafCert, caCert}}))
+	assertEqualE(t, CrlStats().AdvisoryAcceptances, initialAcceptances+2, "verified chains should not be counted")
+	assertStringContainsE(t, structuredBuf.String(), `"msg":"CRL downloaded","url":"`+fullCrlURL("/rootCrl"))
+}
+
+func TestCrlServedAsPem(t *testing.T) {
+	caPrivateKey, caCert := createCa(t, nil, nil, "root CA", "/rootCrl")
+	_, leafCert := createLeafCert(t, caCert, caPrivateKey, "/rootCrl")
+	_, revokedLeafCert := createLeafCert(t, caCert, caPrivateKey, "/rootCrl")
+	crl := createCrl(t, caCert, caPrivateKey, revokedCert(revokedLeafCert))
+
+	rt := &pemCrlRoundTripper{crl: crl}
+	cv := newTestCrlValidator(t, CertRevocationCheckEnabled, &http.Client{Transport: rt})
+	assertNilE(t, cv.verifyPeerCertificates(nil, [][]*x509.Certificate{{leafCert, caCert}}))
+	err := cv.verifyPeerCertificates(nil, [][]*x509.Certificate{{revokedLeafCert, caCert}})
+	assertNotNilF(t, err)
+	assertEqualE(t, err.Error(), "every verified certificate chain contained revoked certificates")
+	assertEqualE(t, rt.requests, 1)
+
+	// the CRL is cached as DER
+	cached, err := os.ReadFile(cv.crlURLToPath(fullCrlURL("/rootCrl")))
+	assertNilF(t, err)
+	assertDeepEqualE(t, cached, crl.Raw)
+
+	_, err = parseCrl(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caCert.Raw}))
+	assertNotNilE(t, err)
+}
+
+func TestCrlInMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	var endpointDefs []*crlEndpointDef
+	var chains [][][]*x509.Certificate
+	for _, endpoint := range []string{"/firstCrl", "/secondCrl", "/thirdCrl"} {
+		caPrivateKey, caCert := createCa(t, nil, nil, "CA "+endpoint, "")
+		_, leafCert := createLeafCert(t, caCert, caPrivateKey, endpoint)
+		endpointDefs = append(endpointDefs, newCrlEndpointDef(endpoint, createCrl(t, caCert, caPrivateKey)))
+		chains = append(chains, [][]*x509.Certificate{{leafCert, caCert}})
+	}
+	server := createCrlServer(t, endpointDefs...)
+	defer closeServer(t, server)
+
+	crt := newCountingRoundTripper(snowflakeNoOcspTransport)
+	cv := newTestCrlValidator(t, CertRevocationCheckEnabled, &http.Client{Transport: crt})
+	cv.setMaxEntries(2)
+	for _, chain := range chains {
+		assertNilF(t, cv.verifyPeerCertificates(nil, chain))
+	}
+	assertEqualE(t, crt.totalRequests(), 3)
+	assertEqualE(t, len(cv.inMemoryCache), 2)
+	assertNilE(t, cv.inMemoryCache[fullCrlURL("/firstCrl")], "the least recently used CRL should be evicted")
+	_, err := os.Stat(cv.crlURLToPath(fullCrlURL("/firstCrl")))
+	assertNilE(t, err, "the evicted CRL should stay on disk")
+
+	// the evicted CRL is promoted from disk, evicting the next least recently used one
+	assertNilF(t, cv.verifyPeerCertificates(nil, chains[0]))
+	assertEqualE(t, crt.totalRequests(), 3)
+	assertEqualE(t, len(cv.inMemoryCache), 2)
+	assertNotNilE(t, cv.inMemoryCache[fullCrlURL("/firstCrl")])
+	assertNilE(t, cv.inMemoryCache[fullCrlURL("/secondCrl")])
+	assertNotNilE(t, cv.inMemoryCache[fullCrlURL("/thirdCrl")])
+	assertEqualE(t, cv.order.Len(), 2)
+}
+
+func TestCrlInMemoryCacheMaxEntriesFromConfig(t *testing.T) {
+	cacheDir := t.TempDir()
+	cfg, err := ParseDSN("u:p@a?certRevocationCheckMode=enabled&crlInMemoryCacheMaxEntries=2&crlOnDiskCacheDir=" + url.QueryEscape(cacheDir))
+	assertNilF(t, err)
+	cv, err := crlValidatorFromConfig(cfg)
+	assertNilF(t, err)
+	assertEqualE(t, cv.maxEntries, 2)
+
+	cv, err = crlValidatorFromConfig(&Config{CertRevocationCheckMode: CertRevocationCheckEnabled, CrlOnDiskCacheDir: cacheDir})
+	assertNilF(t, err)
+	assertEqualE(t, cv.maxEntries, defaultCrlInMemoryCacheMaxEntries)
+}
+
+func TestIndirectCrl(t *testing.T) {
+	rootKey, rootCert := createCa(t, nil, nil, "root CA", "")
+	intermediateKey, intermediateCert := createCa(t, rootCert, rootKey, "intermediate CA", "/rootCrl")
+	_, leafCert := createLeafCert(t, intermediateCert, intermediateKey, "/indirectCrl")
+	_, revokedLeafCert := createLeafCert(t, intermediateCert, intermediateKey, "/indirectCrl")
+	_, otherCert := createCa(t, nil, nil, "other CA", "")
+	rootCrl := createCrl(t, rootCert, rootKey)
+
+	generalNames := func(issuer *x509.Certificate) []byte {
+		value, err := asn1.Marshal([]asn1.RawValue{{Class: asn1.ClassContextSpecific, Tag: 4, IsCompound: true, Bytes: issuer.RawSubject}})
+		assertNilF(t, err)
+		return value
+	}
+	createIndirectCrl := func(t *testing.T, indirect bool) *x509.RevocationList {
+		idp, err := asn1.Marshal(issuingDistributionPoint{
+			DistributionPoint: distributionPointName{FullName: []asn1.RawValue{{Class: asn1.ClassContextSpecific, Tag: 6, Bytes: []byte(fullCrlURL("/indirectCrl"))}}},
+			IndirectCRL:       indirect,
+		})
+		assertNilF(t, err)
+		template := &x509.RevocationList{
+			Number: big.NewInt(1),
+			RevokedCertificateEntries: []x509.RevocationListEntry{
+				{
+					// a certificate of another CA with the serial number of the valid leaf
+					SerialNumber:    leafCert.SerialNumber,
+					RevocationTime:  time.Now().Add(-time.Hour),
+					ExtraExtensions: []pkix.Extension{{Id: certificateIssuerOID, Critical: true, Value: generalNames(otherCert)}},
+				},
+				{
+					SerialNumber:    revokedLeafCert.SerialNumber,
+					RevocationTime:  time.Now().Add(-time.Hour),
+					ExtraExtensions: []pkix.Extension{{Id: certificateIssuerOID, Critical: true, Value: generalNames(intermediateCert)}},
+				},
+			},
+			ExtraExtensions: []pkix.Extension{{Id: idpOID, Critical: true, Value: idp}},
+			ThisUpdate:      time.Now().Add(-time.Hour),
+			NextUpdate:      time.Now().Add(time.Hour),
+		}
+		crlBytes, err := x509.CreateRevocationList(rand.Reader, template, rootCert, rootKey)
+		assertNilF(t, err)
+		crl, err := x509.ParseRevocationList(crlBytes)
+		assertNilF(t, err)
+		return crl
+	}
+	chain := func(leaf *x509.Certificate) [][]*x509.Certificate {
+		return [][]*x509.Certificate{{leaf, intermediateCert, rootCert}}
+	}
+
+	t.Run("entries are matched by their certificate issuer", func(t *testing.T) {
+		server := createCrlServer(t, newCrlEndpointDef("/indirectCrl", createIndirectCrl(t, true)), newCrlEndpointDef("/rootCrl", rootCrl))
+		defer closeServer(t, server)
+		cv := newTestCrlValidator(t, CertRevocationCheckEnabled)
+		err := cv.verifyPeerCertificates(nil, chain(revokedLeafCert))
+		assertNotNilF(t, err)
+		assertEqualE(t, err.Error(), "every verified certificate chain contained revoked certificates")
+		assertNilE(t, cv.verifyPeerCertificates(nil, chain(leafCert)), "the entry of the other CA should not revoke the leaf")
+	})
+
+	t.Run("rejected without the indirect flag", func(t *testing.T) {
+		server := createCrlServer(t, newCrlEndpointDef("/indirectCrl", createIndirectCrl(t, false)), newCrlEndpointDef("/rootCrl", rootCrl))
+		defer closeServer(t, server)
+		cv := newTestCrlValidator(t, CertRevocationCheckEnabled)
+		err := cv.verifyPeerCertificates(nil, chain(leafCert))
+		assertNotNilF(t, err)
+		assertEqualE(t, err.Error(), "certificate revocation check failed")
+	})
+}
+
+func TestCrlCheckAllChains(t *testing.T) {
+	firstKey, firstCaCert := createCa(t, nil, nil, "first CA", "")
+	secondKey, secondCaCert := createCa(t, nil, nil, "second CA", "")
+	_, firstLeafCert := createLeafCert(t, firstCaCert, firstKey, "/firstCrl")
+	_, secondLeafCert := createLeafCert(t, secondCaCert, secondKey, "/secondCrl")
+	server := createCrlServer(t,
+		newCrlEndpointDef("/firstCrl", createCrl(t, firstCaCert, firstKey)),
+		newCrlEndpointDef("/secondCrl", createCrl(t, secondCaCert, secondKey)))
+	defer closeServer(t, server)
+	chains := [][]*x509.Certificate{{firstLeafCert, firstCaCert}, {secondLeafCert, secondCaCert}}
+
+	for _, checkAllChains := range []bool{false, true} {
+		t.Run(fmt.Sprintf("checkAllChains=%v", checkAllChains), func(t *testing.T) {
+			crt := newCountingRoundTripper(snowflakeNoOcspTransport)
+			cv, err := crlValidatorFromConfig(&Config{
+				CertRevocationCheckMode: CertRevocationCheckEnabled,
+				CrlOnDiskCacheDir:       t.TempDir(),
+				CrlCheckAllChains:       checkAllChains,
+			})
+			assertNilF(t, err)
+			cv.httpClient = &http.Client{Transport: crt}
+			assertNilF(t, cv.verifyPeerCertificates(nil, chains))
+			results, errs := cv.validateChains(context.Background(), chains)
+			assertEqualE(t, len(errs), 0)
+			if checkAllChains {
+				assertEqualE(t, crt.totalRequests(), 2)
+				assertDeepEqualE(t, results, []crlValidationResult{crlUnrevoked, crlUnrevoked})
+			} else {
+				assertEqualE(t, crt.totalRequests(), 1, "only the first chain should be checked")
+				assertEqualE(t, results[0], crlUnrevoked)
+			}
+		})
+	}
+}
+
+// flakyRoundTripper fails the first requests with a network error or the given status code.
+type flakyRoundTripper struct {
+	delegate   http.RoundTripper
+	failures   int
+	statusCode int
+	requests   int
+}
+
+func (f *flakyRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.requests++
+	if f.requests <= f.failures {
+		if f.statusCode == 0 {
+			return nil, errors.New("connection reset by peer")
+		}
+		return &http.Response{StatusCode: f.statusCode, Body: http.NoBody, Request: req}, nil
+	}
+	return f.delegate.RoundTrip(req)
+}
+
+func TestCrlDownloadRetries(t *testing.T) {
+	caPrivateKey, caCert := createCa(t, nil, nil, "root CA", "/rootCrl")
+	_, leafCert := createLeafCert(t, caCert, caPrivateKey, "/rootCrl")
+	server := createCrlServer(t, newCrlEndpointDef("/rootCrl", createCrl(t, caCert, caPrivateKey)))
+	defer closeServer(t, server)
+	chains := [][]*x509.Certificate{{leafCert, caCert}}
+
+	testcases := []struct {
+		name       string
+		failures   int
+		statusCode int
+		requests   int
+		succeeds   bool
+	}{
+		{name: "network error once", failures: 1, requests: 2, succeeds: true},
+		{name: "server error once", failures: 1, statusCode: http.StatusServiceUnavailable, requests: 2, succeeds: true},
+		{name: "retries exhausted", failures: 3, requests: 3, succeeds: false},
+		{name: "not found is not retried", failures: 1, statusCode: http.StatusNotFound, requests: 1, succeeds: false},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			rt := &flakyRoundTripper{delegate: snowflakeNoOcspTransport, failures: tc.failures, statusCode: tc.statusCode}
+			cv := newTestCrlValidator(t, CertRevocationCheckEnabled, &http.Client{Transport: rt})
+			cv.downloadRetryBackoff = time.Millisecond
+			err := cv.verifyPeerCertificates(nil, chains)
+			if tc.succeeds {
+				assertNilE(t, err)
+			} else {
+				assertNotNilF(t, err)
+				assertEqualE(t, err.Error(), "certificate revocation check failed")
+			}
+			assertEqualE(t, rt.requests, tc.requests)
+		})
+	}
+
+	t.Run("interrupted by the context", func(t *testing.T) {
+		rt := &flakyRoundTripper{delegate: snowflakeNoOcspTransport, failures: 3}
+		cv := newTestCrlValidator(t, CertRevocationCheckEnabled, &http.Client{Transport: rt})
+		cv.downloadRetryBackoff = time.Hour
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		_, _, err := cv.downloadCrl(ctx, fullCrlURL("/rootCrl"))
+		assertErrIsE(t, err, context.DeadlineExceeded)
+		assertTrueE(t, time.Since(start) < 10*time.Second)
+		assertEqualE(t, rt.requests, 1)
+	})
+
+	t.Run("configured in the config", func(t *testing.T) {
+		cfg, err := ParseDSN("u:p@a?certRevocationCheckMode=enabled&crlDownloadRetries=4&crlDownloadRetryBackoff=1ms&crlOnDiskCacheDir=" + url.QueryEscape(t.TempDir()))
+		assertNilF(t, err)
+		cv, err := crlValidatorFromConfig(cfg)
+		assertNilF(t, err)
+		assertEqualE(t, cv.downloadRetries, 4)
+		assertEqualE(t, cv.downloadRetryBackoff, time.Millisecond)
+		rt := &flakyRoundTripper{delegate: snowflakeNoOcspTransport, failures: 4}
+		cv.httpClient = &http.Client{Transport: rt}
+		assertNilE(t, cv.verifyPeerCertificates(nil, chains))
+		assertEqualE(t, rt.requests, 5)
+	})
+}
+
+func TestCrlAuditSink(t *testing.T) {
+	firstKey, firstCaCert := createCa(t, nil, nil, "first CA", "")
+	secondKey, secondCaCert := createCa(t, nil, nil, "second CA", "")
+	_, firstLeafCert := createLeafCert(t, firstCaCert, firstKey, "/firstCrl")
+	_, secondLeafCert := createLeafCert(t, secondCaCert, secondKey, "/secondCrl")
+	server := createCrlServer(t,
+		newCrlEndpointDef("/firstCrl", createCrl(t, firstCaCert, firstKey, revokedCert(firstLeafCert))),
+		newCrlEndpointDef("/secondCrl", createCrl(t, secondCaCert, secondKey)))
+	defer closeServer(t, server)
+	chains := [][]*x509.Certificate{{firstLeafCert, firstCaCert}, {secondLeafCert, secondCaCert}}
+
+	var audits []CrlChainAudit
+	cfg := &Config{
+		CertRevocationCheckMode: CertRevocationCheckEnabled,
+		CrlOnDiskCacheDir:       t.TempDir(),
+		CrlCheckAllChains:       true,
+		CrlAuditSink: func(a []CrlChainAudit) {
+			audits = a
+		},
+	}
+	cv, err := crlValidatorFromConfig(cfg)
+	assertNilF(t, err)
+	expectedAudits := func(cacheHit bool) []CrlChainAudit {
+		return []CrlChainAudit{
+			{
+				Subjects: []string{firstLeafCert.Subject.String(), firstCaCert.Subject.String()},
+				Crls:     []CrlURLAudit{{URL: fullCrlURL("/firstCrl"), CacheHit: cacheHit, Verdict: CrlVerdictRevoked}},
+				Verdict:  CrlVerdictRevoked,
+			},
+			{
+				Subjects: []string{secondLeafCert.Subject.String(), secondCaCert.Subject.String()},
+				Crls:     []CrlURLAudit{{URL: fullCrlURL("/secondCrl"), CacheHit: cacheHit, Verdict: CrlVerdictUnrevoked}},
+				Verdict:  CrlVerdictUnrevoked,
+			},
+		}
+	}
+
+	assertNilF(t, cv.verifyPeerCertificates(nil, chains))
+	assertDeepEqualE(t, audits, expectedAudits(false))
+	assertEqualE(t, audits[0].Verdict.String(), "revoked")
+
+	audits = nil
+	assertNilF(t, cv.verifyPeerCertificates(nil, chains))
+	assertDeepEqualE(t, audits, expectedAudits(true), "the CRLs should be cached")
+
+	first, err := withCrlSettings(snowflakeNoOcspTransport, cfg)
+	assertNilF(t, err)
+	second, err := withCrlSettings(snowflakeNoOcspTransport, cfg)
+	assertNilF(t, err)
+	assertFalseE(t, first == second, "the transports auditing the checks should not be shared")
+}
+
+func TestCrlDownloadWithClientCertificate(t *testing.T) {
+	caPrivateKey, caCert := createCa(t, nil, nil, "root CA", "")
+	crl := createCrl(t, caCert, caPrivateKey)
+	clientPrivateKey, clientCert := createLeafCert(t, caCert, caPrivateKey, "")
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(caCert)
+
+	var presentedCerts [][]*x509.Certificate
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		presentedCerts = append(presentedCerts, r.TLS.PeerCertificates)
+		_, err := w.Write(crl.Raw)
+		assertNilE(t, err)
+	}))
+	server.TLS = &tls.Config{ClientCAs: clientCAs, ClientAuth: tls.RequireAndVerifyClientCert}
+	server.StartTLS()
+	defer server.Close()
+	serverCAs := x509.NewCertPool()
+	serverCAs.AddCert(server.Certificate())
+
+	t.Run("with a client certificate", func(t *testing.T) {
+		presentedCerts = nil
+		tlsConfig := &tls.Config{
+			RootCAs:      serverCAs,
+			Certificates: []tls.Certificate{{Certificate: [][]byte{clientCert.Raw}, PrivateKey: clientPrivateKey}},
+		}
+		cv, err := crlValidatorFromConfig(&Config{
+			CertRevocationCheckMode: CertRevocationCheckEnabled,
+			CrlOnDiskCacheDisabled:  true,
+			CrlOnDiskCacheDir:       t.TempDir(),
+			CrlTLSConfig:            tlsConfig,
+		})
+		assertNilF(t, err)
+		downloadedCrl, _, err := cv.downloadCrl(context.Background(), server.URL+"/rootCrl")
+		assertNilF(t, err)
+		assertDeepEqualE(t, downloadedCrl.Raw, crl.Raw)
+		assertEqualF(t, len(presentedCerts), 1)
+		assertEqualF(t, len(presentedCerts[0]), 1)
+		assertDeepEqualE(t, presentedCerts[0][0].Raw, clientCert.Raw)
+	})
+
+	t.Run("without a client certificate", func(t *testing.T) {
+		presentedCerts = nil
+		cv, err := crlValidatorFromConfig(&Config{
+			CertRevocationCheckMode: CertRevocationCheckEnabled,
+			CrlOnDiskCacheDisabled:  true,
+			CrlOnDiskCacheDir:       t.TempDir(),
+			CrlTLSConfig:            &tls.Config{RootCAs: serverCAs},
+		})
+		assertNilF(t, err)
+		cv.downloadRetries = 0
+		_, _, err = cv.downloadCrl(context.Background(), server.URL+"/rootCrl")
+		assertNotNilE(t, err)
+		assertEqualE(t, len(presentedCerts), 0)
+	})
+}
@@ -25,6 +25,9 @@ const (
 	binaryType
 	timeType
 	booleanType
+	vectorType
+	geographyType
+	geometryType
 	// the following are not snowflake types per se but internal types
 	nullType
 	sliceType
@@ -50,6 +53,9 @@ var snowflakeToDriverType = map[string]snowflakeType{
 	"BINARY":        binaryType,
 	"TIME":          timeType,
 	"BOOLEAN":       booleanType,
+	"VECTOR":        vectorType,
+	"GEOGRAPHY":     geographyType,
+	"GEOMETRY":      geometryType,
 	"NULL":          nullType,
 	"SLICE":         sliceType,
 	"CHANGE_TYPE":   changeType,
@@ -107,6 +113,8 @@ var (
 	DataTypeTime = []byte{timeType.Byte()}
 	// DataTypeBoolean is a BOOLEAN datatype.
 	DataTypeBoolean = []byte{booleanType.Byte()}
+	// DataTypeVector is a VECTOR datatype. It binds the following []float32 or []int32 value as a VECTOR.
+	DataTypeVector = []byte{vectorType.Byte()}
 	// DataTypeNilObject represents a nil structured object.
 	DataTypeNilObject = []byte{nilObjectType.Byte()}
 	// DataTypeNilArray represents a nil structured array.
@@ -137,6 +145,8 @@ func dataTypeMode(v driver.Value) (tsmode snowflakeType, err error) {
 			tsmode = arrayType
 		case bytes.Equal(bd, DataTypeVariant):
 			tsmode = variantType
+		case bytes.Equal(bd, DataTypeVector):
+			tsmode = vectorType
 		case bytes.Equal(bd, DataTypeNilObject):
 			tsmode = nilObjectType
 		case bytes.Equal(bd, DataTypeNilArray):
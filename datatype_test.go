@@ -21,6 +21,7 @@ func TestDataTypeMode(t *testing.T) {
 		{tp: DataTypeObject, tmode: objectType, err: nil},
 		{tp: DataTypeArray, tmode: arrayType, err: nil},
 		{tp: DataTypeVariant, tmode: variantType, err: nil},
+		{tp: DataTypeVector, tmode: vectorType, err: nil},
 		{tp: DataTypeFixed, tmode: fixedType,
 			err: fmt.Errorf(errMsgInvalidByteArray, DataTypeFixed)},
 		{tp: DataTypeReal, tmode: realType,
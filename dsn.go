@@ -1,16 +1,22 @@
 package gosnowflake
 
 import (
+	"compress/gzip"
+	"context"
+	"crypto"
 	"crypto/rsa"
+	"crypto/tls"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net"
 	"net/http"
 	"net/url"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -22,6 +28,8 @@ const (
 	defaultLoginTimeout           = 300 * time.Second // Timeout for retry for login EXCLUDING clientTimeout
 	defaultRequestTimeout         = 0 * time.Second   // Timeout for retry for request EXCLUDING clientTimeout
 	defaultJWTTimeout             = 60 * time.Second
+	maxJWTTimeout                 = time.Hour         // Longest JWT lifetime accepted by the server
+	defaultValidationQuery        = "SELECT 1"        // Query run by Ping, answered by the cloud services layer without a warehouse
 	defaultExternalBrowserTimeout = 120 * time.Second // Timeout for external browser login
 	defaultCloudStorageTimeout    = -1                // Timeout for calling cloud storage.
 	defaultMaxRetryCount          = 7                 // specifies maximum number of subsequent retries
@@ -59,6 +67,8 @@ type Config struct {
 	OauthRedirectURI             string // Redirect URI registered in IdP. The default is http://127.0.0.1:<random port>/
 	OauthScope                   string // Comma separated list of scopes. If empty it is derived from role.
 	EnableSingleUseRefreshTokens bool   // Enables single use refresh tokens for Snowflake IdP
+	OauthRedirectHost            string // Loopback host the redirect listener binds to. The default is 127.0.0.1 in the redirect URI and localhost for the listener.
+	OauthRedirectPortRange       string // Range of local ports the redirect listener may bind to, e.g. 50000-50010. The default is a random free port.
 
 	// ValidateDefaultParameters disable the validation checks for Database, Schema, Warehouse and Role
 	// at the time a connection is established
@@ -66,6 +76,16 @@ type Config struct {
 
 	Params map[string]*string // other connection parameters
 
+	QueryTag string // QUERY_TAG session parameter set on login. It can be overridden per query with WithQueryTag.
+
+	GeographyOutputFormat string // GEOGRAPHY_OUTPUT_FORMAT session parameter set on login: GeoJSON, WKT, EWKT, WKB or EWKB. It can be overridden per query with WithGeographyOutputFormat.
+	GeometryOutputFormat  string // GEOMETRY_OUTPUT_FORMAT session parameter set on login: GeoJSON, WKT, EWKT, WKB or EWKB. It can be overridden per query with WithGeometryOutputFormat.
+
+	TimestampLTZTimezone string // IANA time zone TIMESTAMP_LTZ values are returned in, e.g. America/New_York. The TIMEZONE session parameter by default
+
+	IntegerScanType string // Go type of FIXED columns with scale 0 and precision up to 18: int64 or string. string by default. Ignored with WithHigherPrecision
+	RealScanType    string // Go type of REAL columns: float64 or string. By default JSON results return string and Arrow results float64
+
 	ClientIP net.IP // IP address for network check
 	Protocol string // http or https (optional)
 	Host     string // hostname (optional)
@@ -85,35 +105,96 @@ type Config struct {
 	JWTClientTimeout       time.Duration // Timeout for network round trip + read out http response used when JWT token auth is taking place
 	ExternalBrowserTimeout time.Duration // Timeout for external browser login
 	CloudStorageTimeout    time.Duration // Timeout for a single call to a cloud storage provider
+	DialTimeout            time.Duration // Timeout for opening a TCP connection, excluding the TLS handshake. 30 seconds by default
+	DialNetwork            string        // Network used to open TCP connections: tcp (default), tcp4 for IPv4 only or tcp6 for IPv6 only
 	MaxRetryCount          int           // Specifies how many times non-periodic HTTP request can be retried
+	RetryBackoffBase       time.Duration // Base of the exponential backoff between HTTP request retries. 1 second by default
+	RetryBackoffCap        time.Duration // Maximum backoff between HTTP request retries. 16 seconds by default
 
 	Application       string // application name.
+	ClientOS          string // OS reported in the client environment on login, runtime.GOOS by default
+	ClientOSVersion   string // OS_VERSION reported in the client environment on login, the compiler and the architecture by default
+	ClientGoVersion   string // GO_VERSION reported in the client environment on login, runtime.Version() by default
 	DisableOCSPChecks bool   // driver doesn't check certificate revocation status
 	// Deprecated: InsecureMode use DisableOCSPChecks instead
 	InsecureMode bool             // driver doesn't check certificate revocation status
 	OCSPFailOpen OCSPFailOpenMode // OCSP Fail Open
 
+	OcspInMemoryCacheDisabled bool // OCSP responses are neither read from nor stored in the in-memory cache, the responder is asked on each handshake
+	OcspOnDiskCacheDisabled   bool // OCSP responses are not persisted in the OCSP cache file
+
+	CertRevocationCheckMode           CertRevocationCheckMode // Checks the revocation of the certificates with CRLs instead of OCSP unless disabled (default). Ignored with DisableOCSPChecks or a custom Transporter
+	CrlAllowCertificatesWithoutCrlURL bool                    // Certificates without a CRL distribution point are accepted instead of failing the check
+	CrlCacheValidityTime              time.Duration           // Time a cached CRL is used before it is downloaded again, even before its next update. 24 hours by default
+	CrlInMemoryCacheDisabled          bool                    // CRLs are neither read from nor stored in the in-memory cache
+	CrlOnDiskCacheDisabled            bool                    // CRLs are not persisted in CrlOnDiskCacheDir
+	CrlOnDiskCacheDir                 string                  // Directory of the persisted CRLs, snowflake/crls in the user cache directory by default
+	CrlHTTPClientTimeout              time.Duration           // Timeout of a CRL download. 10 seconds by default
+	CrlPreloadDir                     string                  // Directory of CRLs in DER or PEM format loaded once, e.g. in air-gapped environments. They are used until their next update when no CRL of the distribution point is cached
+	CrlDownloadDisabled               bool                    // CRLs are never downloaded, only the cached and preloaded ones are used
+	CrlTLSConfig                      *tls.Config             // Optional TLS configuration of the CRL downloads, e.g. with a client certificate for a mutually authenticated distribution point. Replaces the TLS settings and RootCAs of the config for them
+	CrlSharedCache                    bool                    // The in-memory CRL cache is shared by all configs of the process with the same CrlOnDiskCacheDir, even when their other CRL settings differ
+	CrlMinimumSignatureHash           crypto.Hash             // CRLs signed with a weaker hash are not trusted. SHA-256 by default
+	CrlInMemoryCacheMaxEntries        int                     // Maximum number of CRLs kept in memory, the least recently used ones being evicted. 100 by default
+	CrlCheckAllChains                 bool                    // Every verified chain is checked, e.g. for auditing, instead of stopping at the first unrevoked one
+	CrlDownloadRetries                int                     // Retries of a CRL download failing with a network or server error. 2 by default
+	CrlDownloadRetryBackoff           time.Duration           // Wait before the first retry of a CRL download, doubled for each next one. 100 milliseconds by default
+	CrlAuditSink                      func([]CrlChainAudit)   // Optional, receives how the chains were checked on each handshake, e.g. to audit the CRLs consulted. Called concurrently by the connections
+
 	Token            string        // Token to use for OAuth other forms of token based auth
 	TokenAccessor    TokenAccessor // Optional token accessor to use
 	KeepSessionAlive bool          // Enables the session to persist even after the connection is closed
 
-	PrivateKey *rsa.PrivateKey // Private key used to sign JWT
+	OAuthTokenProvider func(ctx context.Context) (string, error) // Optional function called before each OAuth login to fetch a fresh access token, takes precedence over Token
+
+	PrivateKey           *rsa.PrivateKey // Private key used to sign JWT
+	PrivateKeyPassphrase string          // Passphrase used to decrypt an encrypted PKCS#8 private key passed in DSN or connections.toml
+	PrivateKeySecondary  *rsa.PrivateKey // Optional private key used to sign JWT when the server rejects PrivateKey, e.g. during key rotation
+	JWTIssuer            string          // Overrides the iss claim of the JWT, <ACCOUNT>.<USER>.SHA256:<public key fingerprint> by default
+	JWTSubject           string          // Overrides the sub claim of the JWT, <ACCOUNT>.<USER> by default
 
 	Transporter http.RoundTripper // RoundTripper to intercept HTTP requests and responses
 
+	Proxy string // URL of the proxy all requests are sent through, including OCSP requests, e.g. socks5://proxy:1080. http, https, socks5 and socks5h are supported. Replaces the proxy of the environment. Ignored with a custom Transporter
+
+	TLSMinVersion   uint16   // Minimum TLS version of the driver's connections, e.g. tls.VersionTLS13. The Go default is used if not set. Ignored with a custom Transporter
+	TLSCipherSuites []uint16 // Cipher suites allowed for TLS 1.0-1.2, e.g. tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384. TLS 1.3 suites are not configurable. Ignored with a custom Transporter
+
+	RootCAs     *x509.CertPool // Root CAs trusted by the driver's TLS connections instead of the built-in CA bundle and the system roots. Ignored with a custom Transporter
+	RootCAsFile string         // Path of a PEM bundle of root CAs loaded into RootCAs when it is not set. Kept in the DSN of the config, unlike RootCAs
+
 	DisableTelemetry bool // indicates whether to disable telemetry
 
-	Tracing string // sets logging level
+	Tracing string       // sets logging level
+	Logger  *slog.Logger // Optional structured logger for logins, queries, retries and CRL downloads. Nothing is emitted to it when not set
+
+	OnSessionParameters func(map[string]any) // Optional function called on its own goroutine after each successful login with a copy of the session parameters returned by the server
 
 	TmpDirPath string // sets temporary directory used by a driver for operations like encrypting, compressing etc
 
+	UploadCompressionLevel int // gzip level of files compressed by PUT, from 1 (fastest) to 9 (smallest). 0 (default) uses the gzip default level
+	MaxGetConcurrency      int // Maximum number of files downloaded at a time by GET, capped by the PARALLEL option of the command. 0 (default) uses the PARALLEL option
+
 	MfaToken                       string     // Internally used to cache the MFA token
 	IDToken                        string     // Internally used to cache the Id Token for external browser
 	ClientRequestMfaToken          ConfigBool // When true the MFA token is cached in the credential manager. True by default in Windows/OSX. False for Linux.
 	ClientStoreTemporaryCredential ConfigBool // When true the ID token is cached in the credential manager. True by default in Windows/OSX. False for Linux.
 
+	CredentialStore    CredentialStore // Optional storage for cached temporary credentials. Replaces the default file or keyring based storage.
+	CredentialCacheDir string          // Directory of the file caching temporary credentials, created with 0700 permissions if missing. Replaces the default storage except on Windows. Ignored with a CredentialStore
+
 	DisableQueryContextCache bool // Should HTAP query context cache be disabled
 
+	PreparedStatementCacheSize int // Number of describe results of prepared statements cached per connection. 0 (default) disables the cache
+
+	MaxStatementsPerSecond int // Maximum number of statements a connection starts per second, blocking until the next one may start. 0 (default) is unlimited
+
+	ValidationQuery string // Query run by Ping to check the connection. SELECT 1 (default) is answered without a running warehouse
+
+	FailIfWarehouseSuspended bool // Aborts queries waiting for their suspended warehouse to resume and returns ErrWarehouseSuspended instead
+
+	ArrayBindChunkSize int // Maximum number of array bind values sent inline in a single request. Larger array binds are split into several requests. 65280 by default, a negative value disables splitting
+
 	IncludeRetryReason ConfigBool // Should retried request contain retry reason
 
 	ClientConfigFile string // File path to the client configuration json file
@@ -122,6 +203,11 @@ type Config struct {
 
 	DisableSamlURLCheck ConfigBool // Indicates whether the SAML URL check should be disabled
 
+	// DisableOAuthURLValidation treats OauthAuthorizationURL and OauthTokenRequestURL as Snowflake URLs
+	// even when their host is not the account host, e.g. in split-network setups. The default client
+	// credentials of Snowflake as the IdP are then sent to these URLs, so only enable it for trusted URLs.
+	DisableOAuthURLValidation ConfigBool
+
 	WorkloadIdentityProvider      string // The workload identity provider to use for WIF authentication
 	WorkloadIdentityEntraResource string // The resource to use for WIF authentication on Azure environment
 }
@@ -218,6 +304,51 @@ func DSN(cfg *Config) (dsn string, err error) {
 	if cfg.OauthScope != "" {
 		params.Add("oauthScope", cfg.OauthScope)
 	}
+	if cfg.QueryTag != "" {
+		params.Add("queryTag", cfg.QueryTag)
+	}
+	if cfg.ClientOS != "" {
+		params.Add("clientOs", cfg.ClientOS)
+	}
+	if cfg.ClientOSVersion != "" {
+		params.Add("clientOsVersion", cfg.ClientOSVersion)
+	}
+	if cfg.ClientGoVersion != "" {
+		params.Add("clientGoVersion", cfg.ClientGoVersion)
+	}
+	if cfg.GeographyOutputFormat != "" {
+		params.Add("geographyOutputFormat", cfg.GeographyOutputFormat)
+	}
+	if cfg.GeometryOutputFormat != "" {
+		params.Add("geometryOutputFormat", cfg.GeometryOutputFormat)
+	}
+	if cfg.TimestampLTZTimezone != "" {
+		params.Add("timestampLtzTimezone", cfg.TimestampLTZTimezone)
+	}
+	if cfg.Proxy != "" {
+		params.Add("proxy", cfg.Proxy)
+	}
+	if cfg.IntegerScanType != "" {
+		params.Add("integerScanType", cfg.IntegerScanType)
+	}
+	if cfg.RealScanType != "" {
+		params.Add("realScanType", cfg.RealScanType)
+	}
+	if cfg.OauthRedirectHost != "" {
+		params.Add("oauthRedirectHost", cfg.OauthRedirectHost)
+	}
+	if cfg.OauthRedirectPortRange != "" {
+		params.Add("oauthRedirectPortRange", cfg.OauthRedirectPortRange)
+	}
+	if cfg.TLSMinVersion != 0 {
+		params.Add("tlsMinVersion", tlsVersionString(cfg.TLSMinVersion))
+	}
+	if len(cfg.TLSCipherSuites) > 0 {
+		params.Add("tlsCipherSuites", tlsCipherSuitesString(cfg.TLSCipherSuites))
+	}
+	if cfg.RootCAsFile != "" {
+		params.Add("rootCAsFile", cfg.RootCAsFile)
+	}
 	if cfg.EnableSingleUseRefreshTokens {
 		params.Add("enableSingleUseRefreshTokens", strconv.FormatBool(cfg.EnableSingleUseRefreshTokens))
 	}
@@ -261,9 +392,21 @@ func DSN(cfg *Config) (dsn string, err error) {
 	if cfg.CloudStorageTimeout != defaultCloudStorageTimeout {
 		params.Add("cloudStorageTimeout", strconv.FormatInt(int64(cfg.CloudStorageTimeout/time.Second), 10))
 	}
+	if cfg.DialTimeout != 0 {
+		params.Add("dialTimeout", cfg.DialTimeout.String())
+	}
+	if cfg.DialNetwork != "" {
+		params.Add("dialNetwork", cfg.DialNetwork)
+	}
 	if cfg.MaxRetryCount != defaultMaxRetryCount {
 		params.Add("maxRetryCount", strconv.Itoa(cfg.MaxRetryCount))
 	}
+	if cfg.RetryBackoffBase != 0 {
+		params.Add("retryBackoffBase", cfg.RetryBackoffBase.String())
+	}
+	if cfg.RetryBackoffCap != 0 {
+		params.Add("retryBackoffCap", cfg.RetryBackoffCap.String())
+	}
 	if cfg.Application != clientType {
 		params.Add("application", cfg.Application)
 	}
@@ -286,21 +429,99 @@ func DSN(cfg *Config) (dsn string, err error) {
 		keyBase64 := base64.URLEncoding.EncodeToString(privateKeyInBytes)
 		params.Add("privateKey", keyBase64)
 	}
+	if cfg.JWTIssuer != "" {
+		params.Add("jwtIssuer", cfg.JWTIssuer)
+	}
+	if cfg.JWTSubject != "" {
+		params.Add("jwtSubject", cfg.JWTSubject)
+	}
 	if cfg.InsecureMode {
 		params.Add("insecureMode", strconv.FormatBool(cfg.InsecureMode))
 	}
 	if cfg.DisableOCSPChecks {
 		params.Add("disableOCSPChecks", strconv.FormatBool(cfg.DisableOCSPChecks))
 	}
+	if cfg.OcspInMemoryCacheDisabled {
+		params.Add("ocspInMemoryCacheDisabled", "true")
+	}
+	if cfg.OcspOnDiskCacheDisabled {
+		params.Add("ocspOnDiskCacheDisabled", "true")
+	}
+	if cfg.CertRevocationCheckMode != CertRevocationCheckDisabled {
+		params.Add("certRevocationCheckMode", cfg.CertRevocationCheckMode.String())
+	}
+	if cfg.CrlAllowCertificatesWithoutCrlURL {
+		params.Add("crlAllowCertificatesWithoutCrlURL", "true")
+	}
+	if cfg.CrlCacheValidityTime != 0 {
+		params.Add("crlCacheValidityTime", cfg.CrlCacheValidityTime.String())
+	}
+	if cfg.CrlInMemoryCacheDisabled {
+		params.Add("crlInMemoryCacheDisabled", "true")
+	}
+	if cfg.CrlOnDiskCacheDisabled {
+		params.Add("crlOnDiskCacheDisabled", "true")
+	}
+	if cfg.CrlOnDiskCacheDir != "" {
+		params.Add("crlOnDiskCacheDir", cfg.CrlOnDiskCacheDir)
+	}
+	if cfg.CrlHTTPClientTimeout != 0 {
+		params.Add("crlHttpClientTimeout", cfg.CrlHTTPClientTimeout.String())
+	}
+	if cfg.CrlPreloadDir != "" {
+		params.Add("crlPreloadDir", cfg.CrlPreloadDir)
+	}
+	if cfg.CrlDownloadDisabled {
+		params.Add("crlDownloadDisabled", "true")
+	}
+	if cfg.CrlSharedCache {
+		params.Add("crlSharedCache", "true")
+	}
+	if cfg.CrlMinimumSignatureHash != 0 {
+		params.Add("crlMinimumSignatureHash", cfg.CrlMinimumSignatureHash.String())
+	}
+	if cfg.CrlInMemoryCacheMaxEntries != 0 {
+		params.Add("crlInMemoryCacheMaxEntries", strconv.Itoa(cfg.CrlInMemoryCacheMaxEntries))
+	}
+	if cfg.CrlCheckAllChains {
+		params.Add("crlCheckAllChains", "true")
+	}
+	if cfg.CrlDownloadRetries != 0 {
+		params.Add("crlDownloadRetries", strconv.Itoa(cfg.CrlDownloadRetries))
+	}
+	if cfg.CrlDownloadRetryBackoff != 0 {
+		params.Add("crlDownloadRetryBackoff", cfg.CrlDownloadRetryBackoff.String())
+	}
 	if cfg.Tracing != "" {
 		params.Add("tracing", cfg.Tracing)
 	}
 	if cfg.TmpDirPath != "" {
 		params.Add("tmpDirPath", cfg.TmpDirPath)
 	}
+	if cfg.UploadCompressionLevel != 0 {
+		params.Add("uploadCompressionLevel", strconv.Itoa(cfg.UploadCompressionLevel))
+	}
+	if cfg.MaxGetConcurrency != 0 {
+		params.Add("maxGetConcurrency", strconv.Itoa(cfg.MaxGetConcurrency))
+	}
 	if cfg.DisableQueryContextCache {
 		params.Add("disableQueryContextCache", "true")
 	}
+	if cfg.PreparedStatementCacheSize > 0 {
+		params.Add("preparedStatementCacheSize", strconv.Itoa(cfg.PreparedStatementCacheSize))
+	}
+	if cfg.MaxStatementsPerSecond > 0 {
+		params.Add("maxStatementsPerSecond", strconv.Itoa(cfg.MaxStatementsPerSecond))
+	}
+	if cfg.ValidationQuery != "" {
+		params.Add("validationQuery", cfg.ValidationQuery)
+	}
+	if cfg.FailIfWarehouseSuspended {
+		params.Add("failIfWarehouseSuspended", "true")
+	}
+	if cfg.ArrayBindChunkSize != 0 {
+		params.Add("arrayBindChunkSize", strconv.Itoa(cfg.ArrayBindChunkSize))
+	}
 	if cfg.IncludeRetryReason == ConfigBoolFalse {
 		params.Add("includeRetryReason", "false")
 	}
@@ -316,6 +537,9 @@ func DSN(cfg *Config) (dsn string, err error) {
 	if cfg.ClientStoreTemporaryCredential != configBoolNotSet {
 		params.Add("clientStoreTemporaryCredential", strconv.FormatBool(cfg.ClientStoreTemporaryCredential != ConfigBoolFalse))
 	}
+	if cfg.CredentialCacheDir != "" {
+		params.Add("credentialCacheDir", cfg.CredentialCacheDir)
+	}
 	if cfg.ClientConfigFile != "" {
 		params.Add("clientConfigFile", cfg.ClientConfigFile)
 	}
@@ -325,6 +549,9 @@ func DSN(cfg *Config) (dsn string, err error) {
 	if cfg.DisableSamlURLCheck != configBoolNotSet {
 		params.Add("disableSamlURLCheck", strconv.FormatBool(cfg.DisableSamlURLCheck != ConfigBoolFalse))
 	}
+	if cfg.DisableOAuthURLValidation != configBoolNotSet {
+		params.Add("disableOAuthURLValidation", strconv.FormatBool(cfg.DisableOAuthURLValidation != ConfigBoolFalse))
+	}
 
 	dsn = fmt.Sprintf("%v:%v@%v:%v", url.QueryEscape(cfg.User), url.QueryEscape(cfg.Password), cfg.Host, cfg.Port)
 	if params.Encode() != "" {
@@ -335,6 +562,12 @@ func DSN(cfg *Config) (dsn string, err error) {
 
 // ParseDSN parses the DSN string to a Config.
 func ParseDSN(dsn string) (cfg *Config, err error) {
+	defer func() {
+		if err != nil {
+			// parts of a malformed DSN end up in the error, they must not reveal the credentials
+			err = redactErrorSecrets(err, dsnSecrets(dsn))
+		}
+	}()
 	// New config with some default values
 	cfg = &Config{
 		Params:        make(map[string]*string),
@@ -485,24 +718,8 @@ func fillMissingConfigParameters(cfg *Config) error {
 			cfg.Account = cfg.Account[:posDash]
 		}
 	}
-	if strings.Trim(cfg.Account, " ") == "" {
-		return errEmptyAccount()
-	}
-
-	if authRequiresUser(cfg) && strings.TrimSpace(cfg.User) == "" {
-		return errEmptyUsername()
-	}
-
-	if authRequiresPassword(cfg) && strings.TrimSpace(cfg.Password) == "" {
-		return errEmptyPassword()
-	}
-
-	if authRequiresEitherPasswordOrToken(cfg) && strings.TrimSpace(cfg.Password) == "" && strings.TrimSpace(cfg.Token) == "" {
-		return errEmptyPasswordAndToken()
-	}
-
-	if authRequiresClientIDAndSecret(cfg) && (strings.TrimSpace(cfg.OauthClientID) == "" || strings.TrimSpace(cfg.OauthClientSecret) == "") {
-		return errEmptyOAuthParameters()
+	if errs := validateConfigParameters(cfg); len(errs) > 0 {
+		return errs[0]
 	}
 	if strings.Trim(cfg.Protocol, " ") == "" {
 		cfg.Protocol = "https"
@@ -510,9 +727,16 @@ func fillMissingConfigParameters(cfg *Config) error {
 	if cfg.Port == 0 {
 		cfg.Port = 443
 	}
+	if cfg.RootCAs == nil && cfg.RootCAsFile != "" {
+		var err error
+		if cfg.RootCAs, err = loadRootCAsFromFile(cfg.RootCAsFile); err != nil {
+			return err
+		}
+	}
 
 	cfg.Region = strings.Trim(cfg.Region, " ")
-	if cfg.Region != "" {
+	// a PrivateLink host is the actual endpoint and is used as given
+	if cfg.Region != "" && !isPrivateLink(cfg.Host) {
 		// region is specified but not included in Host
 		domain, i := extractDomainFromHost(cfg.Host)
 		if i >= 1 {
@@ -534,6 +758,7 @@ func fillMissingConfigParameters(cfg *Config) error {
 			}
 		}
 	}
+	warnOnMismatchedHostAndAccount(cfg)
 	if cfg.LoginTimeout == 0 {
 		cfg.LoginTimeout = defaultLoginTimeout
 	}
@@ -561,6 +786,11 @@ func fillMissingConfigParameters(cfg *Config) error {
 	if strings.Trim(cfg.Application, " ") == "" {
 		cfg.Application = clientType
 	}
+	if !applicationNamePattern.MatchString(cfg.Application) {
+		// rejecting the names accepted by the earlier versions would break existing configurations
+		logger.Warnf("application name %q may not be accepted by Snowflake. it should start with a letter "+
+			"followed by 1 to 50 letters, digits, periods, hyphens or underscores", cfg.Application)
+	}
 
 	if cfg.OCSPFailOpen == ocspFailOpenNotSet {
 		cfg.OCSPFailOpen = OCSPFailOpenTrue
@@ -585,6 +815,232 @@ func fillMissingConfigParameters(cfg *Config) error {
 	return nil
 }
 
+// validateConfigParameters returns an error for every missing or invalid setting of the config,
+// in the order fillMissingConfigParameters reports the first of them.
+func validateConfigParameters(cfg *Config) []error {
+	var errs []error
+	if strings.Trim(cfg.Account, " ") == "" {
+		errs = append(errs, errEmptyAccount())
+	}
+	if authRequiresUser(cfg) && strings.TrimSpace(cfg.User) == "" {
+		errs = append(errs, errEmptyUsername())
+	}
+	if authRequiresPassword(cfg) && strings.TrimSpace(cfg.Password) == "" {
+		errs = append(errs, errEmptyPassword())
+	}
+	if authRequiresEitherPasswordOrToken(cfg) && strings.TrimSpace(cfg.Password) == "" && strings.TrimSpace(cfg.Token) == "" {
+		errs = append(errs, errEmptyPasswordAndToken())
+	}
+	if authRequiresClientIDAndSecret(cfg) && (strings.TrimSpace(cfg.OauthClientID) == "" || strings.TrimSpace(cfg.OauthClientSecret) == "") {
+		errs = append(errs, errEmptyOAuthParameters())
+	}
+	if cfg.OauthRedirectPortRange != "" {
+		if _, _, err := parsePortRange(cfg.OauthRedirectPortRange); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if cfg.TLSMinVersion != 0 && tlsVersionString(cfg.TLSMinVersion) == "" {
+		errs = append(errs, &SnowflakeError{
+			Number:      ErrCodeInvalidTLSSetting,
+			Message:     errMsgInvalidTLSMinVersion,
+			MessageArgs: []interface{}{fmt.Sprintf("0x%04x", cfg.TLSMinVersion)},
+		})
+	}
+	if err := validateScanTypes(cfg); err != nil {
+		errs = append(errs, err)
+	}
+	supportedCipherSuites := supportedTLSCipherSuites()
+	for _, id := range cfg.TLSCipherSuites {
+		if _, ok := supportedCipherSuites[tls.CipherSuiteName(id)]; !ok {
+			errs = append(errs, errInvalidTLSCipherSuite(tls.CipherSuiteName(id)))
+		}
+	}
+	if cfg.Proxy != "" {
+		if err := validateProxy(cfg.Proxy); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if cfg.UploadCompressionLevel < 0 || cfg.UploadCompressionLevel > gzip.BestCompression {
+		errs = append(errs, &SnowflakeError{
+			Number:      ErrCodeInvalidCompressionLevel,
+			Message:     errMsgInvalidCompressionLevel,
+			MessageArgs: []interface{}{cfg.UploadCompressionLevel},
+		})
+	}
+	switch cfg.DialNetwork {
+	case "", "tcp", "tcp4", "tcp6":
+	default:
+		errs = append(errs, &SnowflakeError{
+			Number:      ErrCodeInvalidDialNetwork,
+			Message:     errMsgInvalidDialNetwork,
+			MessageArgs: []interface{}{cfg.DialNetwork},
+		})
+	}
+	if _, ok := certRevocationCheckModeNames[cfg.CertRevocationCheckMode]; !ok {
+		errs = append(errs, &SnowflakeError{
+			Number:      ErrCodeInvalidCertRevocationCheckMode,
+			Message:     errMsgInvalidCertRevocationCheckMode,
+			MessageArgs: []interface{}{cfg.CertRevocationCheckMode},
+		})
+	}
+	if cfg.CrlMinimumSignatureHash != 0 && !isCrlSignatureHash(cfg.CrlMinimumSignatureHash) {
+		errs = append(errs, &SnowflakeError{
+			Number:      ErrCodeInvalidCrlSetting,
+			Message:     errMsgInvalidCrlMinimumSignatureHash,
+			MessageArgs: []interface{}{cfg.CrlMinimumSignatureHash},
+		})
+	}
+	if cfg.CrlInMemoryCacheMaxEntries < 0 {
+		errs = append(errs, &SnowflakeError{
+			Number:      ErrCodeInvalidCrlSetting,
+			Message:     errMsgNegativeCrlSetting,
+			MessageArgs: []interface{}{"crlInMemoryCacheMaxEntries", cfg.CrlInMemoryCacheMaxEntries},
+		})
+	}
+	if cfg.CrlDownloadRetries < 0 {
+		errs = append(errs, &SnowflakeError{
+			Number:      ErrCodeInvalidCrlSetting,
+			Message:     errMsgNegativeCrlSetting,
+			MessageArgs: []interface{}{"crlDownloadRetries", cfg.CrlDownloadRetries},
+		})
+	}
+	if cfg.CrlDownloadRetryBackoff < 0 {
+		errs = append(errs, &SnowflakeError{
+			Number:      ErrCodeInvalidCrlSetting,
+			Message:     errMsgNegativeCrlSetting,
+			MessageArgs: []interface{}{"crlDownloadRetryBackoff", cfg.CrlDownloadRetryBackoff},
+		})
+	}
+	if cfg.TimestampLTZTimezone != "" {
+		if _, err := time.LoadLocation(cfg.TimestampLTZTimezone); err != nil {
+			errs = append(errs, &SnowflakeError{
+				Number:      ErrCodeInvalidTimezone,
+				Message:     errMsgInvalidTimezone,
+				MessageArgs: []interface{}{cfg.TimestampLTZTimezone, err},
+			})
+		}
+	}
+	return errs
+}
+
+// applicationNamePattern is the character set Snowflake accepts in the application name
+// reported in the CLIENT_APPLICATION_* columns of the query history.
+var applicationNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9.\-_]{1,50}$`)
+
+// validateProxy checks that the proxy is a URL with a scheme supported by net/http.
+func validateProxy(proxy string) error {
+	proxyURL, err := url.Parse(proxy)
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		// the URL of the error would reveal the password of the proxy
+		err = urlErr.Err
+	}
+	if err == nil && proxyURL.Host == "" {
+		err = errors.New("missing host")
+	}
+	if err == nil {
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			err = fmt.Errorf("unsupported scheme %q", proxyURL.Scheme)
+		}
+	}
+	if err != nil {
+		return &SnowflakeError{
+			Number:      ErrCodeInvalidProxy,
+			Message:     errMsgInvalidProxy,
+			MessageArgs: []interface{}{err},
+		}
+	}
+	return nil
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// parseTLSVersion parses a TLS version in the form of 1.2 or 1.3.
+func parseTLSVersion(version string) (uint16, error) {
+	v, ok := tlsVersions[strings.TrimSpace(version)]
+	if !ok {
+		return 0, &SnowflakeError{
+			Number:      ErrCodeInvalidTLSSetting,
+			Message:     errMsgInvalidTLSMinVersion,
+			MessageArgs: []interface{}{version},
+		}
+	}
+	return v, nil
+}
+
+// tlsVersionString returns the version in the form of 1.2 or 1.3, or an empty string if it's unknown.
+func tlsVersionString(version uint16) string {
+	for name, v := range tlsVersions {
+		if v == version {
+			return name
+		}
+	}
+	return ""
+}
+
+// supportedTLSCipherSuites returns the cipher suites implemented by crypto/tls by name.
+func supportedTLSCipherSuites() map[string]uint16 {
+	suites := make(map[string]uint16)
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		suites[suite.Name] = suite.ID
+	}
+	return suites
+}
+
+// parseTLSCipherSuites parses a comma separated list of cipher suite names, e.g. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256.
+func parseTLSCipherSuites(names string) ([]uint16, error) {
+	supported := supportedTLSCipherSuites()
+	var ids []uint16
+	for _, name := range strings.Split(names, ",") {
+		id, ok := supported[strings.TrimSpace(name)]
+		if !ok {
+			return nil, errInvalidTLSCipherSuite(name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func tlsCipherSuitesString(ids []uint16) string {
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = tls.CipherSuiteName(id)
+	}
+	return strings.Join(names, ",")
+}
+
+// parsePortRange parses a port range in the form of <from>-<to>. A single port is also accepted.
+func parsePortRange(portRange string) (int, int, error) {
+	invalidRange := &SnowflakeError{
+		Number:      ErrCodeFailedToParsePort,
+		Message:     errMsgFailedToParsePortRange,
+		MessageArgs: []interface{}{portRange},
+	}
+	fromStr, toStr, found := strings.Cut(strings.TrimSpace(portRange), "-")
+	if !found {
+		toStr = fromStr
+	}
+	from, err := strconv.Atoi(strings.TrimSpace(fromStr))
+	if err != nil {
+		return 0, 0, invalidRange
+	}
+	to, err := strconv.Atoi(strings.TrimSpace(toStr))
+	if err != nil {
+		return 0, 0, invalidRange
+	}
+	if from <= 0 || to > 65535 || from > to {
+		return 0, 0, invalidRange
+	}
+	return from, to, nil
+}
+
 func extractDomainFromHost(host string) (domain string, index int) {
 	i := strings.LastIndex(strings.ToLower(host), topLevelDomainPrefix)
 	if i >= 1 {
@@ -613,6 +1069,24 @@ func hostIncludesTopLevelDomain(host string) bool {
 	return strings.Contains(strings.ToLower(host), topLevelDomainPrefix)
 }
 
+// warnOnMismatchedHostAndAccount logs a warning if a Snowflake host obviously belongs to a different
+// account or region than the configured ones. The host is the endpoint the driver connects to while the
+// account is the logical account used to log in, so both are kept as they are.
+func warnOnMismatchedHostAndAccount(cfg *Config) {
+	domain, i := extractDomainFromHost(cfg.Host)
+	if i < 1 || domain == "" {
+		return
+	}
+	labels := strings.Split(strings.ToLower(cfg.Host[:i]), ".")
+	if account := strings.ToLower(cfg.Account); !strings.HasPrefix(labels[0], account) {
+		logger.Warnf("host %v does not match account %v, the host is used to connect and the account to log in", cfg.Host, cfg.Account)
+	}
+	// e.g. myaccount.us-east-1.privatelink
+	if cfg.Region != "" && isPrivateLink(cfg.Host) && len(labels) == 3 && labels[1] != strings.ToLower(cfg.Region) {
+		logger.Warnf("PrivateLink host %v does not match region %v", cfg.Host, cfg.Region)
+	}
+}
+
 func buildHostFromAccountAndRegion(account, region string) string {
 	return account + "." + region + getDomainBasedOnRegion(region)
 }
@@ -650,25 +1124,41 @@ func authRequiresClientIDAndSecret(cfg *Config) bool {
 func transformAccountToHost(cfg *Config) (err error) {
 	if cfg.Port == 0 && cfg.Host != "" && !hostIncludesTopLevelDomain(cfg.Host) {
 		// account name is specified instead of host:port
-		cfg.Account = cfg.Host
-		region, posDot := extractRegionFromAccount(cfg.Account)
-		if strings.ToLower(region) == "us-west-2" {
-			region = ""
-			cfg.Account = cfg.Account[:posDot]
-			logger.Info("Ignoring default region .us-west-2 from Account configuration.")
+		info, err := ParseAccountIdentifier(cfg.Host)
+		if err != nil {
+			// rejecting the accounts accepted by the earlier versions would break existing DSNs
+			logger.Warnf("%v. using the account as given", err)
+			info = accountInfoAsGiven(cfg.Host)
 		}
-		if region != "" {
-			cfg.Region = region
-			cfg.Account = cfg.Account[:posDot]
-			cfg.Host = buildHostFromAccountAndRegion(cfg.Account, cfg.Region)
-		} else {
-			cfg.Host = cfg.Account + defaultDomain
+		cfg.Account = info.Account
+		if info.Region != "" {
+			cfg.Region = info.Region
 		}
+		cfg.Host = info.Host
 		cfg.Port = 443
 	}
 	return nil
 }
 
+// accountInfoAsGiven splits the account at its first dot as the earlier versions did,
+// for the accounts ParseAccountIdentifier rejects.
+func accountInfoAsGiven(account string) AccountInfo {
+	info := AccountInfo{Account: account}
+	region, posDot := extractRegionFromAccount(account)
+	if strings.ToLower(region) == "us-west-2" {
+		region = ""
+		info.Account = account[:posDot]
+	}
+	if region != "" {
+		info.Region = region
+		info.Account = account[:posDot]
+		info.Host = buildHostFromAccountAndRegion(info.Account, info.Region)
+	} else {
+		info.Host = info.Account + defaultDomain
+	}
+	return info
+}
+
 // parseAccountHostPort parses the DSN string to attempt to get account or host and port.
 func parseAccountHostPort(cfg *Config, posAt, posSlash int, dsn string) (err error) {
 	// account or host:port
@@ -719,7 +1209,8 @@ func parseParams(cfg *Config, posQuestion int, dsn string) (err error) {
 
 // parseDSNParams parses the DSN "query string". Values must be url.QueryEscape'ed
 func parseDSNParams(cfg *Config, params string) (err error) {
-	logger.Infof("Query String: %v\n", params)
+	logger.Infof("Query String: %v\n", redactQueryParams(params))
+	var privateKeyBlock []byte
 	paramsSlice := strings.Split(params, "&")
 	insecureModeIdx := findByPrefix(paramsSlice, "insecureMode")
 	disableOCSPChecksIdx := findByPrefix(paramsSlice, "disableOCSPChecks")
@@ -741,6 +1232,8 @@ func parseDSNParams(cfg *Config, params string) (err error) {
 		// Disable INFILE whitelist / enable all files
 		case "account":
 			cfg.Account = value
+		case "host":
+			cfg.Host = value
 		case "warehouse":
 			cfg.Warehouse = value
 		case "database":
@@ -767,6 +1260,46 @@ func parseDSNParams(cfg *Config, params string) (err error) {
 			cfg.OauthRedirectURI = value
 		case "oauthScope":
 			cfg.OauthScope = value
+		case "queryTag":
+			cfg.QueryTag = value
+		case "clientOs":
+			cfg.ClientOS = value
+		case "clientOsVersion":
+			cfg.ClientOSVersion = value
+		case "clientGoVersion":
+			cfg.ClientGoVersion = value
+		case "geographyOutputFormat":
+			cfg.GeographyOutputFormat = value
+		case "geometryOutputFormat":
+			cfg.GeometryOutputFormat = value
+		case "timestampLtzTimezone":
+			cfg.TimestampLTZTimezone = value
+		case "proxy":
+			cfg.Proxy = value
+		case "integerScanType":
+			cfg.IntegerScanType = value
+		case "realScanType":
+			cfg.RealScanType = value
+		case "oauthRedirectHost":
+			cfg.OauthRedirectHost = value
+		case "oauthRedirectPortRange":
+			cfg.OauthRedirectPortRange = value
+		case "tlsMinVersion":
+			cfg.TLSMinVersion, err = parseTLSVersion(value)
+			if err != nil {
+				return
+			}
+		case "tlsCipherSuites":
+			cfg.TLSCipherSuites, err = parseTLSCipherSuites(value)
+			if err != nil {
+				return
+			}
+		case "rootCAsFile":
+			cfg.RootCAsFile = value
+			cfg.RootCAs, err = loadRootCAsFromFile(value)
+			if err != nil {
+				return
+			}
 		case "enableSingleUseRefreshTokens":
 			var vv bool
 			vv, err = strconv.ParseBool(value)
@@ -821,6 +1354,23 @@ func parseDSNParams(cfg *Config, params string) (err error) {
 			if err != nil {
 				return err
 			}
+		case "dialTimeout":
+			cfg.DialTimeout, err = parseBackoffDuration(value)
+			if err != nil {
+				return err
+			}
+		case "dialNetwork":
+			cfg.DialNetwork = value
+		case "retryBackoffBase":
+			cfg.RetryBackoffBase, err = parseBackoffDuration(value)
+			if err != nil {
+				return err
+			}
+		case "retryBackoffCap":
+			cfg.RetryBackoffCap, err = parseBackoffDuration(value)
+			if err != nil {
+				return err
+			}
 		case "application":
 			cfg.Application = value
 		case "authenticator":
@@ -854,6 +1404,89 @@ func parseDSNParams(cfg *Config, params string) (err error) {
 			} else {
 				cfg.OCSPFailOpen = OCSPFailOpenFalse
 			}
+		case "ocspInMemoryCacheDisabled":
+			var vv bool
+			vv, err = strconv.ParseBool(value)
+			if err != nil {
+				return
+			}
+			cfg.OcspInMemoryCacheDisabled = vv
+		case "ocspOnDiskCacheDisabled":
+			var vv bool
+			vv, err = strconv.ParseBool(value)
+			if err != nil {
+				return
+			}
+			cfg.OcspOnDiskCacheDisabled = vv
+		case "certRevocationCheckMode":
+			cfg.CertRevocationCheckMode, err = parseCertRevocationCheckMode(value)
+			if err != nil {
+				return
+			}
+		case "crlAllowCertificatesWithoutCrlURL":
+			cfg.CrlAllowCertificatesWithoutCrlURL, err = strconv.ParseBool(value)
+			if err != nil {
+				return
+			}
+		case "crlCacheValidityTime":
+			cfg.CrlCacheValidityTime, err = parseBackoffDuration(value)
+			if err != nil {
+				return
+			}
+		case "crlInMemoryCacheDisabled":
+			cfg.CrlInMemoryCacheDisabled, err = strconv.ParseBool(value)
+			if err != nil {
+				return
+			}
+		case "crlOnDiskCacheDisabled":
+			cfg.CrlOnDiskCacheDisabled, err = strconv.ParseBool(value)
+			if err != nil {
+				return
+			}
+		case "crlOnDiskCacheDir":
+			cfg.CrlOnDiskCacheDir = value
+		case "crlHttpClientTimeout":
+			cfg.CrlHTTPClientTimeout, err = parseBackoffDuration(value)
+			if err != nil {
+				return
+			}
+		case "crlPreloadDir":
+			cfg.CrlPreloadDir = value
+		case "crlDownloadDisabled":
+			cfg.CrlDownloadDisabled, err = strconv.ParseBool(value)
+			if err != nil {
+				return
+			}
+		case "crlSharedCache":
+			cfg.CrlSharedCache, err = strconv.ParseBool(value)
+			if err != nil {
+				return
+			}
+		case "crlMinimumSignatureHash":
+			cfg.CrlMinimumSignatureHash, err = parseCrlSignatureHash(value)
+			if err != nil {
+				return
+			}
+		case "crlInMemoryCacheMaxEntries":
+			cfg.CrlInMemoryCacheMaxEntries, err = strconv.Atoi(value)
+			if err != nil {
+				return
+			}
+		case "crlCheckAllChains":
+			cfg.CrlCheckAllChains, err = strconv.ParseBool(value)
+			if err != nil {
+				return
+			}
+		case "crlDownloadRetries":
+			cfg.CrlDownloadRetries, err = strconv.Atoi(value)
+			if err != nil {
+				return
+			}
+		case "crlDownloadRetryBackoff":
+			cfg.CrlDownloadRetryBackoff, err = parseBackoffDuration(value)
+			if err != nil {
+				return
+			}
 
 		case "token":
 			cfg.Token = value
@@ -863,7 +1496,7 @@ func parseDSNParams(cfg *Config, params string) (err error) {
 			cfg.WorkloadIdentityEntraResource = value
 		case "privateKey":
 			var decodeErr error
-			block, decodeErr := base64.URLEncoding.DecodeString(value)
+			privateKeyBlock, decodeErr = base64.URLEncoding.DecodeString(value)
 			if decodeErr != nil {
 				err = &SnowflakeError{
 					Number:  ErrCodePrivateKeyParseError,
@@ -871,10 +1504,12 @@ func parseDSNParams(cfg *Config, params string) (err error) {
 				}
 				return
 			}
-			cfg.PrivateKey, err = parsePKCS8PrivateKey(block)
-			if err != nil {
-				return err
-			}
+		case "privateKeyPwd":
+			cfg.PrivateKeyPassphrase = value
+		case "jwtIssuer":
+			cfg.JWTIssuer = value
+		case "jwtSubject":
+			cfg.JWTSubject = value
 		case "validateDefaultParameters":
 			var vv bool
 			vv, err = strconv.ParseBool(value)
@@ -908,6 +1543,8 @@ func parseDSNParams(cfg *Config, params string) (err error) {
 			} else {
 				cfg.ClientStoreTemporaryCredential = ConfigBoolFalse
 			}
+		case "credentialCacheDir":
+			cfg.CredentialCacheDir = value
 		case "tracing":
 			cfg.Tracing = value
 		case "tmpDirPath":
@@ -919,6 +1556,40 @@ func parseDSNParams(cfg *Config, params string) (err error) {
 				return
 			}
 			cfg.DisableQueryContextCache = b
+		case "failIfWarehouseSuspended":
+			var b bool
+			b, err = strconv.ParseBool(value)
+			if err != nil {
+				return
+			}
+			cfg.FailIfWarehouseSuspended = b
+		case "preparedStatementCacheSize":
+			cfg.PreparedStatementCacheSize, err = strconv.Atoi(value)
+			if err != nil {
+				return
+			}
+		case "maxStatementsPerSecond":
+			cfg.MaxStatementsPerSecond, err = strconv.Atoi(value)
+			if err != nil {
+				return
+			}
+		case "validationQuery":
+			cfg.ValidationQuery = value
+		case "uploadCompressionLevel":
+			cfg.UploadCompressionLevel, err = strconv.Atoi(value)
+			if err != nil {
+				return
+			}
+		case "maxGetConcurrency":
+			cfg.MaxGetConcurrency, err = strconv.Atoi(value)
+			if err != nil {
+				return
+			}
+		case "arrayBindChunkSize":
+			cfg.ArrayBindChunkSize, err = strconv.Atoi(value)
+			if err != nil {
+				return
+			}
 		case "includeRetryReason":
 			var vv bool
 			vv, err = strconv.ParseBool(value)
@@ -954,6 +1625,17 @@ func parseDSNParams(cfg *Config, params string) (err error) {
 			} else {
 				cfg.DisableSamlURLCheck = ConfigBoolFalse
 			}
+		case "disableOAuthURLValidation":
+			var vv bool
+			vv, err = strconv.ParseBool(value)
+			if err != nil {
+				return
+			}
+			if vv {
+				cfg.DisableOAuthURLValidation = ConfigBoolTrue
+			} else {
+				cfg.DisableOAuthURLValidation = ConfigBoolFalse
+			}
 		default:
 			if cfg.Params == nil {
 				cfg.Params = make(map[string]*string)
@@ -962,6 +1644,10 @@ func parseDSNParams(cfg *Config, params string) (err error) {
 			cfg.Params[urlDecodeIfNeeded(param[0])] = &value
 		}
 	}
+	if privateKeyBlock != nil {
+		// the key is parsed after all params as it may require privateKeyPwd
+		cfg.PrivateKey, err = parsePrivateKey(privateKeyBlock, cfg.PrivateKeyPassphrase)
+	}
 	return
 }
 
@@ -979,6 +1665,14 @@ func parseTimeout(value string) (time.Duration, error) {
 	return time.Duration(vv * int64(time.Second)), nil
 }
 
+// parseBackoffDuration accepts a number of seconds or a Go duration string, e.g. 500ms.
+func parseBackoffDuration(value string) (time.Duration, error) {
+	if d, err := parseTimeout(value); err == nil {
+		return d, nil
+	}
+	return time.ParseDuration(value)
+}
+
 // ConfigParam is used to bind the name of the Config field with the environment variable and set the requirement for it
 type ConfigParam struct {
 	Name          string
@@ -1086,6 +1780,27 @@ func GetConfigFromEnv(properties []*ConfigParam) (*Config, error) {
 	return cfg, nil
 }
 
+// loadRootCAsFromFile reads a PEM bundle of root CA certificates.
+func loadRootCAsFromFile(path string) (*x509.CertPool, error) {
+	bundle, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &SnowflakeError{
+			Number:      ErrCodeFailedToLoadRootCAs,
+			Message:     errMsgFailedToLoadRootCAs,
+			MessageArgs: []interface{}{path, err},
+		}
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(bundle) {
+		return nil, &SnowflakeError{
+			Number:      ErrCodeFailedToLoadRootCAs,
+			Message:     errMsgFailedToLoadRootCAs,
+			MessageArgs: []interface{}{path, "no certificates found"},
+		}
+	}
+	return pool, nil
+}
+
 func parsePrivateKeyFromFile(path string) (*rsa.PrivateKey, error) {
 	bytes, err := os.ReadFile(path)
 	if err != nil {
@@ -1,10 +1,12 @@
 package gosnowflake
 
 import (
+	"crypto"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	cr "crypto/rand"
 	"crypto/rsa"
+	"crypto/tls"
 	"crypto/x509"
 	"database/sql"
 	"encoding/pem"
@@ -12,6 +14,7 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
@@ -280,6 +283,130 @@ func TestParseDSN(t *testing.T) {
 			ocspMode: ocspModeFailOpen,
 			err:      nil,
 		},
+		{
+			dsn: "user:pass@account?oauthRedirectHost=127.0.0.1&oauthRedirectPortRange=50000-50010",
+			config: &Config{
+				Account: "account", User: "user", Password: "pass",
+				Protocol: "https", Host: "account.snowflakecomputing.com", Port: 443,
+				OauthRedirectHost: "127.0.0.1", OauthRedirectPortRange: "50000-50010",
+				OCSPFailOpen:              OCSPFailOpenTrue,
+				ValidateDefaultParameters: ConfigBoolTrue,
+				ClientTimeout:             defaultClientTimeout,
+				JWTClientTimeout:          defaultJWTClientTimeout,
+				ExternalBrowserTimeout:    defaultExternalBrowserTimeout,
+				CloudStorageTimeout:       defaultCloudStorageTimeout,
+				IncludeRetryReason:        ConfigBoolTrue,
+			},
+			ocspMode: ocspModeFailOpen,
+			err:      nil,
+		},
+		{
+			dsn: "user:pass@account?retryBackoffBase=2&retryBackoffCap=250ms",
+			config: &Config{
+				Account: "account", User: "user", Password: "pass",
+				Protocol: "https", Host: "account.snowflakecomputing.com", Port: 443,
+				RetryBackoffBase: 2 * time.Second, RetryBackoffCap: 250 * time.Millisecond,
+				OCSPFailOpen:              OCSPFailOpenTrue,
+				ValidateDefaultParameters: ConfigBoolTrue,
+				ClientTimeout:             defaultClientTimeout,
+				JWTClientTimeout:          defaultJWTClientTimeout,
+				ExternalBrowserTimeout:    defaultExternalBrowserTimeout,
+				CloudStorageTimeout:       defaultCloudStorageTimeout,
+				IncludeRetryReason:        ConfigBoolTrue,
+			},
+			ocspMode: ocspModeFailOpen,
+			err:      nil,
+		},
+		{
+			dsn: "user:pass@account?tlsMinVersion=1.2&tlsCipherSuites=TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+			config: &Config{
+				Account: "account", User: "user", Password: "pass",
+				Protocol: "https", Host: "account.snowflakecomputing.com", Port: 443,
+				TLSMinVersion:             tls.VersionTLS12,
+				TLSCipherSuites:           []uint16{tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384, tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
+				OCSPFailOpen:              OCSPFailOpenTrue,
+				ValidateDefaultParameters: ConfigBoolTrue,
+				ClientTimeout:             defaultClientTimeout,
+				JWTClientTimeout:          defaultJWTClientTimeout,
+				ExternalBrowserTimeout:    defaultExternalBrowserTimeout,
+				CloudStorageTimeout:       defaultCloudStorageTimeout,
+				IncludeRetryReason:        ConfigBoolTrue,
+			},
+			ocspMode: ocspModeFailOpen,
+			err:      nil,
+		},
+		{
+			dsn:    "user:pass@account?tlsMinVersion=1.4",
+			config: &Config{},
+			err: &SnowflakeError{
+				Number:      ErrCodeInvalidTLSSetting,
+				Message:     errMsgInvalidTLSMinVersion,
+				MessageArgs: []interface{}{"1.4"},
+			},
+		},
+		{
+			dsn:    "user:pass@account?dialNetwork=udp",
+			config: &Config{},
+			err: &SnowflakeError{
+				Number:      ErrCodeInvalidDialNetwork,
+				Message:     errMsgInvalidDialNetwork,
+				MessageArgs: []interface{}{"udp"},
+			},
+		},
+		{
+			dsn:    "user:pass@account?certRevocationCheckMode=strict",
+			config: &Config{},
+			err: &SnowflakeError{
+				Number:      ErrCodeInvalidCertRevocationCheckMode,
+				Message:     errMsgInvalidCertRevocationCheckMode,
+				MessageArgs: []interface{}{"strict"},
+			},
+		},
+		{
+			dsn:    "user:pass@account?crlMinimumSignatureHash=SHA-224",
+			config: &Config{},
+			err: &SnowflakeError{
+				Number:      ErrCodeInvalidCrlSetting,
+				Message:     errMsgInvalidCrlMinimumSignatureHash,
+				MessageArgs: []interface{}{"SHA-224"},
+			},
+		},
+		{
+			dsn:    "user:pass@account?crlInMemoryCacheMaxEntries=-1",
+			config: &Config{},
+			err: &SnowflakeError{
+				Number:      ErrCodeInvalidCrlSetting,
+				Message:     errMsgNegativeCrlSetting,
+				MessageArgs: []interface{}{"crlInMemoryCacheMaxEntries", -1},
+			},
+		},
+		{
+			dsn:    "user:pass@account?crlDownloadRetries=-1",
+			config: &Config{},
+			err: &SnowflakeError{
+				Number:      ErrCodeInvalidCrlSetting,
+				Message:     errMsgNegativeCrlSetting,
+				MessageArgs: []interface{}{"crlDownloadRetries", -1},
+			},
+		},
+		{
+			dsn:    "user:pass@account?crlDownloadRetryBackoff=-1s",
+			config: &Config{},
+			err: &SnowflakeError{
+				Number:      ErrCodeInvalidCrlSetting,
+				Message:     errMsgNegativeCrlSetting,
+				MessageArgs: []interface{}{"crlDownloadRetryBackoff", -time.Second},
+			},
+		},
+		{
+			dsn:    "user:pass@account?oauthRedirectPortRange=50010-50000",
+			config: &Config{},
+			err: &SnowflakeError{
+				Number:      ErrCodeFailedToParsePort,
+				Message:     errMsgFailedToParsePortRange,
+				MessageArgs: []interface{}{"50010-50000"},
+			},
+		},
 		{
 			dsn: "user:pass@host:123/db/schema?account=ac&protocol=http",
 			config: &Config{
@@ -1178,6 +1305,24 @@ func TestParseDSN(t *testing.T) {
 			ocspMode: ocspModeFailOpen,
 			err:      nil,
 		},
+		{
+			dsn: "u:p@a.snowflake.local:9876?account=a&protocol=http&authenticator=EXTERNALBROWSER&disableOAuthURLValidation=true",
+			config: &Config{
+				Account: "a", User: "u", Password: "p",
+				Authenticator: AuthTypeExternalBrowser,
+				Protocol:      "http", Host: "a.snowflake.local", Port: 9876,
+				OCSPFailOpen:              OCSPFailOpenTrue,
+				ValidateDefaultParameters: ConfigBoolTrue,
+				ClientTimeout:             defaultClientTimeout,
+				JWTClientTimeout:          defaultJWTClientTimeout,
+				ExternalBrowserTimeout:    defaultExternalBrowserTimeout,
+				CloudStorageTimeout:       defaultCloudStorageTimeout,
+				IncludeRetryReason:        ConfigBoolTrue,
+				DisableOAuthURLValidation: ConfigBoolTrue,
+			},
+			ocspMode: ocspModeFailOpen,
+			err:      nil,
+		},
 	}
 
 	for _, at := range []AuthType{AuthTypeExternalBrowser, AuthTypeOAuth} {
@@ -1350,6 +1495,9 @@ func TestParseDSN(t *testing.T) {
 				if test.config.DisableSamlURLCheck != cfg.DisableSamlURLCheck {
 					t.Fatalf("%v: Failed to match DisableSamlURLCheck. expected: %v, got: %v", i, test.config.DisableSamlURLCheck, cfg.DisableSamlURLCheck)
 				}
+				if test.config.DisableOAuthURLValidation != cfg.DisableOAuthURLValidation {
+					t.Fatalf("%v: Failed to match DisableOAuthURLValidation. expected: %v, got: %v", i, test.config.DisableOAuthURLValidation, cfg.DisableOAuthURLValidation)
+				}
 				if test.config.OauthClientID != cfg.OauthClientID {
 					t.Fatalf("%v: Failed to match OauthClientId. expected: %v, got: %v", i, test.config.OauthClientID, cfg.OauthClientID)
 				}
@@ -1404,6 +1552,9 @@ type tcDSN struct {
 
 func TestDSN(t *testing.T) {
 	tmfmt := "MM-DD-YYYY"
+	_, caCert := createCa(t, nil, nil, "root CA", "")
+	rootCAsFile := filepath.Join(t.TempDir(), "ca.pem")
+	assertNilF(t, os.WriteFile(rootCAsFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caCert.Raw}), 0600))
 	testcases := []tcDSN{
 		{
 			cfg: &Config{
@@ -1556,6 +1707,216 @@ func TestDSN(t *testing.T) {
 			},
 			dsn: "u:p@a.r.snowflakecomputing.com:443?enableSingleUseRefreshTokens=true&oauthAuthorizationUrl=http%3A%2F%2Fsomehost.com&oauthClientId=testClientId&oauthClientSecret=testClientSecret&oauthRedirectUri=http%3A%2F%2Flocalhost%3A8001%2Fsome-path&oauthScope=test+scope&oauthTokenRequestUrl=https%3A%2F%2Fsomehost2.com%2Fsomepath&ocspFailOpen=true&region=r&validateDefaultParameters=true",
 		},
+		{
+			cfg: &Config{
+				User:     "u",
+				Password: "p",
+				Account:  "a",
+				Region:   "r",
+				QueryTag: "my tag",
+			},
+			dsn: "u:p@a.r.snowflakecomputing.com:443?ocspFailOpen=true&queryTag=my+tag&region=r&validateDefaultParameters=true",
+		},
+		{
+			cfg: &Config{
+				User:            "u",
+				Password:        "p",
+				Account:         "a",
+				Region:          "r",
+				ClientOS:        "linux",
+				ClientOSVersion: "alpine-3.20",
+				ClientGoVersion: "go1.23.4",
+			},
+			dsn: "u:p@a.r.snowflakecomputing.com:443?clientGoVersion=go1.23.4&clientOs=linux&clientOsVersion=alpine-3.20&ocspFailOpen=true&region=r&validateDefaultParameters=true",
+		},
+		{
+			cfg: &Config{
+				User:             "u",
+				Password:         "p",
+				Account:          "a",
+				Region:           "r",
+				RetryBackoffBase: 500 * time.Millisecond,
+				RetryBackoffCap:  5 * time.Second,
+			},
+			dsn: "u:p@a.r.snowflakecomputing.com:443?ocspFailOpen=true&region=r&retryBackoffBase=500ms&retryBackoffCap=5s&validateDefaultParameters=true",
+		},
+		{
+			cfg: &Config{
+				User:                       "u",
+				Password:                   "p",
+				Account:                    "a",
+				Region:                     "r",
+				PreparedStatementCacheSize: 32,
+			},
+			dsn: "u:p@a.r.snowflakecomputing.com:443?ocspFailOpen=true&preparedStatementCacheSize=32&region=r&validateDefaultParameters=true",
+		},
+		{
+			cfg: &Config{
+				User:                  "u",
+				Password:              "p",
+				Account:               "a",
+				Region:                "r",
+				GeographyOutputFormat: "WKT",
+				GeometryOutputFormat:  "WKB",
+			},
+			dsn: "u:p@a.r.snowflakecomputing.com:443?geographyOutputFormat=WKT&geometryOutputFormat=WKB&ocspFailOpen=true&region=r&validateDefaultParameters=true",
+		},
+		{
+			cfg: &Config{
+				User:                 "u",
+				Password:             "p",
+				Account:              "a",
+				Region:               "r",
+				TimestampLTZTimezone: "America/New_York",
+			},
+			dsn: "u:p@a.r.snowflakecomputing.com:443?ocspFailOpen=true&region=r&timestampLtzTimezone=America%2FNew_York&validateDefaultParameters=true",
+		},
+		{
+			cfg: &Config{
+				User:     "u",
+				Password: "p",
+				Account:  "a",
+				Region:   "r",
+				Proxy:    "socks5://proxy:1080",
+			},
+			dsn: "u:p@a.r.snowflakecomputing.com:443?ocspFailOpen=true&proxy=socks5%3A%2F%2Fproxy%3A1080&region=r&validateDefaultParameters=true",
+		},
+		{
+			cfg: &Config{
+				User:                   "u",
+				Password:               "p",
+				Account:                "a",
+				Region:                 "r",
+				UploadCompressionLevel: 1,
+			},
+			dsn: "u:p@a.r.snowflakecomputing.com:443?ocspFailOpen=true&region=r&uploadCompressionLevel=1&validateDefaultParameters=true",
+		},
+		{
+			cfg: &Config{
+				User:        "u",
+				Password:    "p",
+				Account:     "a",
+				Region:      "r",
+				DialNetwork: "tcp4",
+			},
+			dsn: "u:p@a.r.snowflakecomputing.com:443?dialNetwork=tcp4&ocspFailOpen=true&region=r&validateDefaultParameters=true",
+		},
+		{
+			cfg: &Config{
+				User:                    "u",
+				Password:                "p",
+				Account:                 "a",
+				Region:                  "r",
+				CertRevocationCheckMode: CertRevocationCheckEnabled,
+				CrlCacheValidityTime:    time.Hour,
+				CrlPreloadDir:           "/etc/crls",
+				CrlDownloadDisabled:     true,
+			},
+			dsn: "u:p@a.r.snowflakecomputing.com:443?certRevocationCheckMode=enabled&crlCacheValidityTime=1h0m0s&crlDownloadDisabled=true&crlPreloadDir=%2Fetc%2Fcrls&ocspFailOpen=true&region=r&validateDefaultParameters=true",
+		},
+		{
+			cfg: &Config{
+				User:                    "u",
+				Password:                "p",
+				Account:                 "a",
+				Region:                  "r",
+				CertRevocationCheckMode: CertRevocationCheckAdvisory,
+				CrlMinimumSignatureHash: crypto.SHA384,
+			},
+			dsn: "u:p@a.r.snowflakecomputing.com:443?certRevocationCheckMode=advisory&crlMinimumSignatureHash=SHA-384&ocspFailOpen=true&region=r&validateDefaultParameters=true",
+		},
+		{
+			cfg: &Config{
+				User:                    "u",
+				Password:                "p",
+				Account:                 "a",
+				CrlMinimumSignatureHash: crypto.SHA224,
+			},
+			err: &SnowflakeError{Number: ErrCodeInvalidCrlSetting},
+		},
+		{
+			cfg: &Config{
+				User:                       "u",
+				Password:                   "p",
+				Account:                    "a",
+				Region:                     "r",
+				CertRevocationCheckMode:    CertRevocationCheckEnabled,
+				CrlInMemoryCacheMaxEntries: 10,
+				CrlCheckAllChains:          true,
+				CrlDownloadRetries:         5,
+				CrlDownloadRetryBackoff:    time.Second,
+			},
+			dsn: "u:p@a.r.snowflakecomputing.com:443?certRevocationCheckMode=enabled&crlCheckAllChains=true&crlDownloadRetries=5&crlDownloadRetryBackoff=1s&crlInMemoryCacheMaxEntries=10&ocspFailOpen=true&region=r&validateDefaultParameters=true",
+		},
+		{
+			cfg: &Config{
+				User:                       "u",
+				Password:                   "p",
+				Account:                    "a",
+				CrlInMemoryCacheMaxEntries: -1,
+			},
+			err: &SnowflakeError{Number: ErrCodeInvalidCrlSetting},
+		},
+		{
+			cfg: &Config{
+				User:               "u",
+				Password:           "p",
+				Account:            "a",
+				CrlDownloadRetries: -1,
+			},
+			err: &SnowflakeError{Number: ErrCodeInvalidCrlSetting},
+		},
+		{
+			cfg: &Config{
+				User:                    "u",
+				Password:                "p",
+				Account:                 "a",
+				CrlDownloadRetryBackoff: -time.Second,
+			},
+			err: &SnowflakeError{Number: ErrCodeInvalidCrlSetting},
+		},
+		{
+			cfg: &Config{
+				User:               "u",
+				Password:           "p",
+				Account:            "a",
+				Region:             "r",
+				CredentialCacheDir: "/tmp/snowflake",
+			},
+			dsn: "u:p@a.r.snowflakecomputing.com:443?credentialCacheDir=%2Ftmp%2Fsnowflake&ocspFailOpen=true&region=r&validateDefaultParameters=true",
+		},
+		{
+			cfg: &Config{
+				User:                   "u",
+				Password:               "p",
+				Account:                "a",
+				Region:                 "r",
+				OauthRedirectHost:      "127.0.0.1",
+				OauthRedirectPortRange: "50000-50010",
+			},
+			dsn: "u:p@a.r.snowflakecomputing.com:443?oauthRedirectHost=127.0.0.1&oauthRedirectPortRange=50000-50010&ocspFailOpen=true&region=r&validateDefaultParameters=true",
+		},
+		{
+			cfg: &Config{
+				User:            "u",
+				Password:        "p",
+				Account:         "a",
+				Region:          "r",
+				TLSMinVersion:   tls.VersionTLS13,
+				TLSCipherSuites: []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384},
+			},
+			dsn: "u:p@a.r.snowflakecomputing.com:443?ocspFailOpen=true&region=r&tlsCipherSuites=TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384&tlsMinVersion=1.3&validateDefaultParameters=true",
+		},
+		{
+			cfg: &Config{
+				User:        "u",
+				Password:    "p",
+				Account:     "a",
+				Region:      "r",
+				RootCAsFile: rootCAsFile,
+			},
+			dsn: "u:p@a.r.snowflakecomputing.com:443?ocspFailOpen=true&region=r&rootCAsFile=" + url.QueryEscape(rootCAsFile) + "&validateDefaultParameters=true",
+		},
 		{
 			cfg: &Config{
 				User:                   "u",
@@ -1891,6 +2252,25 @@ func TestDSN(t *testing.T) {
 			},
 			dsn: "u:p@a.b.c.snowflakecomputing.com:443?ocspFailOpen=true&region=b.c&tracing=debug&validateDefaultParameters=true",
 		},
+		{
+			cfg: &Config{
+				User:       "u",
+				Password:   "p",
+				Account:    "a.b.c",
+				JWTIssuer:  "ISS",
+				JWTSubject: "SUB",
+			},
+			dsn: "u:p@a.b.c.snowflakecomputing.com:443?jwtIssuer=ISS&jwtSubject=SUB&ocspFailOpen=true&region=b.c&validateDefaultParameters=true",
+		},
+		{
+			cfg: &Config{
+				User:            "u",
+				Password:        "p",
+				Account:         "a.b.c",
+				ValidationQuery: "SELECT 2",
+			},
+			dsn: "u:p@a.b.c.snowflakecomputing.com:443?ocspFailOpen=true&region=b.c&validateDefaultParameters=true&validationQuery=SELECT+2",
+		},
 		{
 			cfg: &Config{
 				User:                  "u",
@@ -2045,6 +2425,18 @@ func TestDSN(t *testing.T) {
 			},
 			dsn: "u:p@a.b.c.snowflakecomputing.com:443?authenticator=externalbrowser&disableSamlURLCheck=false&ocspFailOpen=true&region=b.c&validateDefaultParameters=true",
 		},
+		{
+			cfg: &Config{
+				User:                      "u",
+				Password:                  "p",
+				Account:                   "a.b.c",
+				Authenticator:             AuthTypeOAuthAuthorizationCode,
+				OauthClientID:             "id",
+				OauthClientSecret:         "secret",
+				DisableOAuthURLValidation: ConfigBoolTrue,
+			},
+			dsn: "u:p@a.b.c.snowflakecomputing.com:443?authenticator=oauth_authorization_code&disableOAuthURLValidation=true&oauthClientId=id&oauthClientSecret=secret&ocspFailOpen=true&region=b.c&validateDefaultParameters=true",
+		},
 	}
 	for _, test := range testcases {
 		t.Run(test.dsn, func(t *testing.T) {
@@ -2053,9 +2445,11 @@ func TestDSN(t *testing.T) {
 				if dsn != test.dsn {
 					t.Errorf("failed to get DSN. expected: %v, got:\n %v", test.dsn, dsn)
 				}
-				_, err := ParseDSN(dsn)
+				cfg, err := ParseDSN(dsn)
 				if err != nil {
 					t.Errorf("failed to parse DSN. dsn: %v, err: %v", dsn, err)
+				} else if !cfg.RootCAs.Equal(test.cfg.RootCAs) || cfg.RootCAsFile != test.cfg.RootCAsFile {
+					t.Errorf("failed to keep the root CAs. dsn: %v", dsn)
 				}
 			}
 			if test.err != nil && err == nil {
@@ -2250,6 +2644,30 @@ func TestExtractAccountName(t *testing.T) {
 	}
 }
 
+func TestParseDSNPrivateLinkHost(t *testing.T) {
+	testcases := map[string]string{
+		"host after @":   "u:p@myaccount.us-east-1.privatelink.snowflakecomputing.com:443/db?account=myaccount&region=us-east-1",
+		"host parameter": "u:p@myaccount/db?region=us-east-1&host=myaccount.us-east-1.privatelink.snowflakecomputing.com",
+	}
+	for name, dsn := range testcases {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := ParseDSN(dsn)
+			assertNilF(t, err)
+			assertEqualE(t, cfg.Host, "myaccount.us-east-1.privatelink.snowflakecomputing.com")
+			assertEqualE(t, cfg.Account, "myaccount")
+			assertEqualE(t, cfg.Region, "us-east-1")
+			assertEqualE(t, cfg.Port, 443)
+
+			roundTrip, err := DSN(cfg)
+			assertNilF(t, err)
+			cfg, err = ParseDSN(roundTrip)
+			assertNilF(t, err)
+			assertEqualE(t, cfg.Host, "myaccount.us-east-1.privatelink.snowflakecomputing.com")
+			assertEqualE(t, cfg.Account, "myaccount")
+		})
+	}
+}
+
 func TestUrlDecodeIfNeeded(t *testing.T) {
 	testcases := map[string]string{
 		"query_tag":             "query_tag",
@@ -0,0 +1,159 @@
+package gosnowflake
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"hash"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+var (
+	oidPBES2          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+	oidAES128CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+// encryptedPrivateKeyInfo is defined in RFC 5208 section 6.
+type encryptedPrivateKeyInfo struct {
+	EncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedData       []byte
+}
+
+// pbes2Params is defined in RFC 8018 appendix A.4.
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+// pbkdf2Params is defined in RFC 8018 appendix A.2.
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// parsePrivateKey parses a DER encoded PKCS#8 private key.
+// If a passphrase is provided, the key is expected to be encrypted.
+func parsePrivateKey(block []byte, passphrase string) (*rsa.PrivateKey, error) {
+	if passphrase != "" {
+		return parseEncryptedPKCS8PrivateKey(block, []byte(passphrase))
+	}
+	return parsePKCS8PrivateKey(block)
+}
+
+// parseEncryptedPKCS8PrivateKey decrypts a DER encoded PKCS#8 EncryptedPrivateKeyInfo
+// protected with PBES2 (PBKDF2 and AES-CBC) and parses the RSA private key inside.
+func parseEncryptedPKCS8PrivateKey(der []byte, passphrase []byte) (*rsa.PrivateKey, error) {
+	var keyInfo encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(der, &keyInfo); err != nil {
+		return nil, errPrivateKeyParse("Error decoding encrypted private key using PKCS8. %v", err)
+	}
+	if !keyInfo.EncryptionAlgorithm.Algorithm.Equal(oidPBES2) {
+		return nil, errPrivateKeyParse("unsupported private key encryption algorithm %v. Only PBES2 is supported", keyInfo.EncryptionAlgorithm.Algorithm)
+	}
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(keyInfo.EncryptionAlgorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, errPrivateKeyParse("failed to parse PBES2 parameters. %v", err)
+	}
+	keyLen, err := aesKeyLength(params.EncryptionScheme.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	var iv []byte
+	if _, err = asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, errPrivateKeyParse("failed to parse encryption scheme IV. %v", err)
+	}
+	key, err := deriveKey(params.KeyDerivationFunc, passphrase, keyLen)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != block.BlockSize() || len(keyInfo.EncryptedData) == 0 || len(keyInfo.EncryptedData)%block.BlockSize() != 0 {
+		return nil, errPrivateKeyParse("invalid encrypted private key data")
+	}
+	decrypted := make([]byte, len(keyInfo.EncryptedData))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(decrypted, keyInfo.EncryptedData)
+	decrypted, err = paddingTrim(decrypted)
+	if err != nil {
+		return nil, errIncorrectPrivateKeyPassphrase()
+	}
+	privKey, err := x509.ParsePKCS8PrivateKey(decrypted)
+	if err != nil {
+		return nil, errIncorrectPrivateKeyPassphrase()
+	}
+	rsaKey, ok := privKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errPrivateKeyParse("interface convertion. expected type *rsa.PrivateKey, but got %T", privKey)
+	}
+	return rsaKey, nil
+}
+
+func deriveKey(kdf pkix.AlgorithmIdentifier, passphrase []byte, keyLen int) ([]byte, error) {
+	if !kdf.Algorithm.Equal(oidPBKDF2) {
+		return nil, errPrivateKeyParse("unsupported key derivation function %v. Only PBKDF2 is supported", kdf.Algorithm)
+	}
+	var params pbkdf2Params
+	if _, err := asn1.Unmarshal(kdf.Parameters.FullBytes, &params); err != nil {
+		return nil, errPrivateKeyParse("failed to parse PBKDF2 parameters. %v", err)
+	}
+	var hashFunc func() hash.Hash
+	switch {
+	case len(params.PRF.Algorithm) == 0, params.PRF.Algorithm.Equal(oidHMACWithSHA1):
+		hashFunc = sha1.New
+	case params.PRF.Algorithm.Equal(oidHMACWithSHA256):
+		hashFunc = sha256.New
+	case params.PRF.Algorithm.Equal(oidHMACWithSHA512):
+		hashFunc = sha512.New
+	default:
+		return nil, errPrivateKeyParse("unsupported PBKDF2 pseudorandom function %v", params.PRF.Algorithm)
+	}
+	if params.KeyLength != 0 && params.KeyLength != keyLen {
+		return nil, errPrivateKeyParse("PBKDF2 key length %v doesn't match the cipher key length %v", params.KeyLength, keyLen)
+	}
+	return pbkdf2.Key(passphrase, params.Salt, params.IterationCount, keyLen, hashFunc), nil
+}
+
+func aesKeyLength(oid asn1.ObjectIdentifier) (int, error) {
+	switch {
+	case oid.Equal(oidAES128CBC):
+		return 16, nil
+	case oid.Equal(oidAES192CBC):
+		return 24, nil
+	case oid.Equal(oidAES256CBC):
+		return 32, nil
+	}
+	return 0, errPrivateKeyParse("unsupported private key cipher %v. Only AES-CBC is supported", oid)
+}
+
+func errPrivateKeyParse(format string, args ...interface{}) *SnowflakeError {
+	return &SnowflakeError{
+		Number:  ErrCodePrivateKeyParseError,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+func errIncorrectPrivateKeyPassphrase() *SnowflakeError {
+	return &SnowflakeError{
+		Number:  ErrCodePrivateKeyParseError,
+		Message: "failed to decrypt private key. the passphrase may be incorrect",
+	}
+}
@@ -0,0 +1,70 @@
+package gosnowflake
+
+import (
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+const encryptedPrivateKeyPassphrase = "test-passphrase" // pragma: allowlist secret
+
+func readEncryptedPrivateKey(t *testing.T) []byte {
+	pemBytes, err := os.ReadFile("test_data/rsa_key_encrypted.p8")
+	assertNilF(t, err)
+	block, _ := pem.Decode(pemBytes)
+	assertNotNilF(t, block)
+	assertEqualF(t, block.Type, "ENCRYPTED PRIVATE KEY")
+	return block.Bytes
+}
+
+func TestParseEncryptedPKCS8PrivateKey(t *testing.T) {
+	der := readEncryptedPrivateKey(t)
+
+	t.Run("correct passphrase", func(t *testing.T) {
+		key, err := parseEncryptedPKCS8PrivateKey(der, []byte(encryptedPrivateKeyPassphrase))
+		assertNilF(t, err)
+		assertNilF(t, key.Validate())
+		token, err := prepareJWTToken(&Config{Account: "a", User: "u", PrivateKey: key, JWTExpireTimeout: defaultJWTTimeout})
+		assertNilF(t, err)
+		assertNotEqualE(t, token, "")
+	})
+
+	t.Run("wrong passphrase", func(t *testing.T) {
+		_, err := parseEncryptedPKCS8PrivateKey(der, []byte("wrong-passphrase"))
+		assertNotNilF(t, err)
+		var se *SnowflakeError
+		assertTrueF(t, errors.As(err, &se))
+		assertEqualE(t, se.Number, ErrCodePrivateKeyParseError)
+		assertStringContainsE(t, se.Message, "passphrase")
+	})
+
+	t.Run("not encrypted key", func(t *testing.T) {
+		unencrypted, err := marshalPKCS8PrivateKey(testPrivKey)
+		assertNilF(t, err)
+		_, err = parseEncryptedPKCS8PrivateKey(unencrypted, []byte(encryptedPrivateKeyPassphrase))
+		assertNotNilF(t, err)
+	})
+}
+
+func TestParseDSNWithEncryptedPrivateKey(t *testing.T) {
+	encodedKey := base64.URLEncoding.EncodeToString(readEncryptedPrivateKey(t))
+
+	for _, dsn := range []string{
+		fmt.Sprintf("u:p@a?authenticator=SNOWFLAKE_JWT&privateKey=%v&privateKeyPwd=%v", encodedKey, encryptedPrivateKeyPassphrase),
+		fmt.Sprintf("u:p@a?authenticator=SNOWFLAKE_JWT&privateKeyPwd=%v&privateKey=%v", encryptedPrivateKeyPassphrase, encodedKey),
+	} {
+		cfg, err := ParseDSN(dsn)
+		assertNilF(t, err)
+		assertNotNilF(t, cfg.PrivateKey)
+		assertEqualE(t, cfg.PrivateKeyPassphrase, encryptedPrivateKeyPassphrase)
+	}
+
+	_, err := ParseDSN(fmt.Sprintf("u:p@a?authenticator=SNOWFLAKE_JWT&privateKey=%v&privateKeyPwd=wrong", encodedKey))
+	assertNotNilF(t, err)
+	var se *SnowflakeError
+	assertTrueF(t, errors.As(err, &se))
+	assertEqualE(t, se.Number, ErrCodePrivateKeyParseError)
+}
@@ -1,6 +1,8 @@
 package gosnowflake
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"runtime/debug"
 	"strconv"
@@ -15,6 +17,10 @@ type SnowflakeError struct {
 	Message        string
 	MessageArgs    []interface{}
 	IncludeQueryID bool // TODO: populate this in connection
+
+	// StatementNumber is the 1-based position of the failed statement
+	// in a multi-statement request. It is 0 for single statements.
+	StatementNumber int
 }
 
 func (se *SnowflakeError) Error() string {
@@ -22,6 +28,9 @@ func (se *SnowflakeError) Error() string {
 	if len(se.MessageArgs) > 0 {
 		message = fmt.Sprintf(se.Message, se.MessageArgs...)
 	}
+	if se.StatementNumber > 0 {
+		message = fmt.Sprintf("statement %d: %s", se.StatementNumber, message)
+	}
 	if se.SQLState != "" {
 		if se.IncludeQueryID {
 			return fmt.Sprintf("%06d (%s): %s: %s", se.Number, se.SQLState, se.QueryID, message)
@@ -34,6 +43,76 @@ func (se *SnowflakeError) Error() string {
 	return fmt.Sprintf("%06d: %s", se.Number, message)
 }
 
+// BrowserAuthTimeoutError is returned when browser based authentication
+// is not completed within Config.ExternalBrowserTimeout.
+type BrowserAuthTimeoutError struct {
+	AuthType AuthType
+}
+
+func (e *BrowserAuthTimeoutError) Error() string {
+	if e.AuthType == AuthTypeExternalBrowser {
+		return "authentication timed out"
+	}
+	return "authentication via browser timed out"
+}
+
+// BrowserAuthCancelledError is returned when the user ends browser based authentication
+// without completing it, e.g. by denying access in the identity provider.
+type BrowserAuthCancelledError struct {
+	AuthType    AuthType
+	Reason      string // error code reported to the redirect URI, e.g. access_denied
+	Description string // optional error description reported to the redirect URI
+}
+
+func (e *BrowserAuthCancelledError) Error() string {
+	msg := fmt.Sprintf("authentication via browser was cancelled by the user. authenticator: %v, reason: %v", e.AuthType, e.Reason)
+	if e.Description != "" {
+		msg += ". Details: " + e.Description
+	}
+	return msg
+}
+
+// QueryResultExpiredError is returned when the result of a query fetched by its ID
+// is no longer available on the server. The query has to be run again.
+type QueryResultExpiredError struct {
+	QueryID string
+	Err     error // error reported by the server
+}
+
+func (e *QueryResultExpiredError) Error() string {
+	return fmt.Sprintf("result of query %v has expired: %v", e.QueryID, e.Err)
+}
+
+func (e *QueryResultExpiredError) Unwrap() error {
+	return e.Err
+}
+
+// BindError is returned when a bind parameter cannot be converted to a Snowflake value.
+// Its message starts with the message of the underlying error.
+type BindError struct {
+	Position      int    // 1-based position of the parameter
+	Name          string // name of the parameter, empty for positional parameters
+	GoType        string // Go type of the supplied value
+	SnowflakeType string // Snowflake type the value was converted to, empty if unknown
+	Err           error
+}
+
+func (e *BindError) Error() string {
+	msg := fmt.Sprintf("%v (bind parameter %v", e.Err, e.Position)
+	if e.Name != "" {
+		msg += fmt.Sprintf(" %q", e.Name)
+	}
+	msg += fmt.Sprintf(", Go type %v", e.GoType)
+	if e.SnowflakeType != "" {
+		msg += fmt.Sprintf(", Snowflake type %v", e.SnowflakeType)
+	}
+	return msg + ")"
+}
+
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
+
 func (se *SnowflakeError) generateTelemetryExceptionData() *telemetryData {
 	data := &telemetryData{
 		Message: map[string]string{
@@ -78,6 +157,19 @@ func (se *SnowflakeError) exceptionTelemetry(sc *snowflakeConn) *SnowflakeError
 	return se
 }
 
+// statementExceptionTelemetry is exceptionTelemetry for the errors of a statement run with the context.
+// The event also carries the query tag of the statement.
+func (se *SnowflakeError) statementExceptionTelemetry(ctx context.Context, sc *snowflakeConn) *SnowflakeError {
+	data := se.generateTelemetryExceptionData()
+	if sc != nil {
+		sc.addQueryTag(ctx, data)
+	}
+	if err := se.sendExceptionTelemetry(sc, data); err != nil {
+		logger.WithContext(sc.ctx).Debugf("failed to log to telemetry: %v", data)
+	}
+	return se
+}
+
 // return populated error fields replacing the default response
 func populateErrorFields(code int, data *execResponse) *SnowflakeError {
 	err := errUnknownError()
@@ -104,6 +196,7 @@ const (
 	sessionExpiredCode          = "390112"
 	invalidOAuthAccessTokenCode = "390303"
 	expiredOAuthAccessTokenCode = "390318"
+	invalidJWTTokenCode         = "390144"
 )
 
 // Driver return errors
@@ -148,6 +241,30 @@ const (
 	ErrCodeEmptyOAuthParameters = 260017
 	// ErrMissingAccessATokenButRefreshTokenPresent is an error code for the case when access token is not found in cache, but the refresh token is present.
 	ErrMissingAccessATokenButRefreshTokenPresent = 260018
+	// ErrCodeNoFreePortInRange is an error code for the case where none of the ports in the configured OAuth redirect port range is free.
+	ErrCodeNoFreePortInRange = 260019
+	// ErrCodeInvalidTLSSetting is an error code for the case where the TLS minimum version or a cipher suite is invalid.
+	ErrCodeInvalidTLSSetting = 260020
+	// ErrCodeFailedToLoadRootCAs is an error code for the case where the PEM bundle of root CAs can't be read or contains no certificates.
+	ErrCodeFailedToLoadRootCAs = 260021
+	// ErrCodeInvalidScanType is an error code for the case where an unsupported Go type is configured for numeric columns.
+	ErrCodeInvalidScanType = 260022
+	// ErrCodeInvalidTimezone is an error code for the case where the time zone is not a known IANA time zone.
+	ErrCodeInvalidTimezone = 260024
+	// ErrCodeInvalidCompressionLevel is an error code for the case where the upload compression level is not a gzip level.
+	ErrCodeInvalidCompressionLevel = 260025
+	// ErrCodeInvalidProxy is an error code for the case where the proxy is not a URL of a supported proxy.
+	ErrCodeInvalidProxy = 260026
+	// ErrCodeConflictingAuthOption is an error code for the case where a credential is set that the authenticator does not use.
+	ErrCodeConflictingAuthOption = 260027
+	// ErrCodeInvalidAccountIdentifier is an error code for the case where the account identifier is malformed.
+	ErrCodeInvalidAccountIdentifier = 260028
+	// ErrCodeInvalidDialNetwork is an error code for the case where the dial network is not tcp, tcp4 or tcp6.
+	ErrCodeInvalidDialNetwork = 260029
+	// ErrCodeInvalidCertRevocationCheckMode is an error code for the case where the certificate revocation check mode is unknown.
+	ErrCodeInvalidCertRevocationCheckMode = 260030
+	// ErrCodeInvalidCrlSetting is an error code for the case where a setting of the CRL based revocation check is invalid.
+	ErrCodeInvalidCrlSetting = 260031
 
 	/* network */
 
@@ -173,6 +290,8 @@ const (
 	ErrFailedToGetExternalBrowserResponse = 261009
 	// ErrFailedToHeartbeat is an error code when a heartbeat fails.
 	ErrFailedToHeartbeat = 261010
+	// ErrPasswordChangeRequired is an error code for the case where the login was rejected because the password of the user expired.
+	ErrPasswordChangeRequired = 261011
 
 	/* rows */
 
@@ -182,12 +301,26 @@ const (
 	ErrNonArrowResponseInArrowBatches = 262001
 	// ErrNilArrowStreamBatch is an error code for when ArrowStreamBatch or its scd field is nil
 	ErrNilArrowStreamBatch = 262002
+	// ErrArrowBatchesNotEnabled is an error code for the case where arrow records are requested, but the query was not run with WithArrowBatches
+	ErrArrowBatchesNotEnabled = 262003
+	// ErrNoCurrentRow is an error code for the case where a column value is requested before a row was read with Next
+	ErrNoCurrentRow = 262004
+	// ErrColumnNotFound is an error code for the case where the result has no column of the requested name
+	ErrColumnNotFound = 262005
+	// ErrAmbiguousColumnName is an error code for the case where the requested name matches more than one column case-insensitively
+	ErrAmbiguousColumnName = 262006
+	// ErrNoGeneratedKey is an error code for the case where a statement run with WithGeneratedKey did not return a single integer value
+	ErrNoGeneratedKey = 262007
+	// ErrShowNotPageable is an error code for the case where ShowAll cannot continue a SHOW command from the name of its last row
+	ErrShowNotPageable = 262008
+	// ErrShowPaginationNoProgress is an error code for the case where a page of ShowAll returned no row after the one it continued from
+	ErrShowPaginationNoProgress = 262009
 
 	/* transaction*/
 
-	// ErrNoReadOnlyTransaction is an error code for the case where readonly mode is specified.
+	// ErrNoReadOnlyTransaction is an error code for the case where a statement that may modify data is run in a read-only transaction.
 	ErrNoReadOnlyTransaction = 263000
-	// ErrNoDefaultTransactionIsolationLevel is an error code for the case where non default isolation level is specified.
+	// ErrNoDefaultTransactionIsolationLevel is an error code for the case where an isolation level other than READ COMMITTED is specified.
 	ErrNoDefaultTransactionIsolationLevel = 263001
 
 	/* file transfer */
@@ -216,6 +349,8 @@ const (
 	ErrNotImplemented = 264011
 	// ErrInvalidPadding is an error code denoting the invalid padding of decryption key
 	ErrInvalidPadding = 264012
+	// ErrInvalidFileStreamLength is an error code denoting a negative length given to WithFileStreamLength
+	ErrInvalidFileStreamLength = 264013
 
 	/* binding */
 
@@ -249,6 +384,14 @@ const (
 	ErrNullValueInArray = 268004
 	// ErrNullValueInMap is an error code for the case where there are null values in a map without mapValuesNullable set to true
 	ErrNullValueInMap = 268005
+	// ErrVectorDimensionMismatch is an error code for the case where a VECTOR value has a different number of elements than the declared dimension
+	ErrVectorDimensionMismatch = 268006
+	// ErrInvalidBinaryBase64Form is an error code for the case where a binary data in base64 form is invalid.
+	ErrInvalidBinaryBase64Form = 268007
+	// ErrInvalidBinaryEncoding is an error code for the case where an unknown BinaryEncoding is used.
+	ErrInvalidBinaryEncoding = 268008
+	// ErrInvalidEWKB is an error code for the case where a GEOGRAPHY or GEOMETRY value is not valid EWKB.
+	ErrInvalidEWKB = 268009
 
 	/* OCSP */
 
@@ -271,6 +414,8 @@ const (
 	ErrQueryReportedError = 279201
 	// ErrQueryIsRunning the query is still running
 	ErrQueryIsRunning = 279301
+	// ErrWarehouseSuspended the query was aborted because its warehouse is suspended, see Config.FailIfWarehouseSuspended
+	ErrWarehouseSuspended = 279401
 
 	/* GS error code */
 
@@ -280,14 +425,97 @@ const (
 	ErrRoleNotExist = 390189
 	// ErrObjectNotExistOrAuthorized is a GS error code for the case that the server-side object specified does not exist
 	ErrObjectNotExistOrAuthorized = 390201
+	// ErrResultExpired is a GS error code for the case that the result of the query is no longer available
+	ErrResultExpired = 612
+	// ErrCodeQueryCancelled is a GS error code for the case that the execution of the query was cancelled
+	ErrCodeQueryCancelled = 604
+	// ErrCodeQueryTimeout is a GS error code for the case that the query reached the statement or warehouse timeout
+	ErrCodeQueryTimeout = 630
+	// ErrCodeSessionExpired is a GS error code for the case that the session token has expired
+	ErrCodeSessionExpired = 390112
+	// ErrCodeUserLocked is a GS error code for the case that the user is temporarily locked after too many failed logins
+	ErrCodeUserLocked = 390102
+	// ErrCodeUsernameMismatch is a GS error code for the case that the user of an OAuth access token differs from the user in the config
+	ErrCodeUsernameMismatch = 390309
 )
 
+// IsSessionExpired tells whether the error is a SnowflakeError reporting an expired session.
+func IsSessionExpired(err error) bool {
+	return hasErrorNumber(err, ErrCodeSessionExpired)
+}
+
+// IsQueryCancelled tells whether the error is a SnowflakeError reporting a cancelled query.
+func IsQueryCancelled(err error) bool {
+	return hasErrorNumber(err, ErrCodeQueryCancelled)
+}
+
+// IsQueryTimeout tells whether the error is a SnowflakeError reporting a query that reached its timeout.
+func IsQueryTimeout(err error) bool {
+	return hasErrorNumber(err, ErrCodeQueryTimeout)
+}
+
+// IsWarehouseSuspended tells whether the error is a SnowflakeError reporting a query that was
+// aborted instead of waiting for its suspended warehouse to resume.
+func IsWarehouseSuspended(err error) bool {
+	return hasErrorNumber(err, ErrWarehouseSuspended)
+}
+
+// IsUsernameMismatch tells whether the error is a SnowflakeError reporting that the user
+// of an OAuth access token differs from the configured user.
+func IsUsernameMismatch(err error) bool {
+	return hasErrorNumber(err, ErrCodeUsernameMismatch)
+}
+
+// IsUserLocked tells whether the error is a SnowflakeError reporting that the login was
+// rejected because the user is temporarily locked.
+func IsUserLocked(err error) bool {
+	return hasErrorNumber(err, ErrCodeUserLocked)
+}
+
+// IsPasswordChangeRequired tells whether the error is a SnowflakeError reporting that the login
+// was rejected because the password of the user expired and must be changed.
+func IsPasswordChangeRequired(err error) bool {
+	return hasErrorNumber(err, ErrPasswordChangeRequired)
+}
+
+// IsRoleNotExist tells whether the error is a SnowflakeError reporting that the role does not exist.
+func IsRoleNotExist(err error) bool {
+	return hasErrorNumber(err, ErrRoleNotExist)
+}
+
+// IsObjectNotExistOrAuthorized tells whether the error is a SnowflakeError reporting
+// that the server-side object does not exist or is not authorized.
+func IsObjectNotExistOrAuthorized(err error) bool {
+	return hasErrorNumber(err, ErrObjectNotExistOrAuthorized)
+}
+
+func hasErrorNumber(err error, number int) bool {
+	var se *SnowflakeError
+	return errors.As(err, &se) && se.Number == number
+}
+
 const (
 	errMsgFailedToParseHost                  = "failed to parse a host name. host: %v"
 	errMsgFailedToParsePort                  = "failed to parse a port number. port: %v"
+	errMsgFailedToParsePortRange             = "failed to parse a port range. expected <from>-<to>, got: %v"
+	errMsgNoFreePortInRange                  = "no free port found in range %v on host %v"
+	errMsgInvalidTLSMinVersion               = "invalid TLS minimum version: %v. expected one of 1.0, 1.1, 1.2, 1.3"
+	errMsgInvalidTLSCipherSuite              = "unsupported TLS cipher suite: %v"
+	errMsgFailedToLoadRootCAs                = "failed to load root CAs from %v: %v"
+	errMsgInvalidScanType                    = "invalid %v: %v. expected %v or %v"
+	errMsgInvalidTimezone                    = "invalid time zone: %v. %v"
+	errMsgInvalidProxy                       = "invalid proxy: %v. expected a http, https, socks5 or socks5h URL"
+	errMsgConflictingAuthOption              = "%v cannot be used with authenticator %v"
+	errMsgInvalidAccountIdentifier           = "invalid account identifier: %v. expected org-account, account.region or account.region.cloud"
+	errMsgInvalidCompressionLevel            = "invalid upload compression level: %v. expected 1 to 9, or 0 for the default level"
+	errMsgInvalidDialNetwork                 = "invalid dial network: %v. expected tcp, tcp4 or tcp6"
+	errMsgInvalidCertRevocationCheckMode     = "invalid certificate revocation check mode: %v. expected disabled, advisory or enabled"
+	errMsgInvalidCrlMinimumSignatureHash     = "invalid CRL minimum signature hash: %v. expected one of MD5, SHA-1, SHA-256, SHA-384, SHA-512"
+	errMsgNegativeCrlSetting                 = "invalid %v: %v. expected a non-negative value, or 0 for the default"
 	errMsgFailedToParseAuthenticator         = "failed to parse an authenticator: %v"
 	errMsgInvalidOffsetStr                   = "offset must be a string consist of sHHMI where one sign character '+'/'-' followed by zero filled hours and minutes: %v"
 	errMsgInvalidByteArray                   = "invalid byte array: %v"
+	errMsgInvalidBinaryEncoding              = "invalid binary encoding: %v"
 	errMsgIdpConnectionError                 = "failed to verify URLs. authenticator: %v, token URL:%v, SSO URL:%v"
 	errMsgSSOURLNotMatch                     = "SSO URL didn't match. expected: %v, got: %v"
 	errMsgFailedToGetChunk                   = "failed to get a chunk of result sets. idx: %v"
@@ -299,11 +527,12 @@ const (
 	errMsgFailedToAuth                       = "failed to auth for unknown reason. HTTP: %v, URL: %v"
 	errMsgFailedToAuthSAML                   = "failed to auth via SAML for unknown reason. HTTP: %v, URL: %v"
 	errMsgFailedToAuthOKTA                   = "failed to auth via OKTA for unknown reason. HTTP: %v, URL: %v"
+	errMsgPasswordChangeRequired             = "the password of user %v must be changed. code: %v, message: %v"
 	errMsgFailedToGetSSO                     = "failed to auth via OKTA for unknown reason. HTTP: %v, URL: %v"
 	errMsgFailedToParseResponse              = "failed to parse a response from Snowflake. Response: %v"
 	errMsgFailedToGetExternalBrowserResponse = "failed to get an external browser response from Snowflake, err: %s"
-	errMsgNoReadOnlyTransaction              = "no readonly mode is supported"
-	errMsgNoDefaultTransactionIsolationLevel = "no default isolation transaction level is supported"
+	errMsgNoReadOnlyTransaction              = "%v statements are not allowed in a read-only transaction"
+	errMsgNoDefaultTransactionIsolationLevel = "isolation level %v is not supported, only READ COMMITTED is supported"
 	errMsgServiceUnavailable                 = "service is unavailable. check your connectivity. you may need a proxy server. HTTP: %v, URL: %v"
 	errMsgFailedToConnect                    = "failed to connect to db. verify account name is correct. HTTP: %v, URL: %v"
 	errMsgOCSPStatusRevoked                  = "OCSP revoked: reason:%v, at:%v"
@@ -311,17 +540,21 @@ const (
 	errMsgOCSPInvalidValidity                = "invalid validity: producedAt: %v, thisUpdate: %v, nextUpdate: %v"
 	errMsgOCSPNoOCSPResponderURL             = "no OCSP server is attached to the certificate. %v"
 	errMsgBindColumnMismatch                 = "column %v has a different number of binds (%v) than column 1 (%v)"
+	errMsgArrowRecordColumnMismatch          = "arrow record has %v columns, but the statement has %v bind placeholders"
+	errMsgUnsupportedArrowBindType           = "unsupported arrow type for binding: %v"
 	errMsgNotImplemented                     = "not implemented"
 	errMsgFeatureNotSupported                = "feature is not supported: %v"
 	errMsgCommandNotRecognized               = "%v command not recognized"
 	errMsgLocalPathNotDirectory              = "the local path is not a directory: %v"
 	errMsgFileNotExists                      = "file does not exist: %v"
 	errMsgFailToReadDataFromBuffer           = "failed to read data from buffer. err: %v"
+	errMsgInvalidFileStreamLength            = "invalid file stream length: %v. it must not be negative"
 	errMsgInvalidStageFs                     = "destination location type is not valid: %v"
 	errMsgInternalNotMatchEncryptMaterial    = "number of downloading files doesn't match the encryption materials. files=%v, encmat=%v"
 	errMsgFailedToConvertToS3Client          = "failed to convert interface to s3 client"
 	errMsgNoResultIDs                        = "no result IDs returned with the multi-statement query"
 	errMsgQueryStatus                        = "server ErrorCode=%s, ErrorMessage=%s"
+	errMsgWarehouseSuspended                 = "the query was aborted because its warehouse is suspended and is being resumed"
 	errMsgInvalidPadding                     = "invalid padding on input"
 	errMsgClientConfigFailed                 = "client configuration failed: %v"
 	errMsgNullValueInArray                   = "for handling null values in arrays use WithArrayValuesNullable(ctx)"
@@ -331,6 +564,15 @@ const (
 	errMsgInvalidWritablePermissionToFile    = "file '%v' is writable by group or others — this poses a security risk because it allows unauthorized users to modify sensitive settings. Your Permission: %v"
 	errMsgInvalidExecutablePermissionToFile  = "file '%v' is executable — this poses a security risk because the file could be misused as a script or executed unintentionally. Your Permission: %v"
 	errMsgNonArrowResponseInArrowBatches     = "arrow batches enabled, but the response is not Arrow based"
+	errMsgArrowBatchesNotEnabled             = "arrow batches are not enabled. run the query with WithArrowBatches(ctx)"
+	errMsgVectorDimensionMismatch            = "vector has %v elements, but its declared dimension is %v"
+	errMsgNoCurrentRow                       = "no row has been read. call Next before getting a column value"
+	errMsgColumnNotFound                     = "column %v not found in the result"
+	errMsgAmbiguousColumnName                = "column name %v matches more than one column: %v"
+	errMsgNoGeneratedKey                     = "the statement did not return a generated key as a single integer value: %v"
+	errMsgShowNotPageable                    = "cannot paginate %v: %v"
+	errMsgShowPaginationNoProgress           = "SHOW pagination made no progress from name %v"
+	errMsgInvalidEWKB                        = "invalid EWKB value: %v"
 )
 
 // Returned if a DNS doesn't include account parameter.
@@ -372,6 +614,24 @@ func errEmptyOAuthParameters() *SnowflakeError {
 	}
 }
 
+// Returned if a credential is set that the authenticator does not use.
+func errConflictingAuthOption(option string, authenticator AuthType) *SnowflakeError {
+	return &SnowflakeError{
+		Number:      ErrCodeConflictingAuthOption,
+		Message:     errMsgConflictingAuthOption,
+		MessageArgs: []any{option, authenticator},
+	}
+}
+
+// Returned if an account identifier is malformed.
+func errInvalidAccountIdentifier(accountIdentifier string) *SnowflakeError {
+	return &SnowflakeError{
+		Number:      ErrCodeInvalidAccountIdentifier,
+		Message:     errMsgInvalidAccountIdentifier,
+		MessageArgs: []any{accountIdentifier},
+	}
+}
+
 // Returned if a DSN's implicit region from account parameter and explicit region parameter conflict.
 func errRegionConflict() *SnowflakeError {
 	return &SnowflakeError{
@@ -388,6 +648,14 @@ func errFailedToParseAuthenticator() *SnowflakeError {
 	}
 }
 
+func errInvalidTLSCipherSuite(name string) *SnowflakeError {
+	return &SnowflakeError{
+		Number:      ErrCodeInvalidTLSSetting,
+		Message:     errMsgInvalidTLSCipherSuite,
+		MessageArgs: []interface{}{name},
+	}
+}
+
 // Returned if the server side returns an error without meaningful message.
 func errUnknownError() *SnowflakeError {
 	return &SnowflakeError{
@@ -405,6 +673,14 @@ func errNullValueInArray() *SnowflakeError {
 	}
 }
 
+func errVectorDimensionMismatch(elements int, dimension int) *SnowflakeError {
+	return &SnowflakeError{
+		Number:      ErrVectorDimensionMismatch,
+		Message:     errMsgVectorDimensionMismatch,
+		MessageArgs: []interface{}{elements, dimension},
+	}
+}
+
 func errNullValueInMap() *SnowflakeError {
 	return &SnowflakeError{
 		Number:  ErrNullValueInMap,
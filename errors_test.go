@@ -1,6 +1,8 @@
 package gosnowflake
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
 )
@@ -77,3 +79,31 @@ func TestErrorMessage(t *testing.T) {
 		t.Errorf("failed to format error. %v", e)
 	}
 }
+
+func TestErrorPredicates(t *testing.T) {
+	predicates := map[string]struct {
+		predicate func(error) bool
+		number    int
+	}{
+		"IsSessionExpired":             {IsSessionExpired, ErrCodeSessionExpired},
+		"IsQueryCancelled":             {IsQueryCancelled, ErrCodeQueryCancelled},
+		"IsQueryTimeout":               {IsQueryTimeout, ErrCodeQueryTimeout},
+		"IsUsernameMismatch":           {IsUsernameMismatch, ErrCodeUsernameMismatch},
+		"IsRoleNotExist":               {IsRoleNotExist, ErrRoleNotExist},
+		"IsObjectNotExistOrAuthorized": {IsObjectNotExistOrAuthorized, ErrObjectNotExistOrAuthorized},
+	}
+	for name, tc := range predicates {
+		t.Run(name, func(t *testing.T) {
+			err := &SnowflakeError{Number: tc.number}
+			assertTrueE(t, tc.predicate(err))
+			assertTrueE(t, tc.predicate(fmt.Errorf("wrapped: %w", err)))
+			for otherName, other := range predicates {
+				if otherName != name {
+					assertFalseE(t, tc.predicate(&SnowflakeError{Number: other.number}), otherName)
+				}
+			}
+			assertFalseE(t, tc.predicate(errors.New("plain error")))
+			assertFalseE(t, tc.predicate(nil))
+		})
+	}
+}
@@ -78,6 +78,18 @@ func (rs resultStatus) isSet() bool {
 	return uploaded <= rs && rs <= needRetryWithLowerConcurrency
 }
 
+// FileTransferResult describes a file transferred by a PUT or GET command, one per row of its result.
+type FileTransferResult struct {
+	Source            string // path of the uploaded local file. Empty for GET
+	Target            string // name of the file in the stage for PUT or of the downloaded local file for GET
+	SourceSize        int64  // size of the local file before compression. 0 for GET
+	TargetSize        int64  // size of the file in the stage for PUT or of the downloaded file for GET
+	SourceCompression string // compression of the local file, e.g. NONE or GZIP. Empty for GET
+	TargetCompression string // compression of the file in the stage. Empty for GET
+	Status            string // UPLOADED, DOWNLOADED, SKIPPED or ERROR
+	Message           string // details of the error when Status is ERROR
+}
+
 // SnowflakeFileTransferOptions enables users to specify options regarding
 // files transfers such as PUT/GET
 type SnowflakeFileTransferOptions struct {
@@ -95,11 +107,43 @@ type SnowflakeFileTransferOptions struct {
 	putCallback             *snowflakeProgressPercentage
 	putAzureCallback        *snowflakeProgressPercentage
 	putCallbackOutputStream *io.Writer
+	// SkipClientSideEncryptionForSSE uploads files without client-side encryption
+	// when the stage reports that it enforces server-side encryption, e.g. AWS_SSE_KMS.
+	SkipClientSideEncryptionForSSE bool
+	// Overwrite, AutoCompress and SourceCompression override the OVERWRITE, AUTO_COMPRESS
+	// and SOURCE_COMPRESSION options of the PUT command when set.
+	Overwrite         ConfigBool
+	AutoCompress      ConfigBool
+	SourceCompression string
 
 	/* GET */
 	getCallback             *snowflakeProgressPercentage
 	getAzureCallback        *snowflakeProgressPercentage
 	getCallbackOutputStream *io.Writer
+	// GetDestinationCallback is called with the stage name of every file matched by GET.
+	// It returns the local path the file is downloaded to, relative paths being resolved
+	// against the GET target directory and an empty path keeping the default one,
+	// or skip set to true to not download the file.
+	GetDestinationCallback func(stageFileName string) (localPath string, skip bool)
+	// GetResults receives the result of every file downloaded by GET as soon as it finishes,
+	// so the files can be processed while the others are still downloading.
+	// The channel must be read concurrently with the GET or be large enough to hold all results,
+	// it is not closed by the driver.
+	GetResults chan<- GetFileResult
+	// CreateGetTargetDirectory makes GET create its local target directory, and the missing
+	// parent directories, instead of failing with ErrLocalPathNotDirectory when it does not exist.
+	CreateGetTargetDirectory bool
+	// GetDirectoryPerm is the permission of the directories created by GET before the umask,
+	// for the target directory and for the directories returned by GetDestinationCallback.
+	// It defaults to os.ModePerm.
+	GetDirectoryPerm os.FileMode
+}
+
+// GetFileResult is the outcome of downloading a single file by GET.
+type GetFileResult struct {
+	Name  string // name of the downloaded file
+	Bytes int64  // size of the downloaded file
+	Err   error  // nil if the file was downloaded
 }
 
 type snowflakeFileTransferAgent struct {
@@ -148,14 +192,6 @@ func (sfa *snowflakeFileTransferAgent) execute() error {
 		return err
 	}
 
-	if sfa.commandType == downloadCommand {
-		if _, err = os.Stat(sfa.localLocation); os.IsNotExist(err) {
-			if err = os.MkdirAll(sfa.localLocation, os.ModePerm); err != nil {
-				return err
-			}
-		}
-	}
-
 	if sfa.stageLocationType == local {
 		if _, err = os.Stat(sfa.stageInfo.Location); os.IsNotExist(err) {
 			if err = os.MkdirAll(sfa.stageInfo.Location, os.ModePerm); err != nil {
@@ -234,6 +270,15 @@ func (sfa *snowflakeFileTransferAgent) parseCommand() error {
 		}
 		sfa.autoCompress = sfa.data.AutoCompress
 		sfa.srcCompression = strings.ToLower(sfa.data.SourceCompression)
+		if sfa.options != nil && sfa.options.AutoCompress != configBoolNotSet {
+			sfa.autoCompress = sfa.options.AutoCompress == ConfigBoolTrue
+		}
+		if sfa.options != nil && sfa.options.SourceCompression != "" {
+			sfa.srcCompression = strings.ToLower(sfa.options.SourceCompression)
+		}
+		if sfa.srcCompression == "" {
+			sfa.srcCompression = "auto_detect" // the default of SOURCE_COMPRESSION
+		}
 	} else {
 		sfa.srcFiles = sfa.srcLocations
 		sfa.srcFileToEncryptionMaterial = make(map[string]*snowflakeFileEncryption)
@@ -255,7 +300,15 @@ func (sfa *snowflakeFileTransferAgent) parseCommand() error {
 		if err != nil {
 			return err
 		}
-		if fi, err := os.Stat(sfa.localLocation); err != nil || !fi.IsDir() {
+		fi, err := os.Stat(sfa.localLocation)
+		if os.IsNotExist(err) && sfa.options != nil && sfa.options.CreateGetTargetDirectory {
+			logger.WithContext(sfa.ctx).Infof("creating the GET target directory %v", sfa.localLocation)
+			if err = os.MkdirAll(sfa.localLocation, sfa.getDirectoryPerm()); err != nil {
+				return err
+			}
+			fi, err = os.Stat(sfa.localLocation)
+		}
+		if err != nil || !fi.IsDir() {
 			return (&SnowflakeError{
 				Number:      ErrLocalPathNotDirectory,
 				SQLState:    sfa.data.SQLState,
@@ -271,6 +324,9 @@ func (sfa *snowflakeFileTransferAgent) parseCommand() error {
 		sfa.parallel = sfa.data.Parallel
 	}
 	sfa.overwrite = sfa.data.Overwrite
+	if sfa.commandType == uploadCommand && sfa.options != nil && sfa.options.Overwrite != configBoolNotSet {
+		sfa.overwrite = sfa.options.Overwrite == ConfigBoolTrue
+	}
 	sfa.stageLocationType = cloudType(strings.ToUpper(sfa.data.StageInfo.LocationType))
 	sfa.stageInfo = &sfa.data.StageInfo
 	sfa.presignedURLs = make([]string, 0)
@@ -295,6 +351,11 @@ func (sfa *snowflakeFileTransferAgent) initEncryptionMaterial() {
 	wrapper := sfa.data.EncryptionMaterial
 
 	if sfa.commandType == uploadCommand {
+		if sfa.skipClientSideEncryption() {
+			logger.WithContext(sfa.ctx).Debugf("stage uses server-side encryption %v, skipping client-side encryption",
+				sfa.data.StageInfo.EncryptionType)
+			return
+		}
 		if wrapper.QueryID != "" {
 			sfa.encryptionMaterial = append(sfa.encryptionMaterial, &wrapper.snowflakeFileEncryption)
 		}
@@ -307,39 +368,81 @@ func (sfa *snowflakeFileTransferAgent) initEncryptionMaterial() {
 	}
 }
 
+// skipClientSideEncryption tells whether the uploaded files are encrypted by the stage itself
+// and the client-side encryption was disabled with SnowflakeFileTransferOptions.
+func (sfa *snowflakeFileTransferAgent) skipClientSideEncryption() bool {
+	if sfa.options == nil || !sfa.options.SkipClientSideEncryptionForSSE {
+		return false
+	}
+	return isServerSideEncryption(sfa.data.StageInfo.EncryptionType)
+}
+
+func isServerSideEncryption(encryptionType string) bool {
+	switch strings.ToUpper(encryptionType) {
+	case "AWS_SSE_S3", "AWS_SSE_KMS", "GCS_SSE_KMS", "SNOWFLAKE_SSE":
+		return true
+	default:
+		return false
+	}
+}
+
 func (sfa *snowflakeFileTransferAgent) expandFilenames(locations []string) ([]string, error) {
 	canonicalLocations := make([]string, 0)
+	seen := make(map[string]bool)
 	for _, fileName := range locations {
-		if sfa.commandType == uploadCommand {
-			var err error
-			fileName, err = expandUser(fileName)
-			if err != nil {
-				return []string{}, err
-			}
-			if !filepath.IsAbs(fileName) {
-				cwd, err := getDirectory()
+		if sfa.commandType != uploadCommand {
+			canonicalLocations = append(canonicalLocations, fileName)
+			continue
+		}
+		files, err := expandUploadPattern(fileName)
+		if err != nil {
+			return []string{}, err
+		}
+		if len(files) == 0 && strings.Contains(fileName, ",") {
+			// comma separated list of files or patterns, e.g. '/data/a/*.csv,/data/b/*.csv'
+			for _, pattern := range strings.Split(fileName, ",") {
+				pattern = strings.TrimPrefix(strings.TrimSpace(pattern), fileProtocol)
+				if pattern == "" {
+					continue
+				}
+				matched, err := expandUploadPattern(pattern)
 				if err != nil {
 					return []string{}, err
 				}
-				fileName = filepath.Join(cwd, fileName)
+				files = append(files, matched...)
 			}
-			if isWindows && len(fileName) > 2 && fileName[0] == '/' && fileName[2] == ':' {
-				// Windows path: /C:/data/file1.txt where it starts with slash
-				// followed by a drive letter and colon.
-				fileName = fileName[1:]
-			}
-			files, err := filepath.Glob(fileName)
-			if err != nil {
-				return []string{}, err
+		}
+		for _, file := range files {
+			// a file matched by several patterns is uploaded once
+			if !seen[file] {
+				seen[file] = true
+				canonicalLocations = append(canonicalLocations, file)
 			}
-			canonicalLocations = append(canonicalLocations, files...)
-		} else {
-			canonicalLocations = append(canonicalLocations, fileName)
 		}
 	}
 	return canonicalLocations, nil
 }
 
+func expandUploadPattern(fileName string) ([]string, error) {
+	fileName, err := expandUser(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if !filepath.IsAbs(fileName) {
+		cwd, err := getDirectory()
+		if err != nil {
+			return nil, err
+		}
+		fileName = filepath.Join(cwd, fileName)
+	}
+	if isWindows && len(fileName) > 2 && fileName[0] == '/' && fileName[2] == ':' {
+		// Windows path: /C:/data/file1.txt where it starts with slash
+		// followed by a drive letter and colon.
+		fileName = fileName[1:]
+	}
+	return filepath.Glob(fileName)
+}
+
 func (sfa *snowflakeFileTransferAgent) initFileMetadata() error {
 	sfa.fileMetadata = []*fileMetadata{}
 	if sfa.commandType == uploadCommand {
@@ -363,6 +466,9 @@ func (sfa *snowflakeFileTransferAgent) initFileMetadata() error {
 			fileInfo, err := os.Stat(fileName)
 			if err != nil {
 				buf := new(bytes.Buffer)
+				if flr, ok := sfa.sourceStream.(*fixedLengthReader); ok {
+					buf.Grow(int(flr.length))
+				}
 				_, err := buf.ReadFrom(sfa.sourceStream)
 				if err != nil {
 					return (&SnowflakeError{
@@ -370,7 +476,7 @@ func (sfa *snowflakeFileTransferAgent) initFileMetadata() error {
 						SQLState:    sfa.data.SQLState,
 						QueryID:     sfa.data.QueryID,
 						Message:     errMsgFailToReadDataFromBuffer,
-						MessageArgs: []interface{}{fileName},
+						MessageArgs: []interface{}{err},
 					}).exceptionTelemetry(sfa.sc)
 				}
 				sfa.fileMetadata = append(sfa.fileMetadata, &fileMetadata{
@@ -438,7 +544,7 @@ func (sfa *snowflakeFileTransferAgent) initFileMetadata() error {
 				if firstPathSep >= 0 {
 					dstFileName = fileName[firstPathSep+1:]
 				}
-				sfa.fileMetadata = append(sfa.fileMetadata, &fileMetadata{
+				meta := &fileMetadata{
 					name:              baseName(fileName),
 					srcFileName:       fileName,
 					dstFileName:       dstFileName,
@@ -446,7 +552,17 @@ func (sfa *snowflakeFileTransferAgent) initFileMetadata() error {
 					stageLocationType: sfa.stageLocationType,
 					stageInfo:         sfa.stageInfo,
 					localLocation:     sfa.localLocation,
-				})
+				}
+				skip, err := sfa.applyGetDestination(meta)
+				if err != nil {
+					return err
+				}
+				if skip {
+					meta.resStatus = skipped
+					sfa.results = append(sfa.results, meta)
+					continue
+				}
+				sfa.fileMetadata = append(sfa.fileMetadata, meta)
 			}
 		}
 		// TODO is this necessary?
@@ -461,6 +577,37 @@ func (sfa *snowflakeFileTransferAgent) initFileMetadata() error {
 	return nil
 }
 
+// applyGetDestination lets the GetDestinationCallback choose the local path of a downloaded file or skip it.
+func (sfa *snowflakeFileTransferAgent) applyGetDestination(meta *fileMetadata) (bool, error) {
+	if sfa.options == nil || sfa.options.GetDestinationCallback == nil {
+		return false, nil
+	}
+	localPath, skip := sfa.options.GetDestinationCallback(meta.dstFileName)
+	if skip || localPath == "" {
+		return skip, nil
+	}
+	localPath, err := expandUser(localPath)
+	if err != nil {
+		return false, err
+	}
+	if !filepath.IsAbs(localPath) {
+		localPath = filepath.Join(sfa.localLocation, localPath)
+	}
+	meta.localLocation = filepath.Dir(localPath)
+	meta.dstFileName = filepath.Base(localPath)
+	if err = os.MkdirAll(meta.localLocation, sfa.getDirectoryPerm()); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+func (sfa *snowflakeFileTransferAgent) getDirectoryPerm() os.FileMode {
+	if sfa.options != nil && sfa.options.GetDirectoryPerm != 0 {
+		return sfa.options.GetDirectoryPerm
+	}
+	return os.ModePerm
+}
+
 func (sfa *snowflakeFileTransferAgent) processFileCompressionType() error {
 	var userSpecifiedSourceCompression *compressionType
 	var autoDetect bool
@@ -894,7 +1041,7 @@ func (sfa *snowflakeFileTransferAgent) uploadOneFile(meta *fileMetadata) (*fileM
 	meta.tmpDir = tmpDir
 	defer os.RemoveAll(tmpDir) // cleanup
 
-	fileUtil := new(snowflakeFileUtil)
+	fileUtil := &snowflakeFileUtil{compressionLevel: sfa.sc.cfg.UploadCompressionLevel}
 
 	err = compressDataIfRequired(meta, fileUtil, tmpDir)
 	if err != nil {
@@ -919,80 +1066,108 @@ func (sfa *snowflakeFileTransferAgent) uploadOneFile(meta *fileMetadata) (*fileM
 }
 
 func (sfa *snowflakeFileTransferAgent) downloadFilesParallel(fileMetas []*fileMetadata) error {
-	idx := 0
-	fileMetaLen := len(fileMetas)
-	var err error
-	for idx < fileMetaLen {
-		endOfIdx := intMin(fileMetaLen, idx+int(sfa.parallel))
-		targetMeta := fileMetas[idx:endOfIdx]
-		for {
-			var wg sync.WaitGroup
-			results := make([]*fileMetadata, len(targetMeta))
-			errors := make([]error, len(targetMeta))
-			for i, meta := range targetMeta {
-				wg.Add(1)
-				go func(k int, m *fileMetadata) {
-					defer wg.Done()
-					results[k], errors[k] = sfa.downloadOneFile(m)
-				}(i, meta)
+	targetMeta := fileMetas
+	for {
+		results := sfa.downloadFilesConcurrently(targetMeta)
+
+		retryMeta := make([]*fileMetadata, 0)
+		for _, result := range results {
+			if result.resStatus == renewToken || result.resStatus == renewPresignedURL {
+				retryMeta = append(retryMeta, result)
+			} else {
+				sfa.results = append(sfa.results, result)
 			}
-			wg.Wait()
+		}
+		if len(retryMeta) == 0 {
+			return nil
+		}
+		logger.WithContext(sfa.sc.ctx).Infof("%v retries found", len(retryMeta))
 
-			retryMeta := make([]*fileMetadata, 0)
-			for i, result := range results {
-				result.errorDetails = errors[i]
-				if result.resStatus == renewToken || result.resStatus == renewPresignedURL {
-					retryMeta = append(retryMeta, result)
-				} else {
-					sfa.results = append(sfa.results, result)
-				}
-			}
-			if len(retryMeta) == 0 {
-				break
+		needRenewToken := false
+		for _, result := range retryMeta {
+			if result.resStatus == renewToken {
+				needRenewToken = true
 			}
-			logger.WithContext(sfa.sc.ctx).Infof("%v retries found", len(retryMeta))
+			logger.WithContext(sfa.sc.ctx).Infof(
+				"retying download file %v with status %v",
+				result.name, result.resStatus)
+		}
 
-			needRenewToken := false
+		if needRenewToken {
+			client, err := sfa.renewExpiredClient()
+			if err != nil {
+				return err
+			}
 			for _, result := range retryMeta {
-				if result.resStatus == renewToken {
-					needRenewToken = true
-				}
-				logger.WithContext(sfa.sc.ctx).Infof(
-					"retying download file %v with status %v",
-					result.name, result.resStatus)
+				result.client = client
 			}
+		}
 
-			if needRenewToken {
-				client, err := sfa.renewExpiredClient()
-				if err != nil {
+		for _, result := range retryMeta {
+			if result.resStatus == renewPresignedURL {
+				if err := sfa.updateFileMetadataWithPresignedURL(); err != nil {
 					return err
 				}
-				for _, result := range retryMeta {
-					result.client = client
-				}
-				if endOfIdx < fileMetaLen {
-					for i := idx + int(sfa.parallel); i < fileMetaLen; i++ {
-						fileMetas[i].client = client
-					}
-				}
+				break
 			}
+		}
+		targetMeta = retryMeta
+	}
+}
 
-			for _, result := range retryMeta {
-				if result.resStatus == renewPresignedURL {
-					if err = sfa.updateFileMetadataWithPresignedURL(); err != nil {
-						return err
-					}
-					break
-				}
+// downloadFilesConcurrently downloads the files keeping at most downloadConcurrency downloads
+// running at a time. The results are in the order of the files.
+func (sfa *snowflakeFileTransferAgent) downloadFilesConcurrently(fileMetas []*fileMetadata) []*fileMetadata {
+	results := make([]*fileMetadata, len(fileMetas))
+	slots := make(chan struct{}, sfa.downloadConcurrency())
+	var wg sync.WaitGroup
+	for i, meta := range fileMetas {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(k int, m *fileMetadata) {
+			defer wg.Done()
+			result, err := sfa.downloadOneFile(m)
+			// the slot is released before reporting the result so that a slow reader
+			// of GetResults does not hold back the remaining downloads
+			<-slots
+			if result == nil {
+				result = m
+				result.resStatus = errStatus
+				result.dstFileSize = -1
 			}
-			targetMeta = retryMeta
-		}
-		if endOfIdx == fileMetaLen {
-			break
-		}
-		idx += int(sfa.parallel)
+			result.errorDetails = err
+			results[k] = result
+			if result.resStatus != renewToken && result.resStatus != renewPresignedURL {
+				sfa.reportDownloadResult(result)
+			}
+		}(i, meta)
+	}
+	wg.Wait()
+	return results
+}
+
+func (sfa *snowflakeFileTransferAgent) downloadConcurrency() int {
+	concurrency := int(max(sfa.parallel, 1))
+	if sfa.sc.cfg.MaxGetConcurrency > 0 {
+		return min(concurrency, sfa.sc.cfg.MaxGetConcurrency)
+	}
+	return concurrency
+}
+
+// reportDownloadResult sends the result of a finished download to the GetResults channel if it is set.
+func (sfa *snowflakeFileTransferAgent) reportDownloadResult(meta *fileMetadata) {
+	if sfa.options == nil || sfa.options.GetResults == nil {
+		return
+	}
+	result := GetFileResult{Name: meta.dstFileName, Bytes: max(meta.dstFileSize, 0), Err: meta.errorDetails}
+	ctx := sfa.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	select {
+	case sfa.options.GetResults <- result:
+	case <-ctx.Done():
 	}
-	return err
 }
 
 func (sfa *snowflakeFileTransferAgent) downloadOneFile(meta *fileMetadata) (*fileMetadata, error) {
@@ -1123,6 +1298,7 @@ func (sfa *snowflakeFileTransferAgent) result() (*execResponse, error) {
 				{Name: "message", ByteLength: 10000, Length: 10000, Type: "TEXT", Scale: 0, Nullable: false},
 			}
 			data.RowType = rt
+			data.FileTransferResults = toFileTransferResults(rowset)
 			return &execResponse{Data: *data, Success: true}, nil
 		}
 	} else { // DOWNLOAD
@@ -1173,6 +1349,7 @@ func (sfa *snowflakeFileTransferAgent) result() (*execResponse, error) {
 				{Name: "message", ByteLength: 10000, Length: 10000, Type: "TEXT", Scale: 0, Nullable: false},
 			}
 			data.RowType = rt
+			data.FileTransferResults = toFileTransferResults(rowset)
 			return &execResponse{Data: *data, Success: true}, nil
 		}
 	}
@@ -1184,6 +1361,29 @@ func (sfa *snowflakeFileTransferAgent) result() (*execResponse, error) {
 	}).exceptionTelemetry(sfa.sc)
 }
 
+func toFileTransferResults(rowset []fileTransferResultType) []FileTransferResult {
+	results := make([]FileTransferResult, len(rowset))
+	for i, rs := range rowset {
+		results[i] = FileTransferResult{
+			Source:     rs.srcFileName,
+			Target:     rs.dstFileName,
+			SourceSize: rs.srcFileSize,
+			TargetSize: rs.dstFileSize,
+			Status:     rs.resStatus.String(),
+		}
+		if rs.srcCompressionType != nil {
+			results[i].SourceCompression = rs.srcCompressionType.name
+		}
+		if rs.dstCompressionType != nil {
+			results[i].TargetCompression = rs.dstCompressionType.name
+		}
+		if rs.errorDetails != nil {
+			results[i].Message = rs.errorDetails.Error()
+		}
+	}
+	return results
+}
+
 func isFileTransfer(query string) bool {
 	putRe := regexp.MustCompile(putRegexp)
 	getRe := regexp.MustCompile(getRegexp)
@@ -2,7 +2,10 @@ package gosnowflake
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -11,11 +14,14 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 
 	"github.com/aws/smithy-go"
@@ -1292,3 +1298,655 @@ func TestEncryptFile(t *testing.T) {
 		})
 	}
 }
+
+func TestUploadSkipsClientSideEncryptionForSSEStage(t *testing.T) {
+	srcFile := filepath.Join(t.TempDir(), "data.csv")
+	assertNilF(t, os.WriteFile(srcFile, []byte("1,a\n2,b\n"), 0600))
+
+	for _, tc := range []struct {
+		name           string
+		skip           bool
+		encryptionType string
+		encrypted      bool
+	}{
+		{name: "SSE stage with skip enabled", skip: true, encryptionType: "AWS_SSE_KMS", encrypted: false},
+		{name: "SSE stage with skip disabled", skip: false, encryptionType: "AWS_SSE_KMS", encrypted: true},
+		{name: "stage without SSE", skip: true, encryptionType: "", encrypted: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var uploaded *s3.PutObjectInput
+			sfa := &snowflakeFileTransferAgent{
+				ctx: context.Background(),
+				sc: &snowflakeConn{
+					cfg: &Config{TmpDirPath: t.TempDir()},
+				},
+				data: &execResponseData{
+					Command:           string(uploadCommand),
+					SrcLocations:      []string{srcFile},
+					SourceCompression: "none",
+					EncryptionMaterial: encryptionWrapper{
+						snowflakeFileEncryption: snowflakeFileEncryption{
+							QueryStageMasterKey: "abCdEFO0upIT36dAxGsa0w==",
+							QueryID:             "01abc874-0406-1bf0-0000-53b10668e056",
+							SMKID:               92019681909886,
+						},
+					},
+					StageInfo: execResponseStageInfo{
+						LocationType:   "S3",
+						Location:       "sse-stage/path/",
+						EncryptionType: tc.encryptionType,
+					},
+				},
+				options: &SnowflakeFileTransferOptions{
+					MultiPartThreshold:             dataSizeThreshold,
+					SkipClientSideEncryptionForSSE: tc.skip,
+				},
+			}
+			assertNilF(t, sfa.parseCommand())
+			assertNilF(t, sfa.initFileMetadata())
+			assertEqualF(t, len(sfa.fileMetadata), 1)
+
+			meta := sfa.fileMetadata[0]
+			meta.sfa = sfa
+			meta.options = sfa.options
+			meta.parallel = 1
+			meta.overwrite = true
+			meta.dstFileName = meta.name
+			var err error
+			meta.client, err = new(snowflakeS3Client).createClient(sfa.stageInfo, false)
+			assertNilF(t, err)
+			meta.mockUploader = mockUploadObjectAPI(func(_ context.Context, params *s3.PutObjectInput, _ ...func(*manager.Uploader)) (*manager.UploadOutput, error) {
+				uploaded = params
+				return &manager.UploadOutput{}, nil
+			})
+
+			_, err = sfa.uploadOneFile(meta)
+			assertNilF(t, err)
+			assertNotNilF(t, uploaded)
+			_, hasKey := uploaded.Metadata[amzKey]
+			assertEqualE(t, hasKey, tc.encrypted)
+			body, err := io.ReadAll(uploaded.Body)
+			assertNilF(t, err)
+			assertEqualE(t, string(body) == "1,a\n2,b\n", !tc.encrypted)
+		})
+	}
+}
+
+func TestUploadMultipleGlobPatterns(t *testing.T) {
+	dir1 := t.TempDir()
+	dir2 := t.TempDir()
+	for _, f := range []string{filepath.Join(dir1, "a1.csv"), filepath.Join(dir1, "a2.csv"), filepath.Join(dir2, "b1.csv")} {
+		assertNilF(t, os.WriteFile(f, []byte("1,a\n"), 0600))
+	}
+	assertNilF(t, os.WriteFile(filepath.Join(dir2, "skipped.txt"), []byte("x"), 0600))
+	stageDir := t.TempDir()
+	srcLocation := filepath.Join(dir1, "*.csv") + "," + fileProtocol + filepath.Join(dir2, "*.csv")
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+		_ *url.Values, _ map[string]string, _ []byte, _ time.Duration,
+		_ UUID, _ *Config) (*execResponse, error) {
+		return &execResponse{
+			Data: execResponseData{
+				Command:           string(uploadCommand),
+				SrcLocations:      []string{srcLocation},
+				SourceCompression: "none",
+				Parallel:          4,
+				Overwrite:         true,
+				StageInfo: execResponseStageInfo{
+					LocationType: string(local),
+					Location:     stageDir,
+				},
+			},
+			Code:    "0",
+			Success: true,
+		}, nil
+	}
+	sc := &snowflakeConn{
+		cfg:                 &Config{Params: map[string]*string{}, TmpDirPath: t.TempDir()},
+		rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+	}
+
+	rows, err := sc.QueryContext(context.Background(),
+		fmt.Sprintf("PUT 'file://%v' @~ AUTO_COMPRESS = FALSE", srcLocation), nil)
+	assertNilF(t, err)
+	defer rows.Close()
+	dest := make([]driver.Value, len(rows.Columns()))
+	var sources []string
+	for rows.Next(dest) == nil {
+		sources = append(sources, filepath.Base(dest[0].(string)))
+		assertEqualE(t, dest[6], uploaded.String())
+	}
+	sort.Strings(sources)
+	assertDeepEqualE(t, sources, []string{"a1.csv", "a2.csv", "b1.csv"})
+
+	entries, err := os.ReadDir(stageDir)
+	assertNilF(t, err)
+	assertEqualE(t, len(entries), 3)
+}
+
+func TestPutFileTransferResults(t *testing.T) {
+	srcDir := t.TempDir()
+	srcFile := filepath.Join(srcDir, "data.csv")
+	assertNilF(t, os.WriteFile(srcFile, []byte("1,a\n2,b\n"), 0600))
+	stageDir := t.TempDir()
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+		_ *url.Values, _ map[string]string, _ []byte, _ time.Duration,
+		_ UUID, _ *Config) (*execResponse, error) {
+		return &execResponse{
+			Data: execResponseData{
+				Command:           string(uploadCommand),
+				SrcLocations:      []string{srcFile},
+				SourceCompression: "none",
+				Overwrite:         true,
+				StageInfo: execResponseStageInfo{
+					LocationType: string(local),
+					Location:     stageDir,
+				},
+			},
+			Code:    "0",
+			Success: true,
+		}, nil
+	}
+	sc := &snowflakeConn{
+		cfg:                 &Config{Params: map[string]*string{}, TmpDirPath: t.TempDir()},
+		rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+	}
+
+	rows, err := sc.QueryContext(context.Background(), fmt.Sprintf("PUT 'file://%v' @~ AUTO_COMPRESS = FALSE", srcFile), nil)
+	assertNilF(t, err)
+	defer rows.Close()
+	assertDeepEqualE(t, rows.(SnowflakeRows).GetFileTransferResults(), []FileTransferResult{{
+		Source:            srcFile,
+		Target:            "data.csv",
+		SourceSize:        8,
+		TargetSize:        8,
+		SourceCompression: "NONE",
+		TargetCompression: "NONE",
+		Status:            "UPLOADED",
+	}})
+}
+
+func TestUploadFromPipeWithKnownLength(t *testing.T) {
+	stageDir := t.TempDir()
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+		_ *url.Values, _ map[string]string, _ []byte, _ time.Duration,
+		_ UUID, _ *Config) (*execResponse, error) {
+		return &execResponse{
+			Data: execResponseData{
+				Command:           string(uploadCommand),
+				SrcLocations:      []string{"/tmp/placeholder/data.csv"},
+				SourceCompression: "none",
+				Overwrite:         true,
+				StageInfo: execResponseStageInfo{
+					LocationType: string(local),
+					Location:     stageDir,
+				},
+			},
+			Code:    "0",
+			Success: true,
+		}, nil
+	}
+	sc := &snowflakeConn{
+		cfg:                 &Config{Params: map[string]*string{}, TmpDirPath: t.TempDir()},
+		rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+	}
+	put := "PUT 'file:///tmp/placeholder/data.csv' @~ AUTO_COMPRESS = FALSE"
+	data := "1,a\n2,b\n3,c\n"
+
+	t.Run("known length", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		defer pw.Close()
+		go func() {
+			// the producer keeps the pipe open after writing the data
+			_, _ = pw.Write([]byte(data))
+		}()
+		ctx := WithFileStreamLength(context.Background(), pr, int64(len(data)))
+		_, err := sc.ExecContext(ctx, put, nil)
+		assertNilF(t, err)
+		staged, err := os.ReadFile(filepath.Join(stageDir, "data.csv"))
+		assertNilF(t, err)
+		assertEqualE(t, string(staged), data)
+	})
+
+	t.Run("stream shorter than the length", func(t *testing.T) {
+		pr, pw := io.Pipe()
+		go func() {
+			_, _ = pw.Write([]byte(data))
+			_ = pw.Close()
+		}()
+		ctx := WithFileStreamLength(context.Background(), pr, int64(len(data)+1))
+		_, err := sc.ExecContext(ctx, put, nil)
+		assertNotNilF(t, err)
+		assertStringContainsE(t, err.Error(), fmt.Sprintf("file stream ended after %v of %v bytes", len(data), len(data)+1))
+	})
+
+	t.Run("negative length", func(t *testing.T) {
+		ctx := WithFileStreamLength(context.Background(), strings.NewReader(data), -1)
+		_, err := sc.ExecContext(ctx, put, nil)
+		var se *SnowflakeError
+		assertTrueF(t, errors.As(err, &se), fmt.Sprintf("unexpected error: %v", err))
+		assertEqualE(t, se.Number, ErrInvalidFileStreamLength)
+	})
+}
+
+func TestUploadCompressionLevel(t *testing.T) {
+	srcFile := filepath.Join(t.TempDir(), "data.csv")
+	assertNilF(t, os.WriteFile(srcFile, []byte(strings.Repeat("1,abc\n", 1000)), 0600))
+	// the XFL byte of the gzip header records whether the fastest or the best compression was used
+	for level, xfl := range map[int]byte{gzip.BestSpeed: 4, gzip.BestCompression: 2} {
+		t.Run(fmt.Sprint(level), func(t *testing.T) {
+			stageDir := t.TempDir()
+			postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+				_ *url.Values, _ map[string]string, _ []byte, _ time.Duration,
+				_ UUID, _ *Config) (*execResponse, error) {
+				return &execResponse{
+					Data: execResponseData{
+						Command:           string(uploadCommand),
+						SrcLocations:      []string{srcFile},
+						SourceCompression: "auto_detect",
+						AutoCompress:      true,
+						Overwrite:         true,
+						StageInfo: execResponseStageInfo{
+							LocationType: string(local),
+							Location:     stageDir,
+						},
+					},
+					Code:    "0",
+					Success: true,
+				}, nil
+			}
+			sc := &snowflakeConn{
+				cfg:                 &Config{Params: map[string]*string{}, TmpDirPath: t.TempDir(), UploadCompressionLevel: level},
+				rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+				queryContextCache:   (&queryContextCache{}).init(),
+				currentTimeProvider: defaultTimeProvider,
+			}
+			_, err := sc.ExecContext(context.Background(), fmt.Sprintf("PUT 'file://%v' @~ AUTO_COMPRESS = TRUE", srcFile), nil)
+			assertNilF(t, err)
+			staged, err := os.ReadFile(filepath.Join(stageDir, "data.csv.gz"))
+			assertNilF(t, err)
+			assertTrueF(t, len(staged) > 8)
+			assertEqualE(t, staged[8], xfl)
+		})
+	}
+
+	err := fillMissingConfigParameters(&Config{Account: "a", User: "u", Password: "p", UploadCompressionLevel: 10})
+	assertEqualE(t, err.(*SnowflakeError).Number, ErrCodeInvalidCompressionLevel)
+}
+
+func TestUploadOverwriteAndAutoCompressOptions(t *testing.T) {
+	srcDir := t.TempDir()
+	for _, name := range []string{"existing.csv", "new.csv"} {
+		assertNilF(t, os.WriteFile(filepath.Join(srcDir, name), []byte("1,a\n"), 0600))
+	}
+	stageDir := t.TempDir()
+	assertNilF(t, os.WriteFile(filepath.Join(stageDir, "existing.csv"), []byte("old"), 0600))
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+		_ *url.Values, _ map[string]string, body []byte, _ time.Duration,
+		_ UUID, _ *Config) (*execResponse, error) {
+		var req execRequest
+		assertNilF(t, json.Unmarshal(body, &req))
+		// the server parses the options of the command, SOURCE_COMPRESSION is omitted
+		return &execResponse{
+			Data: execResponseData{
+				Command:      string(uploadCommand),
+				SrcLocations: []string{filepath.Join(srcDir, "*.csv")},
+				AutoCompress: !strings.Contains(req.SQLText, "AUTO_COMPRESS = FALSE"),
+				Overwrite:    strings.Contains(req.SQLText, "OVERWRITE = TRUE"),
+				StageInfo: execResponseStageInfo{
+					LocationType: string(local),
+					Location:     stageDir,
+				},
+			},
+			Code:    "0",
+			Success: true,
+		}, nil
+	}
+	sc := &snowflakeConn{
+		cfg:                 &Config{Params: map[string]*string{}, TmpDirPath: t.TempDir()},
+		rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+	}
+	put := func(ctx context.Context, command string) map[string]string {
+		rows, err := sc.QueryContext(ctx, command, nil)
+		assertNilF(t, err)
+		defer rows.Close()
+		statuses := make(map[string]string)
+		dest := make([]driver.Value, len(rows.Columns()))
+		for rows.Next(dest) == nil {
+			statuses[dest[1].(string)] = dest[6].(string)
+		}
+		return statuses
+	}
+
+	t.Run("from the command", func(t *testing.T) {
+		statuses := put(context.Background(),
+			fmt.Sprintf("PUT 'file://%v/*.csv' @~ OVERWRITE = FALSE AUTO_COMPRESS = FALSE", srcDir))
+		assertDeepEqualE(t, statuses, map[string]string{"existing.csv": skipped.String(), "new.csv": uploaded.String()})
+		staged, err := os.ReadFile(filepath.Join(stageDir, "existing.csv"))
+		assertNilF(t, err)
+		assertEqualE(t, string(staged), "old")
+		staged, err = os.ReadFile(filepath.Join(stageDir, "new.csv"))
+		assertNilF(t, err)
+		assertEqualE(t, string(staged), "1,a\n")
+		assertNilF(t, os.Remove(filepath.Join(stageDir, "new.csv")))
+	})
+
+	t.Run("from the options", func(t *testing.T) {
+		ctx := WithFileTransferOptions(context.Background(), &SnowflakeFileTransferOptions{
+			RaisePutGetError: true,
+			Overwrite:        ConfigBoolFalse,
+			AutoCompress:     ConfigBoolFalse,
+		})
+		statuses := put(ctx, fmt.Sprintf("PUT 'file://%v/*.csv' @~ OVERWRITE = TRUE", srcDir))
+		assertDeepEqualE(t, statuses, map[string]string{"existing.csv": skipped.String(), "new.csv": uploaded.String()})
+		_, err := os.Stat(filepath.Join(stageDir, "new.csv.gz"))
+		assertTrueE(t, os.IsNotExist(err))
+		staged, err := os.ReadFile(filepath.Join(stageDir, "new.csv"))
+		assertNilF(t, err)
+		assertEqualE(t, string(staged), "1,a\n")
+	})
+}
+
+func TestDownloadWithDestinationCallback(t *testing.T) {
+	stageDir := t.TempDir()
+	for _, name := range []string{"a.csv", "b.csv", "c.csv"} {
+		assertNilF(t, os.WriteFile(filepath.Join(stageDir, name), []byte(name), 0600))
+	}
+	localDir := t.TempDir()
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+		_ *url.Values, _ map[string]string, _ []byte, _ time.Duration,
+		_ UUID, _ *Config) (*execResponse, error) {
+		return &execResponse{
+			Data: execResponseData{
+				Command:       string(downloadCommand),
+				SrcLocations:  []string{"a.csv", "b.csv", "c.csv"},
+				LocalLocation: localDir,
+				StageInfo: execResponseStageInfo{
+					LocationType: string(local),
+					Location:     stageDir,
+				},
+			},
+			Code:    "0",
+			Success: true,
+		}, nil
+	}
+	sc := &snowflakeConn{
+		cfg:                 &Config{Params: map[string]*string{}, TmpDirPath: t.TempDir()},
+		rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+	}
+	var stageFileNames []string
+	ctx := WithFileTransferOptions(context.Background(), &SnowflakeFileTransferOptions{
+		GetDestinationCallback: func(stageFileName string) (string, bool) {
+			stageFileNames = append(stageFileNames, stageFileName)
+			switch stageFileName {
+			case "a.csv":
+				return filepath.Join("renamed", "first.csv"), false
+			case "b.csv":
+				return "", true
+			default:
+				return "", false
+			}
+		},
+	})
+
+	rows, err := sc.QueryContext(ctx, fmt.Sprintf("GET @~ file://%v", localDir), nil)
+	assertNilF(t, err)
+	defer rows.Close()
+	dest := make([]driver.Value, len(rows.Columns()))
+	statuses := make(map[string]string)
+	for rows.Next(dest) == nil {
+		statuses[dest[0].(string)] = dest[2].(string)
+	}
+	assertDeepEqualE(t, stageFileNames, []string{"a.csv", "b.csv", "c.csv"})
+	assertDeepEqualE(t, statuses, map[string]string{
+		"first.csv": downloaded.String(),
+		"b.csv":     skipped.String(),
+		"c.csv":     downloaded.String(),
+	})
+
+	content, err := os.ReadFile(filepath.Join(localDir, "renamed", "first.csv"))
+	assertNilF(t, err)
+	assertEqualE(t, string(content), "a.csv")
+	_, err = os.Stat(filepath.Join(localDir, "b.csv"))
+	assertTrueE(t, os.IsNotExist(err), "skipped file should not be downloaded")
+	_, err = os.Stat(filepath.Join(localDir, "c.csv"))
+	assertNilE(t, err)
+}
+
+func TestDownloadToMissingTargetDirectory(t *testing.T) {
+	stageDir := t.TempDir()
+	assertNilF(t, os.WriteFile(filepath.Join(stageDir, "a.csv"), []byte("1,a\n"), 0600))
+	get := func(t *testing.T, localDir string, options *SnowflakeFileTransferOptions) error {
+		postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+			_ *url.Values, _ map[string]string, _ []byte, _ time.Duration,
+			_ UUID, _ *Config) (*execResponse, error) {
+			return &execResponse{
+				Data: execResponseData{
+					Command:       string(downloadCommand),
+					SrcLocations:  []string{"a.csv"},
+					LocalLocation: localDir,
+					StageInfo: execResponseStageInfo{
+						LocationType: string(local),
+						Location:     stageDir,
+					},
+				},
+				Code:    "0",
+				Success: true,
+			}, nil
+		}
+		sc := &snowflakeConn{
+			cfg:                 &Config{Params: map[string]*string{}, TmpDirPath: t.TempDir()},
+			rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+			queryContextCache:   (&queryContextCache{}).init(),
+			currentTimeProvider: defaultTimeProvider,
+		}
+		ctx := WithFileTransferOptions(context.Background(), options)
+		rows, err := sc.QueryContext(ctx, fmt.Sprintf("GET @~ file://%v", localDir), nil)
+		if err != nil {
+			return err
+		}
+		return rows.Close()
+	}
+
+	t.Run("created when enabled", func(t *testing.T) {
+		localDir := filepath.Join(t.TempDir(), "nested", "target")
+		assertNilF(t, get(t, localDir, &SnowflakeFileTransferOptions{
+			CreateGetTargetDirectory: true,
+			GetDirectoryPerm:         0700,
+		}))
+		content, err := os.ReadFile(filepath.Join(localDir, "a.csv"))
+		assertNilF(t, err)
+		assertEqualE(t, string(content), "1,a\n")
+		if !isWindows {
+			fi, err := os.Stat(localDir)
+			assertNilF(t, err)
+			assertEqualE(t, fi.Mode().Perm(), os.FileMode(0700))
+			fi, err = os.Stat(filepath.Dir(localDir))
+			assertNilF(t, err)
+			assertEqualE(t, fi.Mode().Perm(), os.FileMode(0700))
+		}
+	})
+
+	t.Run("error when disabled", func(t *testing.T) {
+		localDir := filepath.Join(t.TempDir(), "nested", "target")
+		err := get(t, localDir, &SnowflakeFileTransferOptions{})
+		var se *SnowflakeError
+		assertTrueF(t, errors.As(err, &se), fmt.Sprintf("unexpected error: %v", err))
+		assertEqualE(t, se.Number, ErrLocalPathNotDirectory)
+		_, err = os.Stat(filepath.Dir(localDir))
+		assertTrueE(t, os.IsNotExist(err), "the directory should not be created")
+	})
+}
+
+func TestDownloadFilesWithBoundedConcurrency(t *testing.T) {
+	info := execResponseStageInfo{
+		Location:     "sfc-teststage/rwyitestacco/users/1234/",
+		LocationType: "S3",
+	}
+	s3Cli, err := new(snowflakeS3Client).createClient(&info, false)
+	assertNilF(t, err)
+	numFiles := 20
+	results := make(chan GetFileResult, numFiles)
+	sfa := &snowflakeFileTransferAgent{
+		ctx:               context.Background(),
+		sc:                &snowflakeConn{cfg: &Config{MaxGetConcurrency: 3, TmpDirPath: t.TempDir()}},
+		stageLocationType: s3Client,
+		parallel:          10,
+		options: &SnowflakeFileTransferOptions{
+			MultiPartThreshold: dataSizeThreshold,
+			GetResults:         results,
+		},
+	}
+
+	var inFlight, maxInFlight atomic.Int32
+	download := mockDownloadObjectAPI(func(_ context.Context, w io.WriterAt, _ *s3.GetObjectInput, _ ...func(*manager.Downloader)) (int64, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for m := maxInFlight.Load(); n > m; m = maxInFlight.Load() {
+			if maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		_, err := w.WriteAt([]byte("data"), 0)
+		return 4, err
+	})
+	header := mockHeaderAPI(func(_ context.Context, _ *s3.HeadObjectInput, _ ...func(*s3.Options)) (*s3.HeadObjectOutput, error) {
+		return &s3.HeadObjectOutput{}, nil
+	})
+	dir := t.TempDir()
+	var metas []*fileMetadata
+	for i := 0; i < numFiles; i++ {
+		name := fmt.Sprintf("data%v.csv", i)
+		metas = append(metas, &fileMetadata{
+			name:              name,
+			stageLocationType: "S3",
+			noSleepingTime:    true,
+			client:            s3Cli,
+			stageInfo:         &info,
+			dstFileName:       name,
+			srcFileName:       name,
+			overwrite:         true,
+			localLocation:     dir,
+			options:           sfa.options,
+			mockDownloader:    download,
+			mockHeader:        header,
+			sfa:               sfa,
+		})
+	}
+
+	assertNilF(t, sfa.downloadFilesParallel(metas))
+	assertEqualE(t, len(sfa.results), numFiles)
+	assertTrueE(t, maxInFlight.Load() <= 3, fmt.Sprintf("%v downloads ran at a time", maxInFlight.Load()))
+	assertEqualF(t, len(results), numFiles)
+	names := make(map[string]bool)
+	for i := 0; i < numFiles; i++ {
+		result := <-results
+		assertNilE(t, result.Err)
+		assertEqualE(t, result.Bytes, int64(4))
+		names[result.Name] = true
+	}
+	assertEqualE(t, len(names), numFiles)
+}
+
+func TestDownloadFilesDoesNotWaitForResultReader(t *testing.T) {
+	info := execResponseStageInfo{
+		Location:     "sfc-teststage/rwyitestacco/users/1234/",
+		LocationType: "S3",
+	}
+	s3Cli, err := new(snowflakeS3Client).createClient(&info, false)
+	assertNilF(t, err)
+	numFiles := 10
+	results := make(chan GetFileResult)
+	sfa := &snowflakeFileTransferAgent{
+		ctx:               context.Background(),
+		sc:                &snowflakeConn{cfg: &Config{MaxGetConcurrency: 2, TmpDirPath: t.TempDir()}},
+		stageLocationType: s3Client,
+		parallel:          10,
+		options: &SnowflakeFileTransferOptions{
+			MultiPartThreshold: dataSizeThreshold,
+			GetResults:         results,
+		},
+	}
+
+	var downloaded atomic.Int32
+	download := mockDownloadObjectAPI(func(_ context.Context, w io.WriterAt, _ *s3.GetObjectInput, _ ...func(*manager.Downloader)) (int64, error) {
+		defer downloaded.Add(1)
+		_, err := w.WriteAt([]byte("data"), 0)
+		return 4, err
+	})
+	header := mockHeaderAPI(func(_ context.Context, _ *s3.HeadObjectInput, _ ...func(*s3.Options)) (*s3.HeadObjectOutput, error) {
+		return &s3.HeadObjectOutput{}, nil
+	})
+	dir := t.TempDir()
+	var metas []*fileMetadata
+	for i := 0; i < numFiles; i++ {
+		name := fmt.Sprintf("data%v.csv", i)
+		metas = append(metas, &fileMetadata{
+			name:              name,
+			stageLocationType: "S3",
+			noSleepingTime:    true,
+			client:            s3Cli,
+			stageInfo:         &info,
+			dstFileName:       name,
+			srcFileName:       name,
+			overwrite:         true,
+			localLocation:     dir,
+			options:           sfa.options,
+			mockDownloader:    download,
+			mockHeader:        header,
+			sfa:               sfa,
+		})
+	}
+
+	// the reader starts only once every file is downloaded, which never happens
+	// if the workers waiting for it keep their download slots
+	allDownloaded := make(chan bool, 1)
+	received := make(chan int, 1)
+	go func() {
+		deadline := time.Now().Add(5 * time.Second)
+		for downloaded.Load() < int32(numFiles) && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		allDownloaded <- downloaded.Load() == int32(numFiles)
+		n := 0
+		for n < numFiles {
+			<-results
+			n++
+		}
+		received <- n
+	}()
+	assertNilF(t, sfa.downloadFilesParallel(metas))
+	assertTrueE(t, <-allDownloaded, "the downloads should not wait for the results to be read")
+	assertEqualE(t, <-received, numFiles)
+}
+
+func TestDownloadConcurrency(t *testing.T) {
+	for _, tc := range []struct {
+		parallel          int64
+		maxGetConcurrency int
+		expected          int
+	}{
+		{parallel: 10, maxGetConcurrency: 0, expected: 10},
+		{parallel: 10, maxGetConcurrency: 3, expected: 3},
+		{parallel: 2, maxGetConcurrency: 8, expected: 2},
+		{parallel: 0, maxGetConcurrency: 0, expected: 1},
+	} {
+		t.Run(fmt.Sprintf("parallel %v max %v", tc.parallel, tc.maxGetConcurrency), func(t *testing.T) {
+			sfa := &snowflakeFileTransferAgent{
+				sc:       &snowflakeConn{cfg: &Config{MaxGetConcurrency: tc.maxGetConcurrency}},
+				parallel: tc.parallel,
+			}
+			assertEqualE(t, sfa.downloadConcurrency(), tc.expected)
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 )
 
 type snowflakeFileUtil struct {
+	compressionLevel int // gzip level, gzip.DefaultCompression if 0
 }
 
 const (
@@ -21,6 +22,13 @@ const (
 	readWriteFileMode os.FileMode = 0666
 )
 
+func (util *snowflakeFileUtil) gzipLevel() int {
+	if util.compressionLevel == 0 {
+		return gzip.DefaultCompression
+	}
+	return util.compressionLevel
+}
+
 func (util *snowflakeFileUtil) compressFileWithGzipFromStream(srcStream **bytes.Buffer) (*bytes.Buffer, int, error) {
 	r := getReaderFromBuffer(srcStream)
 	buf, err := io.ReadAll(r)
@@ -28,7 +36,10 @@ func (util *snowflakeFileUtil) compressFileWithGzipFromStream(srcStream **bytes.
 		return nil, -1, err
 	}
 	var c bytes.Buffer
-	w := gzip.NewWriter(&c)
+	w, err := gzip.NewWriterLevel(&c, util.gzipLevel())
+	if err != nil {
+		return nil, -1, err
+	}
 	if _, err := w.Write(buf); err != nil { // write buf to gzip writer
 		return nil, -1, err
 	}
@@ -55,7 +66,10 @@ func (util *snowflakeFileUtil) compressFileWithGzip(fileName string, tmpDir stri
 	if err != nil {
 		return "", -1, err
 	}
-	gzw := gzip.NewWriter(fw)
+	gzw, err := gzip.NewWriterLevel(fw, util.gzipLevel())
+	if err != nil {
+		return "", -1, err
+	}
 	defer func() {
 		if tmpErr := gzw.Close(); tmpErr != nil {
 			err = tmpErr
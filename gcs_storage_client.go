@@ -35,7 +35,7 @@ func (util *snowflakeGcsClient) createClient(info *execResponseStageInfo, _ bool
 		logger.Debug("Using GCS downscoped token")
 		return info.Creds.GcsAccessToken, nil
 	}
-	logger.Debugf("No access token received from GS, using presigned url: %s", info.PresignedURL)
+	logger.Debugf("No access token received from GS, using presigned url: %s", redactURL(info.PresignedURL))
 	return "", nil
 }
 
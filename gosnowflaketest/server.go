@@ -0,0 +1,201 @@
+// Package gosnowflaketest provides a mock Snowflake server to test code using the driver
+// without a Snowflake account, like net/http/httptest does for HTTP servers.
+//
+// The server accepts any credentials and answers the queries registered with AddResult,
+// and SELECT 1 run by Ping, with JSON results:
+//
+//	server := gosnowflaketest.NewServer()
+//	defer server.Close()
+//	server.AddResult("SELECT name FROM users", []gosnowflaketest.Column{{Name: "NAME", Type: "text"}}, [][]*string{{&name}})
+//	db := sql.OpenDB(sf.NewConnector(sf.SnowflakeDriver{}, *server.Config()))
+//
+// The package is not imported by the driver, so it is built only by the programs and tests importing it.
+package gosnowflaketest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	sf "github.com/snowflakedb/gosnowflake"
+)
+
+const (
+	loginRequestPath = "/session/v1/login-request"
+	queryRequestPath = "/queries/v1/query-request"
+
+	// statementTypeIDSelect is the statement type Snowflake reports for a SELECT.
+	statementTypeIDSelect = 0x1000
+	// sqlCompilationErrorCode is the error code Snowflake reports for an unknown object.
+	sqlCompilationErrorCode = "002003"
+)
+
+// Column describes a column of a result. Type is a Snowflake type, e.g. fixed, real, text,
+// boolean, date or timestamp_ntz, in the format of the JSON results.
+type Column struct {
+	Name      string
+	Type      string
+	Precision int64
+	Scale     int64
+	Length    int64
+	Nullable  bool
+}
+
+type result struct {
+	columns []Column
+	rows    [][]*string
+}
+
+// Server is a mock Snowflake server. The zero value is not usable, use NewServer.
+type Server struct {
+	*httptest.Server
+
+	mutex   sync.Mutex
+	results map[string]result
+	queries []string
+}
+
+// NewServer starts a mock Snowflake server, which the caller should close when finished.
+func NewServer() *Server {
+	one := "1"
+	s := &Server{
+		results: map[string]result{
+			"SELECT 1": {columns: []Column{{Name: "1", Type: "fixed", Precision: 1}}, rows: [][]*string{{&one}}},
+		},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(loginRequestPath, s.handleLogin)
+	mux.HandleFunc(queryRequestPath, s.handleQuery)
+	// session deletion, heartbeats, telemetry etc. succeed
+	mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, map[string]any{"success": true, "code": "0"})
+	})
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// Config returns a configuration connecting to the server.
+func (s *Server) Config() *sf.Config {
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		panic(fmt.Sprintf("invalid mock server URL %v: %v", s.URL, err))
+	}
+	host, portStr, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		panic(fmt.Sprintf("invalid mock server address %v: %v", u.Host, err))
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		panic(fmt.Sprintf("invalid mock server port %v: %v", portStr, err))
+	}
+	return &sf.Config{
+		Account:          "mock",
+		User:             "mock",
+		Password:         "mock",
+		Host:             host,
+		Port:             port,
+		Protocol:         u.Scheme,
+		DisableTelemetry: true,
+	}
+}
+
+// AddResult registers the result of a query. Queries are matched on their text without
+// leading and trailing spaces. Each value of rows is nil for NULL or the value in the format
+// of the JSON results, e.g. "1.5" for a NUMBER(2, 1).
+func (s *Server) AddResult(query string, columns []Column, rows [][]*string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.results[strings.TrimSpace(query)] = result{columns: columns, rows: rows}
+}
+
+// Queries returns the texts of the queries received so far.
+func (s *Server) Queries() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string(nil), s.queries...)
+}
+
+func (s *Server) handleLogin(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, map[string]any{
+		"success": true,
+		"code":    "0",
+		"data": map[string]any{
+			"token":                   "mock-session-token",
+			"masterToken":             "mock-master-token",
+			"validityInSeconds":       3600,
+			"masterValidityInSeconds": 14400,
+			"sessionId":               1,
+			"sessionInfo": map[string]any{
+				"databaseName":  "MOCK_DB",
+				"schemaName":    "PUBLIC",
+				"warehouseName": "MOCK_WH",
+				"roleName":      "PUBLIC",
+			},
+		},
+	})
+}
+
+func (s *Server) handleQuery(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		SQLText string `json:"sqlText"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	query := strings.TrimSpace(req.SQLText)
+	s.mutex.Lock()
+	s.queries = append(s.queries, query)
+	res, ok := s.results[query]
+	s.mutex.Unlock()
+	if !ok {
+		writeJSON(w, map[string]any{
+			"success": false,
+			"code":    sqlCompilationErrorCode,
+			"message": fmt.Sprintf("SQL compilation error: the mock server has no result for %q", query),
+			"data":    map[string]any{"sqlState": "42S02"},
+		})
+		return
+	}
+	rowType := make([]map[string]any, len(res.columns))
+	for i, c := range res.columns {
+		rowType[i] = map[string]any{
+			"name":      c.Name,
+			"type":      c.Type,
+			"precision": c.Precision,
+			"scale":     c.Scale,
+			"length":    c.Length,
+			"nullable":  c.Nullable,
+		}
+	}
+	rowSet := res.rows
+	if rowSet == nil {
+		rowSet = [][]*string{}
+	}
+	writeJSON(w, map[string]any{
+		"success": true,
+		"code":    "0",
+		"data": map[string]any{
+			"queryId":           sf.NewUUID().String(),
+			"queryResultFormat": "json",
+			"statementTypeId":   statementTypeIDSelect,
+			"rowtype":           rowType,
+			"rowset":            rowSet,
+			"total":             len(rowSet),
+			"returned":          len(rowSet),
+		},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,55 @@
+package gosnowflaketest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	sf "github.com/snowflakedb/gosnowflake"
+)
+
+func TestServer(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	name := "alice"
+	server.AddResult("SELECT name, age FROM users", []Column{
+		{Name: "NAME", Type: "text", Length: 16},
+		{Name: "AGE", Type: "fixed", Precision: 38, Nullable: true},
+	}, [][]*string{{&name, nil}})
+
+	db := sql.OpenDB(sf.NewConnector(sf.SnowflakeDriver{}, *server.Config()))
+	defer db.Close()
+	ctx := context.Background()
+	if err := db.PingContext(ctx); err != nil {
+		t.Fatalf("failed to ping: %v", err)
+	}
+
+	var one int
+	if err := db.QueryRowContext(ctx, "SELECT 1").Scan(&one); err != nil {
+		t.Fatalf("failed to run SELECT 1: %v", err)
+	}
+	if one != 1 {
+		t.Errorf("expected 1, got %v", one)
+	}
+
+	var gotName string
+	var age sql.NullInt64
+	if err := db.QueryRowContext(ctx, "SELECT name, age FROM users").Scan(&gotName, &age); err != nil {
+		t.Fatalf("failed to run the registered query: %v", err)
+	}
+	if gotName != name || age.Valid {
+		t.Errorf("unexpected row: %v, %v", gotName, age)
+	}
+
+	_, err := db.QueryContext(ctx, "SELECT * FROM unknown")
+	var se *sf.SnowflakeError
+	if !errors.As(err, &se) || se.Number != 2003 {
+		t.Errorf("expected a SQL compilation error, got: %v", err)
+	}
+
+	queries := server.Queries()
+	if len(queries) == 0 || queries[len(queries)-1] != "SELECT * FROM unknown" {
+		t.Errorf("unexpected queries: %v", queries)
+	}
+}
@@ -87,7 +87,7 @@ func (hc *heartbeat) heartbeatMain() error {
 		return err
 	}
 	logger.Infof("HTTP: %v, URL: %v, Body: %v", resp.StatusCode, fullURL, b)
-	logger.Infof("Header: %v", resp.Header)
+	logger.Infof("Header: %v", redactHeaders(resp.Header))
 	return &SnowflakeError{
 		Number:   ErrFailedToHeartbeat,
 		SQLState: SQLStateConnectionFailure,
@@ -3,7 +3,9 @@ package gosnowflake
 import (
 	"context"
 	"database/sql/driver"
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"reflect"
 	"strconv"
 	"strings"
@@ -337,6 +339,55 @@ func TestQueryContextCacheDisabled(t *testing.T) {
 	})
 }
 
+func TestQueryContextNotSentWhenCacheDisabled(t *testing.T) {
+	var requests []map[string]json.RawMessage
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+		_ *url.Values, _ map[string]string, body []byte, _ time.Duration,
+		_ UUID, _ *Config) (*execResponse, error) {
+		var req map[string]json.RawMessage
+		assertNilF(t, json.Unmarshal(body, &req))
+		requests = append(requests, req)
+		return &execResponse{
+			Data: execResponseData{
+				QueryContext: json.RawMessage(`{"entries":[{"id":0,"timestamp":123,"priority":0,"context":"c29tZSBjb250ZXh0"}]}`),
+			},
+			Code:    "0",
+			Success: true,
+		}, nil
+	}
+	newConn := func(disabled bool) *snowflakeConn {
+		return &snowflakeConn{
+			cfg:                 &Config{Params: map[string]*string{}, DisableQueryContextCache: disabled},
+			rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+			queryContextCache:   (&queryContextCache{}).init(),
+			currentTimeProvider: defaultTimeProvider,
+		}
+	}
+
+	sc := newConn(false)
+	for i := 0; i < 2; i++ {
+		_, err := sc.ExecContext(context.Background(), "SELECT 1", nil)
+		assertNilF(t, err)
+	}
+	assertEqualE(t, len(sc.queryContextCache.entries), 1)
+	var sent requestQueryContext
+	assertNilF(t, json.Unmarshal(requests[1]["queryContextDTO"], &sent))
+	assertEqualE(t, len(sent.Entries), 1)
+	assertEqualE(t, sent.Entries[0].Timestamp, int64(123))
+
+	requests = nil
+	sc = newConn(true)
+	for i := 0; i < 2; i++ {
+		_, err := sc.ExecContext(context.Background(), "SELECT 1", nil)
+		assertNilF(t, err)
+	}
+	assertEqualE(t, len(sc.queryContextCache.entries), 0)
+	for _, req := range requests {
+		_, ok := req["queryContextDTO"]
+		assertFalseE(t, ok, "query context must not be sent when the cache is disabled")
+	}
+}
+
 func TestHybridTablesE2E(t *testing.T) {
 	skipOnJenkins(t, "HTAP is not enabled on environment")
 	if runningOnGithubAction() && !runningOnAWS() {
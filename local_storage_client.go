@@ -48,21 +48,11 @@ func (util *localUtil) uploadOneFileWithRetry(meta *fileMetadata) error {
 		return err
 	}
 	defer output.Close()
-	data := make([]byte, meta.uploadSize)
-	for {
-		n, err := frd.Read(data)
-		if err != nil && err != io.EOF {
-			return err
-		}
-		if n == 0 {
-			break
-		}
-
-		if _, err = output.Write(data); err != nil {
-			return err
-		}
+	written, err := io.Copy(output, frd)
+	if err != nil {
+		return err
 	}
-	meta.dstFileSize = meta.uploadSize
+	meta.dstFileSize = written
 	meta.resStatus = uploaded
 	return nil
 }
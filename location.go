@@ -96,9 +96,20 @@ func getCurrentLocation(params map[string]*string) *time.Location {
 	if tz, ok := params["timezone"]; ok && tz != nil {
 		loc, err = time.LoadLocation(*tz)
 		if err != nil {
+			logger.Warnf("failed to load the session time zone %v, using the local time zone instead. err: %v", *tz, err)
 			loc = time.Now().Location()
 		}
 	}
 	paramsMutex.Unlock()
 	return loc
 }
+
+// getTimestampLTZLocation returns the location TIMESTAMP_LTZ values are returned in:
+// Config.TimestampLTZTimezone if set, the TIMEZONE session parameter otherwise.
+// The configured time zone is resolved once when the connection is built.
+func (sc *snowflakeConn) getTimestampLTZLocation() *time.Location {
+	if sc.timestampLTZLocation != nil {
+		return sc.timestampLTZLocation
+	}
+	return getCurrentLocation(sc.cfg.Params)
+}
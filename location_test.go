@@ -1,8 +1,11 @@
 package gosnowflake
 
 import (
+	"context"
+	"database/sql/driver"
 	"errors"
 	"fmt"
+	"net/url"
 	"reflect"
 	"testing"
 	"time"
@@ -144,3 +147,49 @@ func TestGetCurrentLocation(t *testing.T) {
 		})
 	}
 }
+
+func TestTimestampLTZInSessionTimezone(t *testing.T) {
+	ltz := "1700000000.000000000" // 2023-11-14 22:13:20 UTC
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+		_ *url.Values, _ map[string]string, _ []byte, _ time.Duration,
+		_ UUID, _ *Config) (*execResponse, error) {
+		return &execResponse{
+			Data: execResponseData{
+				RowType:           []execResponseRowType{{Name: "ts", Type: "timestamp_ltz", Scale: 9}},
+				RowSet:            [][]*string{{&ltz}},
+				QueryResultFormat: "json",
+				StatementTypeID:   statementTypeIDSelect,
+			},
+			Code:    "0",
+			Success: true,
+		}, nil
+	}
+	queryLTZ := func(cfg *Config) time.Time {
+		sc, err := buildSnowflakeConn(context.Background(), *cfg)
+		assertNilF(t, err)
+		sc.rest.FuncPostQuery = postQueryMock
+		// the TIMEZONE session parameter as returned in the login response
+		sc.populateSessionParameters([]nameValueParameter{{Name: "TIMEZONE", Value: "America/New_York"}})
+		rows, err := sc.QueryContext(context.Background(), "SELECT ts", nil)
+		assertNilF(t, err)
+		defer rows.Close()
+		dest := make([]driver.Value, 1)
+		assertNilF(t, rows.Next(dest))
+		return dest[0].(time.Time)
+	}
+
+	ts := queryLTZ(&Config{Params: map[string]*string{}})
+	assertEqualE(t, ts.Location().String(), "America/New_York")
+	_, offset := ts.Zone()
+	assertEqualE(t, offset, -5*60*60)
+	assertTrueE(t, ts.Equal(time.Unix(1700000000, 0)))
+
+	ts = queryLTZ(&Config{Params: map[string]*string{}, TimestampLTZTimezone: "Asia/Tokyo"})
+	assertEqualE(t, ts.Location().String(), "Asia/Tokyo")
+	_, offset = ts.Zone()
+	assertEqualE(t, offset, 9*60*60)
+	assertTrueE(t, ts.Equal(time.Unix(1700000000, 0)))
+
+	err := fillMissingConfigParameters(&Config{Account: "a", User: "u", Password: "p", TimestampLTZTimezone: "Not/exists"})
+	assertEqualE(t, err.(*SnowflakeError).Number, ErrCodeInvalidTimezone)
+}
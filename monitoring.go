@@ -5,6 +5,7 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/url"
 	"strconv"
 )
@@ -110,9 +111,45 @@ type SnowflakeQueryStatus struct {
 	ProducedRows int64
 }
 
+// QueryExecutionStatus tells whether a query is still running, succeeded or failed.
+type QueryExecutionStatus int
+
+const (
+	// QueryExecutionUnknown is returned along with an error when the status cannot be fetched,
+	// or without one when the server reports a status the driver does not know
+	QueryExecutionUnknown QueryExecutionStatus = iota
+	// QueryExecutionRunning is the status of a query that is queued, running, restarted or waiting for a warehouse
+	QueryExecutionRunning
+	// QueryExecutionSucceeded is the status of a query whose result can be fetched
+	QueryExecutionSucceeded
+	// QueryExecutionFailed is the status of a query that failed or was aborted
+	QueryExecutionFailed
+)
+
+func (qes QueryExecutionStatus) String() string {
+	switch qes {
+	case QueryExecutionUnknown:
+		return "UNKNOWN"
+	case QueryExecutionRunning:
+		return "RUNNING"
+	case QueryExecutionSucceeded:
+		return "SUCCEEDED"
+	case QueryExecutionFailed:
+		return "FAILED"
+	default:
+		return fmt.Sprintf("UNKNOWN(%d)", int(qes))
+	}
+}
+
 // SnowflakeConnection is a wrapper to snowflakeConn that exposes API functions
 type SnowflakeConnection interface {
 	GetQueryStatus(ctx context.Context, queryID string) (*SnowflakeQueryStatus, error)
+	GetResultStatus(ctx context.Context, queryID string) (QueryExecutionStatus, error)
+	GetQueryResultByID(ctx context.Context, queryID string) (driver.Rows, error)
+	CopyFromReader(ctx context.Context, table string, reader io.Reader, options *CopyFromReaderOptions) (*CopyResult, error)
+	CopyInto(ctx context.Context, copyCommand string) ([]CopyLoadResult, error)
+	ListStage(ctx context.Context, location string) ([]StagedFile, error)
+	ShowAll(ctx context.Context, command string, pageSize int) (*ShowResult, error)
 }
 
 // checkQueryStatus returns the status given the query ID. If successful,
@@ -128,38 +165,12 @@ func (sc *snowflakeConn) checkQueryStatus(
 	ctx context.Context,
 	qid string) (
 	*retStatus, error) {
-	headers := make(map[string]string)
-	param := make(url.Values)
-	param.Set(requestGUIDKey, NewUUID().String())
-	if tok, _, _ := sc.rest.TokenAccessor.GetTokens(); tok != "" {
-		headers[headerAuthorizationKey] = fmt.Sprintf(headerSnowflakeToken, tok)
-	}
-	resultPath := fmt.Sprintf("%s/%s", monitoringQueriesPath, qid)
-	url := sc.rest.getFullURL(resultPath, &param)
-
-	res, err := sc.rest.FuncGet(ctx, sc.rest, url, headers, sc.rest.RequestTimeout)
+	queryRet, err := sc.fetchQueryStatus(ctx, qid)
 	if err != nil {
-		logger.WithContext(ctx).Errorf("failed to get response. err: %v", err)
 		return nil, err
 	}
-	defer res.Body.Close()
-	var statusResp = statusResponse{}
-	if err = json.NewDecoder(res.Body).Decode(&statusResp); err != nil {
-		logger.WithContext(ctx).Errorf("failed to decode JSON. err: %v", err)
-		return nil, err
-	}
-
-	if !statusResp.Success || len(statusResp.Data.Queries) == 0 {
-		logger.WithContext(ctx).Errorf("status query returned not-success or no status returned.")
-		return nil, (&SnowflakeError{
-			Number:  ErrQueryStatus,
-			Message: "status query returned not-success or no status returned. Please retry",
-		}).exceptionTelemetry(sc)
-	}
-
-	queryRet := statusResp.Data.Queries[0]
 	if queryRet.ErrorCode != "" {
-		return &queryRet, (&SnowflakeError{
+		return queryRet, (&SnowflakeError{
 			Number:         ErrQueryStatus,
 			Message:        errMsgQueryStatus,
 			MessageArgs:    []interface{}{queryRet.ErrorCode, queryRet.ErrorMessage},
@@ -171,7 +182,7 @@ func (sc *snowflakeConn) checkQueryStatus(
 	// returned errorCode is 0. Now check what is the returned status of the query.
 	qStatus := strToQueryStatus(queryRet.Status)
 	if qStatus.isError() {
-		return &queryRet, (&SnowflakeError{
+		return queryRet, (&SnowflakeError{
 			Number: ErrQueryReportedError,
 			Message: fmt.Sprintf("%s: status from server: [%s]",
 				queryRet.ErrorMessage, queryRet.Status),
@@ -181,7 +192,7 @@ func (sc *snowflakeConn) checkQueryStatus(
 	}
 
 	if qStatus.isRunning() {
-		return &queryRet, (&SnowflakeError{
+		return queryRet, (&SnowflakeError{
 			Number: ErrQueryIsRunning,
 			Message: fmt.Sprintf("%s: status from server: [%s]",
 				queryRet.ErrorMessage, queryRet.Status),
@@ -190,7 +201,42 @@ func (sc *snowflakeConn) checkQueryStatus(
 		}).exceptionTelemetry(sc)
 	}
 	//success
-	return &queryRet, nil
+	return queryRet, nil
+}
+
+// fetchQueryStatus returns the status of the query as reported by the monitoring endpoint.
+// It requires only a session of the same account, not the one that submitted the query.
+func (sc *snowflakeConn) fetchQueryStatus(ctx context.Context, qid string) (*retStatus, error) {
+	headers := make(map[string]string)
+	param := make(url.Values)
+	param.Set(requestGUIDKey, NewUUID().String())
+	if tok, _, _ := sc.rest.TokenAccessor.GetTokens(); tok != "" {
+		headers[headerAuthorizationKey] = fmt.Sprintf(headerSnowflakeToken, tok)
+	}
+	resultPath := fmt.Sprintf("%s/%s", monitoringQueriesPath, qid)
+	url := sc.rest.getFullURL(resultPath, &param)
+
+	res, err := sc.rest.FuncGet(ctx, sc.rest, url, headers, sc.rest.RequestTimeout)
+	if err != nil {
+		logger.WithContext(ctx).Errorf("failed to get response. err: %v", err)
+		return nil, err
+	}
+	defer res.Body.Close()
+	var statusResp = statusResponse{}
+	if err = json.NewDecoder(res.Body).Decode(&statusResp); err != nil {
+		logger.WithContext(ctx).Errorf("failed to decode JSON. err: %v", err)
+		return nil, err
+	}
+
+	if !statusResp.Success || len(statusResp.Data.Queries) == 0 {
+		logger.WithContext(ctx).Errorf("status query returned not-success or no status returned.")
+		return nil, (&SnowflakeError{
+			Number:  ErrQueryStatus,
+			Message: "status query returned not-success or no status returned. Please retry",
+		}).exceptionTelemetry(sc)
+	}
+
+	return &statusResp.Data.Queries[0], nil
 }
 
 func (sc *snowflakeConn) getQueryResultResp(
@@ -248,6 +294,28 @@ func (sc *snowflakeConn) rowsForRunningQuery(
 	return nil
 }
 
+// GetQueryResultByID fetches the result of a previously executed query without running it again.
+// If the query is still running, the rows are returned once it completes.
+// A *QueryResultExpiredError is returned if the result is no longer available.
+func (sc *snowflakeConn) GetQueryResultByID(ctx context.Context, queryID string) (driver.Rows, error) {
+	if !queryIDRegexp.MatchString(queryID) {
+		return nil, &SnowflakeError{
+			Number:  ErrQueryIDFormat,
+			Message: "Invalid QID",
+			QueryID: queryID,
+		}
+	}
+	_, err := sc.checkQueryStatus(ctx, queryID)
+	if snowflakeErr, ok := err.(*SnowflakeError); err != nil && (!ok || snowflakeErr.Number != ErrQueryIsRunning) {
+		return nil, err
+	}
+	rows, err := sc.buildRowsForRunningQuery(ctx, queryID)
+	if snowflakeErr, ok := err.(*SnowflakeError); ok && snowflakeErr.Number == ErrResultExpired {
+		return nil, &QueryResultExpiredError{QueryID: queryID, Err: err}
+	}
+	return rows, err
+}
+
 // prepare a Rows object to return for query of 'qid'
 func (sc *snowflakeConn) buildRowsForRunningQuery(
 	ctx context.Context,
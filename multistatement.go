@@ -8,6 +8,12 @@ import (
 	"strings"
 )
 
+// StatementResult describes a single statement of a multi-statement request.
+type StatementResult struct {
+	QueryID      string // query ID of the statement
+	AffectedRows int64  // number of rows modified by a DML statement, -1 for other statements
+}
+
 type childResult struct {
 	id  string
 	typ string
@@ -36,11 +42,13 @@ func (sc *snowflakeConn) handleMultiExec(
 			SQLState: data.SQLState,
 			Message:  errMsgNoResultIDs,
 			QueryID:  data.QueryID,
-		}).exceptionTelemetry(sc)
+		}).statementExceptionTelemetry(ctx, sc)
 	}
 	var updatedRows int64
 	childResults := getChildResults(data.ResultIDs, data.ResultTypes)
-	for _, child := range childResults {
+	statementResults := make([]StatementResult, len(childResults))
+	for i, child := range childResults {
+		statementResults[i] = StatementResult{QueryID: child.id, AffectedRows: -1}
 		resultPath := fmt.Sprintf(urlQueriesResultFmt, child.id)
 		childResultType, err := strconv.ParseInt(child.typ, 10, 64)
 		if err != nil {
@@ -53,30 +61,23 @@ func (sc *snowflakeConn) handleMultiExec(
 				return nil, err
 			}
 			if childData != nil && !childData.Success {
-				code, err := strconv.Atoi(childData.Code)
-				if err != nil {
-					return nil, err
-				}
-				return nil, (&SnowflakeError{
-					Number:   code,
-					SQLState: childData.Data.SQLState,
-					Message:  childData.Message,
-					QueryID:  childData.Data.QueryID,
-				}).exceptionTelemetry(sc)
+				return nil, sc.childResultError(ctx, i, childData)
 			}
 			count, err := updateRows(childData.Data)
 			if err != nil {
 				logger.WithContext(ctx).Errorf("error: %v", err)
 				return nil, err
 			}
+			statementResults[i].AffectedRows = count
 			updatedRows += count
 		}
 	}
 	logger.WithContext(ctx).Infof("number of updated rows: %#v", updatedRows)
 	return &snowflakeResult{
-		affectedRows: updatedRows,
-		insertID:     -1,
-		queryID:      data.QueryID,
+		affectedRows:     updatedRows,
+		insertID:         -1,
+		queryID:          data.QueryID,
+		statementResults: statementResults,
 	}, nil
 }
 
@@ -92,13 +93,43 @@ func (sc *snowflakeConn) handleMultiQuery(
 			SQLState: data.SQLState,
 			Message:  errMsgNoResultIDs,
 			QueryID:  data.QueryID,
-		}).exceptionTelemetry(sc)
+		}).statementExceptionTelemetry(ctx, sc)
 	}
 	childResults := getChildResults(data.ResultIDs, data.ResultTypes)
-	for _, child := range childResults {
-		if err := sc.rowsForRunningQuery(ctx, child.id, rows); err != nil {
+	rows.statementResults = make([]StatementResult, len(childResults))
+	for i, child := range childResults {
+		rows.statementResults[i] = StatementResult{QueryID: child.id, AffectedRows: -1}
+		resultPath := fmt.Sprintf(urlQueriesResultFmt, child.id)
+		childData, err := sc.getQueryResultResp(ctx, resultPath)
+		if err != nil {
+			logger.WithContext(ctx).Errorf("error: %v", err)
 			return err
 		}
+		if !childData.Success {
+			return sc.childResultError(ctx, i, childData)
+		}
+		if isDml(childData.Data.StatementTypeID) {
+			if rows.statementResults[i].AffectedRows, err = updateRows(childData.Data); err != nil {
+				return err
+			}
+		}
+		rows.addDownloader(populateChunkDownloader(ctx, sc, childData.Data))
 	}
 	return nil
 }
+
+// childResultError builds the error of a failed statement in a multi-statement request.
+// The statement is identified by its 1-based position in the request.
+func (sc *snowflakeConn) childResultError(ctx context.Context, index int, childData *execResponse) error {
+	code, err := strconv.Atoi(childData.Code)
+	if err != nil {
+		return err
+	}
+	return (&SnowflakeError{
+		Number:          code,
+		SQLState:        childData.Data.SQLState,
+		Message:         childData.Message,
+		QueryID:         childData.Data.QueryID,
+		StatementNumber: index + 1,
+	}).statementExceptionTelemetry(ctx, sc)
+}
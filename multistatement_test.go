@@ -1,9 +1,13 @@
 package gosnowflake
 
 import (
+	"bytes"
 	"context"
+	"database/sql/driver"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
@@ -11,6 +15,11 @@ import (
 	"reflect"
 	"testing"
 	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
 )
 
 func TestMultiStatementExecuteNoResultSet(t *testing.T) {
@@ -571,3 +580,196 @@ func TestUnitHandleMultiQuery(t *testing.T) {
 		}
 	})
 }
+
+func TestUnitMultiStatementResults(t *testing.T) {
+	insertID := "01aa3265-0405-ab7c-0000-53b106343aba"
+	updateID := "02aa3265-0405-ab7c-0000-53b106343aba"
+	selectID := "03aa3265-0405-ab7c-0000-53b106343aba"
+	countType := []execResponseRowType{{Name: "number of rows", Type: "fixed"}}
+	str := func(s string) *string { return &s }
+	childResponses := map[string]execResponse{
+		insertID: {Success: true, Data: execResponseData{QueryID: insertID, StatementTypeID: statementTypeIDDml + 0x100,
+			RowType: countType, RowSet: [][]*string{{str("2")}}, QueryResultFormat: "json"}},
+		updateID: {Success: true, Data: execResponseData{QueryID: updateID, StatementTypeID: statementTypeIDDml + 0x200,
+			RowType: countType, RowSet: [][]*string{{str("1")}}, QueryResultFormat: "json"}},
+		selectID: {Success: true, Data: execResponseData{QueryID: selectID, StatementTypeID: statementTypeIDSelect,
+			RowType: []execResponseRowType{{Name: "C1", Type: "text"}}, RowSet: [][]*string{{str("a")}, {str("b")}}, QueryResultFormat: "json"}},
+	}
+	getMock := func(_ context.Context, _ *snowflakeRestful, u *url.URL, _ map[string]string, _ time.Duration) (*http.Response, error) {
+		for id, resp := range childResponses {
+			if u.Path == fmt.Sprintf(urlQueriesResultFmt, id) {
+				ba, err := json.Marshal(resp)
+				assertNilF(t, err)
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(ba))}, nil
+			}
+		}
+		return nil, fmt.Errorf("unexpected path %v", u.Path)
+	}
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful, _ *url.Values, _ map[string]string, _ []byte, _ time.Duration, _ UUID, _ *Config) (*execResponse, error) {
+		return &execResponse{
+			Success: true,
+			Data: execResponseData{
+				QueryID:           "00aa3265-0405-ab7c-0000-53b106343aba",
+				StatementTypeID:   statementTypeIDMultistatement,
+				ResultIDs:         insertID + "," + updateID + "," + selectID,
+				ResultTypes:       fmt.Sprintf("%d,%d,%d", statementTypeIDDml+0x100, statementTypeIDDml+0x200, statementTypeIDSelect),
+				RowType:           []execResponseRowType{{Name: "multiple statement execution", Type: "text"}},
+				QueryResultFormat: "json",
+			},
+		}, nil
+	}
+	sc := &snowflakeConn{
+		cfg: &Config{Params: map[string]*string{}},
+		rest: &snowflakeRestful{
+			FuncPostQuery: postQueryMock,
+			FuncGet:       getMock,
+			TokenAccessor: getSimpleTokenAccessor(),
+		},
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+	}
+	ctx, err := WithMultiStatement(context.Background(), 3)
+	assertNilF(t, err)
+	query := "INSERT INTO t VALUES ('a'), ('b'); UPDATE t SET c1 = 'b' WHERE c1 = 'a'; SELECT c1 FROM t"
+	expected := []StatementResult{
+		{QueryID: insertID, AffectedRows: 2},
+		{QueryID: updateID, AffectedRows: 1},
+		{QueryID: selectID, AffectedRows: -1},
+	}
+
+	t.Run("exec", func(t *testing.T) {
+		res, err := sc.ExecContext(ctx, query, nil)
+		assertNilF(t, err)
+		statementResults, err := res.(SnowflakeResult).GetStatementResults()
+		assertNilF(t, err)
+		assertDeepEqualE(t, statementResults, expected)
+		affected, err := res.RowsAffected()
+		assertNilF(t, err)
+		assertEqualE(t, affected, int64(3))
+	})
+
+	t.Run("query", func(t *testing.T) {
+		rows, err := sc.QueryContext(ctx, query, nil)
+		assertNilF(t, err)
+		defer rows.Close()
+		statementResults, err := rows.(SnowflakeRows).GetStatementResults()
+		assertNilF(t, err)
+		assertDeepEqualE(t, statementResults, expected)
+
+		rowsWithResultSets := rows.(driver.RowsNextResultSet)
+		dest := make([]driver.Value, 1)
+		var resultSets int
+		var selected []driver.Value
+		for {
+			for rows.Next(dest) == nil {
+				if statementResults[resultSets].QueryID == selectID {
+					selected = append(selected, dest[0])
+				}
+			}
+			resultSets++
+			if !rowsWithResultSets.HasNextResultSet() {
+				break
+			}
+			assertNilF(t, rowsWithResultSets.NextResultSet())
+		}
+		assertEqualE(t, resultSets, len(expected))
+		assertDeepEqualE(t, selected, []driver.Value{"a", "b"})
+	})
+
+	t.Run("failed statement reports its position", func(t *testing.T) {
+		childResponses[updateID] = execResponse{Success: false, Code: "100038", Message: "Numeric value 'b' is not recognized",
+			Data: execResponseData{QueryID: updateID, SQLState: "22018"}}
+		_, err := sc.QueryContext(ctx, query, nil)
+		assertNotNilF(t, err)
+		var se *SnowflakeError
+		assertTrueF(t, errors.As(err, &se))
+		assertEqualE(t, se.StatementNumber, 2)
+		assertEqualE(t, se.QueryID, updateID)
+		assertStringContainsE(t, se.Error(), "statement 2:")
+	})
+}
+
+func TestUnitMultiStatementMixedResultFormats(t *testing.T) {
+	scalarID := "01aa3265-0405-ab7c-0000-53b106343aba"
+	selectID := "02aa3265-0405-ab7c-0000-53b106343aba"
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "C1", Type: arrow.BinaryTypes.String, Nullable: true}}, nil)
+	builder := array.NewRecordBuilder(memory.NewGoAllocator(), schema)
+	defer builder.Release()
+	builder.Field(0).(*array.StringBuilder).AppendValues([]string{"a", "b"}, nil)
+	record := builder.NewRecord()
+	defer record.Release()
+	var buf bytes.Buffer
+	writer := ipc.NewWriter(&buf, ipc.WithSchema(schema))
+	assertNilF(t, writer.Write(record))
+	assertNilF(t, writer.Close())
+
+	str := func(s string) *string { return &s }
+	childResponses := map[string]execResponse{
+		scalarID: {Success: true, Data: execResponseData{QueryID: scalarID, StatementTypeID: statementTypeIDSelect,
+			RowType: []execResponseRowType{{Name: "1", Type: "text"}, {Name: "'X'", Type: "text"}},
+			RowSet:  [][]*string{{str("1"), str("x")}}, QueryResultFormat: "json"}},
+		selectID: {Success: true, Data: execResponseData{QueryID: selectID, StatementTypeID: statementTypeIDSelect,
+			RowType:      []execResponseRowType{{Name: "C1", Type: "text", Nullable: true}},
+			RowSetBase64: base64.StdEncoding.EncodeToString(buf.Bytes()), QueryResultFormat: "arrow"}},
+	}
+	getMock := func(_ context.Context, _ *snowflakeRestful, u *url.URL, _ map[string]string, _ time.Duration) (*http.Response, error) {
+		for id, resp := range childResponses {
+			if u.Path == fmt.Sprintf(urlQueriesResultFmt, id) {
+				ba, err := json.Marshal(resp)
+				assertNilF(t, err)
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(ba))}, nil
+			}
+		}
+		return nil, fmt.Errorf("unexpected path %v", u.Path)
+	}
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful, _ *url.Values, _ map[string]string, _ []byte, _ time.Duration, _ UUID, _ *Config) (*execResponse, error) {
+		return &execResponse{
+			Success: true,
+			Data: execResponseData{
+				QueryID:           "00aa3265-0405-ab7c-0000-53b106343aba",
+				StatementTypeID:   statementTypeIDMultistatement,
+				ResultIDs:         scalarID + "," + selectID,
+				ResultTypes:       fmt.Sprintf("%d,%d", statementTypeIDSelect, statementTypeIDSelect),
+				RowType:           []execResponseRowType{{Name: "multiple statement execution", Type: "text"}},
+				QueryResultFormat: "json",
+			},
+		}, nil
+	}
+	sc := &snowflakeConn{
+		cfg: &Config{Params: map[string]*string{}},
+		rest: &snowflakeRestful{
+			FuncPostQuery: postQueryMock,
+			FuncGet:       getMock,
+			TokenAccessor: getSimpleTokenAccessor(),
+		},
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+	}
+	ctx, err := WithMultiStatement(context.Background(), 2)
+	assertNilF(t, err)
+	rows, err := sc.QueryContext(ctx, "SELECT 1, 'x'; SELECT c1 FROM t", nil)
+	assertNilF(t, err)
+	defer rows.Close()
+	sfRows := rows.(*snowflakeRows)
+
+	dest := make([]driver.Value, 2)
+	assertNilF(t, rows.Next(dest))
+	assertDeepEqualE(t, dest, []driver.Value{"1", "x"})
+	assertErrIsE(t, rows.Next(dest), io.EOF)
+	_, err = sfRows.GetArrowBatches()
+	assertNotNilE(t, err, "the JSON result set has no arrow batches")
+
+	assertTrueF(t, sfRows.HasNextResultSet())
+	assertNilF(t, sfRows.NextResultSet())
+	assertDeepEqualE(t, rows.Columns(), []string{"C1"})
+	dest = make([]driver.Value, 1)
+	var selected []driver.Value
+	for rows.Next(dest) == nil {
+		selected = append(selected, dest[0])
+	}
+	assertDeepEqualE(t, selected, []driver.Value{"a", "b"})
+	_, err = sfRows.GetArrowBatches()
+	assertNilE(t, err)
+	assertFalseE(t, sfRows.HasNextResultSet())
+}
@@ -590,9 +590,17 @@ func fullOCSPURL(url *url.URL) string {
 func getRevocationStatus(ctx context.Context, subject, issuer *x509.Certificate) *ocspStatus {
 	logger.WithContext(ctx).Tracef("Subject: %v, Issuer: %v", subject.Subject, issuer.Subject)
 
-	status, ocspReq, encodedCertID := validateWithCache(subject, issuer)
-	if isValidOCSPStatus(status.code) {
-		return status
+	cacheOptions := getOCSPCacheOptions(ctx)
+	var status *ocspStatus
+	var ocspReq []byte
+	var encodedCertID *certIDKey
+	if cacheOptions.inMemoryCacheDisabled {
+		status, ocspReq, encodedCertID = createOCSPRequest(subject, issuer)
+	} else {
+		status, ocspReq, encodedCertID = validateWithCache(subject, issuer)
+		if isValidOCSPStatus(status.code) {
+			return status
+		}
 	}
 	if ocspReq == nil || encodedCertID == nil {
 		return status
@@ -652,7 +660,7 @@ func getRevocationStatus(ctx context.Context, subject, issuer *x509.Certificate)
 	}
 	ocspClient := &http.Client{
 		Timeout:   timeout,
-		Transport: snowflakeNoOcspTransport,
+		Transport: getOCSPTransport(ctx),
 	}
 	ocspRes, ocspResBytes, ocspS := retryOCSP(
 		ctx, ocspClient, http.NewRequest, u, headers, ocspReq, issuer, timeout)
@@ -664,6 +672,9 @@ func getRevocationStatus(ctx context.Context, subject, issuer *x509.Certificate)
 	if !isValidOCSPStatus(ret.code) {
 		return ret // return invalid
 	}
+	if cacheOptions.inMemoryCacheDisabled {
+		return ret
+	}
 	v := &certCacheValue{float64(time.Now().UTC().Unix()), base64.StdEncoding.EncodeToString(ocspResBytes)}
 	ocspResponseCacheLock.Lock()
 	ocspResponseCache[*encodedCertID] = v
@@ -704,10 +715,10 @@ func verifyPeerCertificate(ctx context.Context, verifiedChains [][]*x509.Certifi
 	}
 
 	ocspResponseCacheLock.Lock()
-	if cacheUpdated {
+	if cacheUpdated && !getOCSPCacheOptions(ctx).onDiskCacheDisabled {
 		writeOCSPCacheFile()
+		cacheUpdated = false
 	}
-	cacheUpdated = false
 	ocspResponseCacheLock.Unlock()
 	return nil
 }
@@ -759,6 +770,15 @@ func validateWithCacheForAllCertificates(verifiedChains []*x509.Certificate) boo
 }
 
 func validateWithCache(subject, issuer *x509.Certificate) (*ocspStatus, []byte, *certIDKey) {
+	status, ocspReq, encodedCertID := createOCSPRequest(subject, issuer)
+	if encodedCertID == nil {
+		return status, ocspReq, nil
+	}
+	status = checkOCSPResponseCache(encodedCertID, subject, issuer)
+	return status, ocspReq, encodedCertID
+}
+
+func createOCSPRequest(subject, issuer *x509.Certificate) (*ocspStatus, []byte, *certIDKey) {
 	ocspReq, err := ocsp.CreateRequest(subject, issuer, &ocsp.RequestOptions{})
 	if err != nil {
 		logger.Errorf("failed to create OCSP request from the certificates.\n")
@@ -775,11 +795,10 @@ func validateWithCache(subject, issuer *x509.Certificate) (*ocspStatus, []byte,
 			err:  errors.New("failed to extract cert ID Key"),
 		}, ocspReq, nil
 	}
-	status := checkOCSPResponseCache(encodedCertID, subject, issuer)
-	return status, ocspReq, encodedCertID
+	return &ocspStatus{code: ocspSuccess}, ocspReq, encodedCertID
 }
 
-func downloadOCSPCacheServer() {
+func downloadOCSPCacheServer(transport http.RoundTripper) {
 	if strings.EqualFold(os.Getenv(cacheServerEnabledEnv), "false") {
 		return
 	}
@@ -803,7 +822,7 @@ func downloadOCSPCacheServer() {
 	}
 	ocspClient := &http.Client{
 		Timeout:   timeout,
-		Transport: snowflakeNoOcspTransport,
+		Transport: transport,
 	}
 	ret, ocspStatus := checkOCSPCacheServer(context.Background(), ocspClient, http.NewRequest, u, timeout)
 	if ocspStatus.code != ocspSuccess {
@@ -824,9 +843,11 @@ func downloadOCSPCacheServer() {
 }
 
 func getAllRevocationStatus(ctx context.Context, verifiedChains []*x509.Certificate) []*ocspStatus {
-	cached := validateWithCacheForAllCertificates(verifiedChains)
-	if !cached {
-		downloadOCSPCacheServer()
+	if !getOCSPCacheOptions(ctx).inMemoryCacheDisabled {
+		cached := validateWithCacheForAllCertificates(verifiedChains)
+		if !cached {
+			downloadOCSPCacheServer(getOCSPTransport(ctx))
+		}
 	}
 	n := len(verifiedChains) - 1
 	results := make([]*ocspStatus, n)
@@ -841,17 +862,54 @@ func getAllRevocationStatus(ctx context.Context, verifiedChains []*x509.Certific
 
 // verifyPeerCertificateSerial verifies the certificate revocation status in serial.
 func verifyPeerCertificateSerial(_ [][]byte, verifiedChains [][]*x509.Certificate) (err error) {
-	func() {
-		ocspModuleMu.Lock()
-		defer ocspModuleMu.Unlock()
-		if !ocspModuleInitialized {
-			initOcspModule()
-		}
-	}()
+	ensureOcspModuleInitialized()
 	overrideCacheDir()
 	return verifyPeerCertificate(context.Background(), verifiedChains)
 }
 
+// ocspCacheOptions disables the OCSP response caches for the revocation checks
+// of the transports built from a Config.
+type ocspCacheOptions struct {
+	inMemoryCacheDisabled bool // responses are neither read from nor added to the in-memory cache
+	onDiskCacheDisabled   bool // the in-memory cache is not written to the cache file
+}
+
+func getOCSPCacheOptions(ctx context.Context) ocspCacheOptions {
+	options, _ := ctx.Value(ocspCacheOptionsKey).(ocspCacheOptions)
+	return options
+}
+
+// getOCSPTransport returns the transport the OCSP responses are fetched with.
+func getOCSPTransport(ctx context.Context) http.RoundTripper {
+	if transport, ok := ctx.Value(ocspTransportKey).(http.RoundTripper); ok {
+		return transport
+	}
+	return snowflakeNoOcspTransport
+}
+
+// verifyPeerCertificateWithOptions returns a serial certificate revocation check which uses
+// the OCSP response caches according to the options and fetches the OCSP responses with the
+// transport, snowflakeNoOcspTransport if nil.
+func verifyPeerCertificateWithOptions(options ocspCacheOptions, transport http.RoundTripper) func([][]byte, [][]*x509.Certificate) error {
+	ctx := context.WithValue(context.Background(), ocspCacheOptionsKey, options)
+	if transport != nil {
+		ctx = context.WithValue(ctx, ocspTransportKey, transport)
+	}
+	return func(_ [][]byte, verifiedChains [][]*x509.Certificate) error {
+		ensureOcspModuleInitialized()
+		overrideCacheDir()
+		return verifyPeerCertificate(ctx, verifiedChains)
+	}
+}
+
+func ensureOcspModuleInitialized() {
+	ocspModuleMu.Lock()
+	defer ocspModuleMu.Unlock()
+	if !ocspModuleInitialized {
+		initOcspModule()
+	}
+}
+
 func overrideCacheDir() {
 	if os.Getenv(cacheDirEnv) != "" {
 		ocspResponseCacheLock.Lock()
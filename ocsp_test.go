@@ -4,14 +4,18 @@ import (
 	"bytes"
 	"context"
 	"crypto"
+	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
+	"math/big"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"testing"
@@ -612,3 +616,61 @@ func syncUpdateOcspResponseCache(f func()) {
 	defer ocspResponseCacheLock.Unlock()
 	f()
 }
+
+func TestOCSPCacheDisabledAsksResponderOnEachHandshake(t *testing.T) {
+	var responderCalls int
+	var caKey *rsa.PrivateKey
+	var ca *x509.Certificate
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/cache" {
+			_, _ = w.Write([]byte("{}"))
+			return
+		}
+		responderCalls++
+		body, err := io.ReadAll(r.Body)
+		assertNilF(t, err)
+		req, err := ocsp.ParseRequest(body)
+		assertNilF(t, err)
+		res, err := ocsp.CreateResponse(ca, ca, ocsp.Response{
+			Status:       ocsp.Good,
+			SerialNumber: req.SerialNumber,
+			ThisUpdate:   time.Now().Add(-time.Hour),
+			NextUpdate:   time.Now().Add(time.Hour),
+		}, caKey)
+		assertNilF(t, err)
+		w.Header().Set(httpHeaderContentType, "application/ocsp-response")
+		_, _ = w.Write(res)
+	}))
+	defer server.Close()
+	t.Setenv(cacheDirEnv, t.TempDir())
+	t.Setenv(cacheServerURLEnv, server.URL+"/cache")
+	caKey, ca = createCa(t, nil, nil, "OCSP root CA", "")
+
+	for _, tc := range []struct {
+		name          string
+		options       ocspCacheOptions
+		expectedCalls int
+	}{
+		{"caches enabled", ocspCacheOptions{}, 1},
+		{"in-memory cache disabled", ocspCacheOptions{inMemoryCacheDisabled: true}, 2},
+		{"on-disk cache disabled", ocspCacheOptions{onDiskCacheDisabled: true}, 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			responderCalls = 0
+			leafTemplate := &x509.Certificate{
+				SerialNumber: big.NewInt(time.Now().UnixNano()),
+				Subject:      pkix.Name{CommonName: "localhost"},
+				NotBefore:    time.Now(),
+				NotAfter:     time.Now().AddDate(1, 0, 0),
+				OCSPServer:   []string{server.URL},
+			}
+			_, leaf := createCert(t, leafTemplate, ca, caKey, "")
+			verify := verifyPeerCertificateWithOptions(tc.options, nil)
+
+			for i := 0; i < 2; i++ {
+				assertNilF(t, verify(nil, [][]*x509.Certificate{{leaf, ca}}))
+			}
+			assertEqualE(t, responderCalls, tc.expectedCalls)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package gosnowflake
+
+import (
+	"container/list"
+	"sync"
+)
+
+// preparedStatementMetadata is the part of a describe response that depends
+// only on the SQL text and the session context it was described in.
+type preparedStatementMetadata struct {
+	numberOfBinds int
+}
+
+type preparedStatementCacheEntry struct {
+	query    string
+	metadata *preparedStatementMetadata
+}
+
+// preparedStatementCache is a per-connection LRU cache of describe results
+// keyed by SQL text. A nil cache is valid and never holds any entries.
+type preparedStatementCache struct {
+	mutex    sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+func newPreparedStatementCache(capacity int) *preparedStatementCache {
+	if capacity <= 0 {
+		return nil
+	}
+	return &preparedStatementCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+func (psc *preparedStatementCache) get(query string) (*preparedStatementMetadata, bool) {
+	if psc == nil {
+		return nil, false
+	}
+	psc.mutex.Lock()
+	defer psc.mutex.Unlock()
+	elem, ok := psc.entries[query]
+	if !ok {
+		return nil, false
+	}
+	psc.order.MoveToFront(elem)
+	return elem.Value.(*preparedStatementCacheEntry).metadata, true
+}
+
+func (psc *preparedStatementCache) put(query string, metadata *preparedStatementMetadata) {
+	if psc == nil {
+		return
+	}
+	psc.mutex.Lock()
+	defer psc.mutex.Unlock()
+	if elem, ok := psc.entries[query]; ok {
+		elem.Value.(*preparedStatementCacheEntry).metadata = metadata
+		psc.order.MoveToFront(elem)
+		return
+	}
+	psc.entries[query] = psc.order.PushFront(&preparedStatementCacheEntry{query: query, metadata: metadata})
+	for psc.order.Len() > psc.capacity {
+		oldest := psc.order.Back()
+		psc.order.Remove(oldest)
+		delete(psc.entries, oldest.Value.(*preparedStatementCacheEntry).query)
+	}
+}
+
+func (psc *preparedStatementCache) len() int {
+	if psc == nil {
+		return 0
+	}
+	psc.mutex.Lock()
+	defer psc.mutex.Unlock()
+	return psc.order.Len()
+}
+
+// reset drops all cached describe results, e.g. when the session context
+// changed and the statements may resolve to different objects.
+func (psc *preparedStatementCache) reset() {
+	if psc == nil {
+		return
+	}
+	psc.mutex.Lock()
+	defer psc.mutex.Unlock()
+	psc.order.Init()
+	psc.entries = make(map[string]*list.Element)
+}
@@ -0,0 +1,105 @@
+package gosnowflake
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestPreparedStatementCacheDescribesOnce(t *testing.T) {
+	var describedQueries []string
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+		_ *url.Values, _ map[string]string, body []byte, _ time.Duration,
+		_ UUID, _ *Config) (*execResponse, error) {
+		var req execRequest
+		assertNilF(t, json.Unmarshal(body, &req))
+		assertTrueF(t, req.DescribeOnly, "only describe requests are expected")
+		describedQueries = append(describedQueries, req.SQLText)
+		return &execResponse{
+			Data: execResponseData{
+				NumberOfBinds:   2,
+				StatementTypeID: statementTypeIDSelect,
+			},
+			Code:    "0",
+			Success: true,
+		}, nil
+	}
+	sc := &snowflakeConn{
+		cfg:                 &Config{Params: map[string]*string{}},
+		rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+		preparedStatements:  newPreparedStatementCache(10),
+	}
+	query := "SELECT * FROM t WHERE a = ? AND b = ?"
+
+	for i := 0; i < 2; i++ {
+		stmt, err := sc.PrepareContext(context.Background(), query)
+		assertNilF(t, err)
+		assertEqualE(t, stmt.NumInput(), 2)
+	}
+	assertDeepEqualE(t, describedQueries, []string{query})
+}
+
+func TestPreparedStatementCacheDisabled(t *testing.T) {
+	sc := &snowflakeConn{
+		cfg:                 &Config{Params: map[string]*string{}},
+		rest:                &snowflakeRestful{},
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+		preparedStatements:  newPreparedStatementCache(0),
+	}
+	stmt, err := sc.PrepareContext(context.Background(), "SELECT ?")
+	assertNilF(t, err)
+	assertEqualE(t, stmt.NumInput(), -1)
+}
+
+func TestPreparedStatementCacheEviction(t *testing.T) {
+	psc := newPreparedStatementCache(2)
+	psc.put("a", &preparedStatementMetadata{numberOfBinds: 1})
+	psc.put("b", &preparedStatementMetadata{numberOfBinds: 2})
+	_, ok := psc.get("a")
+	assertTrueF(t, ok)
+	psc.put("c", &preparedStatementMetadata{numberOfBinds: 3})
+
+	assertEqualE(t, psc.len(), 2)
+	_, ok = psc.get("b")
+	assertFalseE(t, ok, "least recently used entry should be evicted")
+	metadata, ok := psc.get("a")
+	assertTrueF(t, ok)
+	assertEqualE(t, metadata.numberOfBinds, 1)
+	_, ok = psc.get("c")
+	assertTrueE(t, ok)
+
+	psc.reset()
+	assertEqualE(t, psc.len(), 0)
+}
+
+func TestPreparedStatementCacheResetOnSessionContextChange(t *testing.T) {
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+		_ *url.Values, _ map[string]string, _ []byte, _ time.Duration,
+		_ UUID, _ *Config) (*execResponse, error) {
+		return &execResponse{
+			Data: execResponseData{
+				FinalDatabaseName: "OTHER_DB",
+			},
+			Code:    "0",
+			Success: true,
+		}, nil
+	}
+	sc := &snowflakeConn{
+		cfg:                 &Config{Database: "DB", Params: map[string]*string{}},
+		rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+		preparedStatements:  newPreparedStatementCache(10),
+	}
+	sc.preparedStatements.put("SELECT 1", &preparedStatementMetadata{})
+
+	_, err := sc.ExecContext(context.Background(), "USE DATABASE OTHER_DB", nil)
+	assertNilF(t, err)
+	assertEqualE(t, sc.preparedStatements.len(), 0)
+	assertEqualE(t, sc.cfg.Database, "OTHER_DB")
+}
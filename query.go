@@ -12,6 +12,13 @@ const (
 	arrowFormat resultFormat = "arrow"
 )
 
+const (
+	// ResultFormatJSON is the JSON row format of query results
+	ResultFormatJSON = jsonFormat
+	// ResultFormatArrow is the Arrow format of query results
+	ResultFormatArrow = arrowFormat
+)
+
 type execBindParameter struct {
 	Type   string         `json:"type"`
 	Value  interface{}    `json:"value"`
@@ -28,7 +35,7 @@ type execRequest struct {
 	Parameters   map[string]interface{}       `json:"parameters,omitempty"`
 	Bindings     map[string]execBindParameter `json:"bindings,omitempty"`
 	BindStage    string                       `json:"bindStage,omitempty"`
-	QueryContext requestQueryContext          `json:"queryContextDTO,omitempty"`
+	QueryContext *requestQueryContext         `json:"queryContextDTO,omitempty"` // nil if the query context cache is disabled
 }
 
 type requestQueryContext struct {
@@ -47,14 +54,15 @@ type contextData struct {
 }
 
 type execResponseRowType struct {
-	Name       string          `json:"name"`
-	Fields     []fieldMetadata `json:"fields"`
-	ByteLength int64           `json:"byteLength"`
-	Length     int64           `json:"length"`
-	Type       string          `json:"type"`
-	Precision  int64           `json:"precision"`
-	Scale      int64           `json:"scale"`
-	Nullable   bool            `json:"nullable"`
+	Name            string          `json:"name"`
+	Fields          []fieldMetadata `json:"fields"`
+	ByteLength      int64           `json:"byteLength"`
+	Length          int64           `json:"length"`
+	Type            string          `json:"type"`
+	Precision       int64           `json:"precision"`
+	Scale           int64           `json:"scale"`
+	Nullable        bool            `json:"nullable"`
+	VectorDimension int64           `json:"vectorDimension"`
 }
 
 func (ex *execResponseRowType) toFieldMetadata() fieldMetadata {
@@ -66,6 +74,7 @@ func (ex *execResponseRowType) toFieldMetadata() fieldMetadata {
 		int(ex.Scale),
 		int(ex.Precision),
 		ex.Fields,
+		int(ex.VectorDimension),
 	}
 }
 
@@ -77,6 +86,8 @@ type fieldMetadata struct {
 	Scale     int             `json:"scale"`
 	Precision int             `json:"precision"`
 	Fields    []fieldMetadata `json:"fields,omitempty"`
+
+	VectorDimension int `json:"vectorDimension,omitempty"`
 }
 
 type execResponseChunk struct {
@@ -103,6 +114,7 @@ type execResponseStageInfo struct {
 	Region                string                  `json:"region,omitempty"`
 	StorageAccount        string                  `json:"storageAccount,omitempty"`
 	IsClientSideEncrypted bool                    `json:"isClientSideEncrypted,omitempty"`
+	EncryptionType        string                  `json:"encryptionType,omitempty"`
 	Creds                 execResponseCredentials `json:"creds,omitempty"`
 	PresignedURL          string                  `json:"presignedUrl,omitempty"`
 	EndPoint              string                  `json:"endPoint,omitempty"`
@@ -146,6 +158,9 @@ type execResponseData struct {
 	AsyncResult *snowflakeResult `json:"asyncResult,omitempty"`
 	AsyncRows   *snowflakeRows   `json:"asyncRows,omitempty"`
 
+	// files transferred by a PUT or GET command, set by the driver
+	FileTransferResults []FileTransferResult `json:"-"`
+
 	// file transfer response data
 	UploadInfo              execResponseStageInfo `json:"uploadInfo,omitempty"`
 	LocalLocation           string                `json:"localLocation,omitempty"`
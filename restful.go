@@ -3,11 +3,13 @@ package gosnowflake
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strconv"
+	"sync/atomic"
 	"time"
 )
 
@@ -33,6 +35,9 @@ const (
 	consoleLoginRequestPath  = "/console/login"
 )
 
+// cancelQueryTimeout limits the abort request sent after the context of a query is done.
+const cancelQueryTimeout = 10 * time.Second
+
 type (
 	funcGetType      func(context.Context, *snowflakeRestful, *url.URL, map[string]string, time.Duration) (*http.Response, error)
 	funcPostType     func(context.Context, *snowflakeRestful, *url.URL, map[string]string, []byte, time.Duration, currentTimeProvider, *Config) (*http.Response, error)
@@ -59,6 +64,8 @@ type snowflakeRestful struct {
 
 	Connection *snowflakeConn
 
+	sessionExpired atomic.Bool // set once Snowflake refused to renew the session
+
 	FuncPostQuery       func(context.Context, *snowflakeRestful, *url.Values, map[string]string, []byte, time.Duration, UUID, *Config) (*execResponse, error)
 	FuncPostQueryHelper func(context.Context, *snowflakeRestful, *url.Values, map[string]string, []byte, time.Duration, UUID, *Config) (*execResponse, error)
 	FuncPost            funcPostType
@@ -113,7 +120,12 @@ func (sr *snowflakeRestful) renewExpiredSessionToken(ctx context.Context, timeou
 	currentToken, _, _ := sr.TokenAccessor.GetTokens()
 	if expiredToken == currentToken || currentToken == "" {
 		// Only renew the session if the current token is still the expired token or current token is empty
-		return sr.FuncRenewSession(ctx, sr, timeout)
+		err = sr.FuncRenewSession(ctx, sr, timeout)
+		if sfErr, ok := err.(*SnowflakeError); ok && sfErr.Number != ErrFailedToRenewSession {
+			// the server rejected the renewal, e.g. because the master token expired as well
+			sr.sessionExpired.Store(true)
+		}
+		return err
 	}
 	return nil
 }
@@ -202,9 +214,14 @@ func postRestfulQuery(
 
 	data, err = sr.FuncPostQueryHelper(ctx, sr, params, headers, body, timeout, requestID, cfg)
 
-	if err == context.Canceled || err == context.DeadlineExceeded {
-		// For context cancel/timeout cases, a special cancel request needs to be sent.
-		if cancelErr := sr.FuncCancelQuery(context.Background(), sr, requestID, timeout); cancelErr != nil {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		// For context cancel/timeout cases, a special cancel request needs to be sent,
+		// otherwise the query keeps running on the server. ctx is already done,
+		// so the request gets its own short timeout.
+		logger.WithContext(ctx).Infof("query context is done, aborting request %v", requestID)
+		cancelCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cancelQueryTimeout)
+		defer cancel()
+		if cancelErr := sr.FuncCancelQuery(cancelCtx, sr, requestID, cancelQueryTimeout); cancelErr != nil {
 			// Wrap the original error with the cancel error.
 			err = fmt.Errorf("failed to cancel query. cancelErr: %w, queryErr: %w", cancelErr, err)
 		}
@@ -260,6 +277,8 @@ func postRestfulQueryHelper(
 		}
 
 		isSessionRenewed := false
+		// the warehouse of a query is resumed before the query starts, it is not checked anymore afterwards
+		checkWarehouse := cfg != nil && cfg.FailIfWarehouseSuspended
 
 		// if asynchronous query in progress, kick off retrieval but return object
 		if respd.Code == queryInProgressAsyncCode && isAsyncMode(ctx) {
@@ -275,6 +294,12 @@ func postRestfulQueryHelper(
 			token, _, _ = sr.TokenAccessor.GetTokens()
 			headers[headerAuthorizationKey] = fmt.Sprintf(headerSnowflakeToken, token)
 
+			if checkWarehouse && !isSessionRenewed {
+				if checkWarehouse, err = sr.failIfWarehouseResuming(ctx, respd.Data.QueryID, requestID, headers, timeout); err != nil {
+					return nil, err
+				}
+			}
+
 			resp, err = sr.FuncGet(ctx, sr, fullURL, headers, timeout)
 			if err != nil {
 				logger.WithContext(ctx).Errorf("failed to get response. err: %v", err)
@@ -304,7 +329,7 @@ func postRestfulQueryHelper(
 		return nil, err
 	}
 	logger.WithContext(ctx).Infof("HTTP: %v, URL: %v, Body: %v", resp.StatusCode, fullURL, b)
-	logger.WithContext(ctx).Infof("Header: %v", resp.Header)
+	logger.WithContext(ctx).Infof("Header: %v", redactHeaders(resp.Header))
 	return nil, &SnowflakeError{
 		Number:      ErrFailedToPostQuery,
 		SQLState:    SQLStateConnectionFailure,
@@ -313,6 +338,56 @@ func postRestfulQueryHelper(
 	}
 }
 
+// failIfWarehouseResuming checks the status of a query the server reported as still in progress
+// and aborts it when it waits for its suspended warehouse to resume. It reports whether the query
+// is still waiting to start, so the status needs to be checked again on the next poll. Failing to
+// get the status is not an error, the query is then polled as usual without further checks.
+func (sr *snowflakeRestful) failIfWarehouseResuming(
+	ctx context.Context,
+	queryID string,
+	requestID UUID,
+	headers map[string]string,
+	timeout time.Duration) (bool, error) {
+	param := make(url.Values)
+	param.Set(requestGUIDKey, NewUUID().String())
+	fullURL := sr.getFullURL(fmt.Sprintf("%s/%s", monitoringQueriesPath, queryID), &param)
+	resp, err := sr.FuncGet(ctx, sr, fullURL, headers, timeout)
+	if err != nil {
+		logger.WithContext(ctx).Warnf("failed to get the status of query %v. err: %v", queryID, err)
+		return false, nil
+	}
+	defer resp.Body.Close()
+	var statusResp statusResponse
+	if err = json.NewDecoder(resp.Body).Decode(&statusResp); err != nil {
+		logger.WithContext(ctx).Warnf("failed to decode the status of query %v. err: %v", queryID, err)
+		return false, nil
+	}
+	if !statusResp.Success {
+		logger.WithContext(ctx).Warnf("failed to get the status of query %v. err: %v", queryID, statusResp.Message)
+		return false, nil
+	}
+	if len(statusResp.Data.Queries) == 0 {
+		return true, nil
+	}
+	switch strToQueryStatus(statusResp.Data.Queries[0].Status) {
+	case SFQueryResumingWarehouse:
+	case SFQueryQueued, SFQueryQueueRepairingWarehouse, SFQueryBlocked, SFQueryNoData:
+		return true, nil
+	default:
+		return false, nil
+	}
+	logger.WithContext(ctx).Infof("query %v is waiting for a suspended warehouse, aborting it", queryID)
+	if err = sr.FuncCancelQuery(ctx, sr, requestID, timeout); err != nil {
+		logger.WithContext(ctx).Warnf("failed to abort query %v. err: %v", queryID, err)
+	}
+	return false, &SnowflakeError{
+		Number:         ErrWarehouseSuspended,
+		Message:        errMsgWarehouseSuspended,
+		QueryID:        queryID,
+		IncludeQueryID: true,
+	}
+}
+
 func closeSession(ctx context.Context, sr *snowflakeRestful, timeout time.Duration) error {
 	logger.WithContext(ctx).Info("close session")
 	params := &url.Values{}
@@ -354,7 +429,7 @@ func closeSession(ctx context.Context, sr *snowflakeRestful, timeout time.Durati
 		return err
 	}
 	logger.WithContext(ctx).Infof("HTTP: %v, URL: %v, Body: %v", resp.StatusCode, fullURL, b)
-	logger.WithContext(ctx).Infof("Header: %v", resp.Header)
+	logger.WithContext(ctx).Infof("Header: %v", redactHeaders(resp.Header))
 	return &SnowflakeError{
 		Number:      ErrFailedToCloseSession,
 		SQLState:    SQLStateConnectionFailure,
@@ -415,7 +490,7 @@ func renewRestfulSession(ctx context.Context, sr *snowflakeRestful, timeout time
 		return err
 	}
 	logger.WithContext(ctx).Infof("HTTP: %v, URL: %v, Body: %v", resp.StatusCode, fullURL, b)
-	logger.WithContext(ctx).Infof("Header: %v", resp.Header)
+	logger.WithContext(ctx).Infof("Header: %v", redactHeaders(resp.Header))
 	return &SnowflakeError{
 		Number:      ErrFailedToRenewSession,
 		SQLState:    SQLStateConnectionFailure,
@@ -494,7 +569,7 @@ func cancelQuery(ctx context.Context, sr *snowflakeRestful, requestID UUID, time
 		return err
 	}
 	logger.WithContext(ctx).Infof("HTTP: %v, URL: %v, Body: %v", resp.StatusCode, fullURL, b)
-	logger.WithContext(ctx).Infof("Header: %v", resp.Header)
+	logger.WithContext(ctx).Infof("Header: %v", redactHeaders(resp.Header))
 	return &SnowflakeError{
 		Number:      ErrFailedToCancelQuery,
 		SQLState:    SQLStateConnectionFailure,
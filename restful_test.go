@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -619,3 +621,143 @@ func TestCancelRetry(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestPostQueryAbortsQueryOnContextCancel(t *testing.T) {
+	requestID := NewUUID()
+	var abortedRequestID string
+	var abortCtxErr error
+	sr := &snowflakeRestful{
+		Protocol: "https",
+		Host:     "abc.com",
+		Port:     443,
+		FuncPost: func(ctx context.Context, _ *snowflakeRestful, fullURL *url.URL, _ map[string]string, body []byte, _ time.Duration, _ currentTimeProvider, _ *Config) (*http.Response, error) {
+			if fullURL.Path == abortRequestPath {
+				abortCtxErr = ctx.Err()
+				var req map[string]string
+				assertNilF(t, json.Unmarshal(body, &req))
+				abortedRequestID = req[requestIDKey]
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`{"success":true}`)),
+				}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"code":"333333","success":true,"data":{"getResultUrl":"/queries/1/result"}}`)),
+			}, nil
+		},
+		FuncGet: func(ctx context.Context, _ *snowflakeRestful, _ *url.URL, _ map[string]string, _ time.Duration) (*http.Response, error) {
+			// the query is still running when the context gets cancelled
+			<-ctx.Done()
+			return nil, fmt.Errorf("getting query result: %w", ctx.Err())
+		},
+		FuncPostQueryHelper: postRestfulQueryHelper,
+		FuncCancelQuery:     cancelQuery,
+		TokenAccessor:       getSimpleTokenAccessor(),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := postRestfulQuery(ctx, sr, &url.Values{}, map[string]string{}, []byte{}, 0, requestID, nil)
+	assertErrIsE(t, err, context.DeadlineExceeded)
+	assertEqualE(t, abortedRequestID, requestID.String())
+	assertNilE(t, abortCtxErr, "abort request should not use the cancelled context")
+}
+
+func TestPostQueryFailsFastOnSuspendedWarehouse(t *testing.T) {
+	requestID := NewUUID()
+	var abortedRequestID string
+	var resultFetched bool
+	sr := &snowflakeRestful{
+		Protocol: "https",
+		Host:     "abc.com",
+		Port:     443,
+		FuncPost: func(_ context.Context, _ *snowflakeRestful, fullURL *url.URL, _ map[string]string, body []byte, _ time.Duration, _ currentTimeProvider, _ *Config) (*http.Response, error) {
+			if fullURL.Path == abortRequestPath {
+				var req map[string]string
+				assertNilF(t, json.Unmarshal(body, &req))
+				abortedRequestID = req[requestIDKey]
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`{"success":true}`)),
+				}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"code":"333333","success":true,"data":{"queryId":"qid","getResultUrl":"/queries/qid/result"}}`)),
+			}, nil
+		},
+		FuncGet: func(_ context.Context, _ *snowflakeRestful, fullURL *url.URL, _ map[string]string, _ time.Duration) (*http.Response, error) {
+			if fullURL.Path == monitoringQueriesPath+"/qid" {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`{"success":true,"data":{"queries":[{"id":"qid","status":"RESUMING_WAREHOUSE"}]}}`)),
+				}, nil
+			}
+			resultFetched = true
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"code":"0","success":true,"data":{"queryId":"qid"}}`)),
+			}, nil
+		},
+		FuncPostQueryHelper: postRestfulQueryHelper,
+		FuncCancelQuery:     cancelQuery,
+		TokenAccessor:       getSimpleTokenAccessor(),
+	}
+
+	_, err := postRestfulQuery(context.Background(), sr, &url.Values{}, map[string]string{}, []byte{}, 0, requestID,
+		&Config{FailIfWarehouseSuspended: true})
+	assertTrueF(t, IsWarehouseSuspended(err), fmt.Sprintf("unexpected error: %v", err))
+	var se *SnowflakeError
+	assertTrueF(t, errors.As(err, &se))
+	assertEqualE(t, se.QueryID, "qid")
+	assertEqualE(t, abortedRequestID, requestID.String())
+	assertFalseE(t, resultFetched, "the result should not be polled once the query is aborted")
+
+	resultFetched = false
+	_, err = postRestfulQuery(context.Background(), sr, &url.Values{}, map[string]string{}, []byte{}, 0, requestID, &Config{})
+	assertNilF(t, err)
+	assertTrueE(t, resultFetched)
+}
+
+func TestPostQueryChecksWarehouseUntilQueryStarts(t *testing.T) {
+	var statusChecks, resultPolls int
+	sr := &snowflakeRestful{
+		Protocol: "https",
+		Host:     "abc.com",
+		Port:     443,
+		FuncPost: func(_ context.Context, _ *snowflakeRestful, _ *url.URL, _ map[string]string, _ []byte, _ time.Duration, _ currentTimeProvider, _ *Config) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"code":"333333","success":true,"data":{"queryId":"qid","getResultUrl":"/queries/qid/result"}}`)),
+			}, nil
+		},
+		FuncGet: func(_ context.Context, _ *snowflakeRestful, fullURL *url.URL, _ map[string]string, _ time.Duration) (*http.Response, error) {
+			if fullURL.Path == monitoringQueriesPath+"/qid" {
+				statusChecks++
+				status := "QUEUED"
+				if statusChecks > 1 {
+					status = "RUNNING"
+				}
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`{"success":true,"data":{"queries":[{"id":"qid","status":"` + status + `"}]}}`)),
+				}, nil
+			}
+			resultPolls++
+			body := `{"code":"333333","success":true,"data":{"queryId":"qid","getResultUrl":"/queries/qid/result"}}`
+			if resultPolls == 5 {
+				body = `{"code":"0","success":true,"data":{"queryId":"qid"}}`
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+		},
+		FuncPostQueryHelper: postRestfulQueryHelper,
+		TokenAccessor:       getSimpleTokenAccessor(),
+	}
+
+	_, err := postRestfulQuery(context.Background(), sr, &url.Values{}, map[string]string{}, []byte{}, 0, NewUUID(),
+		&Config{FailIfWarehouseSuspended: true})
+	assertNilF(t, err)
+	assertEqualE(t, resultPolls, 5)
+	assertEqualE(t, statusChecks, 2, "the status should not be checked once the query runs")
+}
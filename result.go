@@ -18,15 +18,17 @@ type SnowflakeResult interface {
 	GetQueryID() string
 	GetStatus() queryStatus
 	GetArrowBatches() ([]*ArrowBatch, error)
+	GetStatementResults() ([]StatementResult, error)
 }
 
 type snowflakeResult struct {
-	affectedRows int64
-	insertID     int64 // Snowflake doesn't support last insert id
-	queryID      string
-	status       queryStatus
-	err          error
-	errChannel   chan error
+	affectedRows     int64
+	insertID         int64 // Snowflake doesn't support last insert id
+	queryID          string
+	status           queryStatus
+	err              error
+	errChannel       chan error
+	statementResults []StatementResult
 }
 
 func (res *snowflakeResult) LastInsertId() (int64, error) {
@@ -58,6 +60,19 @@ func (res *snowflakeResult) GetArrowBatches() ([]*ArrowBatch, error) {
 	}
 }
 
+// GetStatementResults returns the query ID and the number of affected rows of every
+// statement of a multi-statement request, in the order the statements were sent.
+// For a single statement it returns one entry describing the statement itself.
+func (res *snowflakeResult) GetStatementResults() ([]StatementResult, error) {
+	if err := res.waitForAsyncExecStatus(); err != nil {
+		return nil, err
+	}
+	if res.statementResults == nil {
+		return []StatementResult{{QueryID: res.queryID, AffectedRows: res.affectedRows}}, nil
+	}
+	return res.statementResults, nil
+}
+
 func (res *snowflakeResult) waitForAsyncExecStatus() error {
 	// if async exec, block until execution is finished
 	if res.status == QueryStatusInProgress {
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"math"
 	"math/rand"
 	"net/http"
@@ -210,19 +211,37 @@ func (w *waitAlgo) calculateWaitBeforeRetryForAuthRequest(attempt int, currWaitT
 	return time.Duration(jitteredSleepTime * float64(time.Second))
 }
 
-func (w *waitAlgo) calculateWaitBeforeRetry(sleep time.Duration) time.Duration {
+// full jitter backoff: a random duration between 0 and min(cap, base * 2^(attempt-1))
+func (w *waitAlgo) calculateWaitBeforeRetry(attempt int) time.Duration {
 	w.mutex.Lock()
 	defer w.mutex.Unlock()
-	// use decorrelated jitter in retry time
-	randDuration := randMilliSecondDuration(w.base, sleep*3)
-	return durationMin(w.cap, randDuration)
+	ceiling := w.base
+	for i := 1; i < attempt && ceiling < w.cap; i++ {
+		ceiling *= 2
+	}
+	ceiling = durationMin(w.cap, ceiling)
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(w.random.Int63n(int64(ceiling) + 1))
 }
 
-func randMilliSecondDuration(base time.Duration, bound time.Duration) time.Duration {
-	baseNumber := int64(base / time.Millisecond)
-	boundNumber := int64(bound / time.Millisecond)
-	randomDuration := random.Int63n(boundNumber-baseNumber) + baseNumber
-	return time.Duration(randomDuration) * time.Millisecond
+// retryAfter returns the wait time requested by the server with the Retry-After header of 429 responses.
+func retryAfter(res *http.Response) (time.Duration, bool) {
+	if res == nil || res.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	value := res.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return durationMax(time.Until(date), 0), true
+	}
+	return 0, false
 }
 
 func (w *waitAlgo) getJitter(currWaitTime float64) float64 {
@@ -275,6 +294,26 @@ func newRetryHTTP(ctx context.Context,
 	return &instance
 }
 
+// waitAlgo returns the backoff algorithm using the retry backoff configured for the connection.
+func (r *retryHTTP) waitAlgo() *waitAlgo {
+	return waitAlgoFor(r.cfg)
+}
+
+// waitAlgoFor returns the backoff configured by RetryBackoffBase and RetryBackoffCap of the config.
+func waitAlgoFor(cfg *Config) *waitAlgo {
+	if cfg == nil || (cfg.RetryBackoffBase <= 0 && cfg.RetryBackoffCap <= 0) {
+		return defaultWaitAlgo
+	}
+	w := *defaultWaitAlgo
+	if cfg.RetryBackoffBase > 0 {
+		w.base = cfg.RetryBackoffBase
+	}
+	if cfg.RetryBackoffCap > 0 {
+		w.cap = cfg.RetryBackoffCap
+	}
+	return &w
+}
+
 func (r *retryHTTP) doPost() *retryHTTP {
 	r.method = "POST"
 	return r
@@ -320,7 +359,14 @@ func (r *retryHTTP) execute() (res *http.Response, err error) {
 		for k, v := range r.headers {
 			req.Header.Set(k, v)
 		}
+		if req != nil {
+			logger.WithContext(r.ctx).Debugf("request: %v %v, headers: %v", r.method, redactURL(r.fullURL.String()), redactHeaders(req.Header))
+		}
 		res, err = r.client.Do(req)
+		if err != nil && r.ctx.Err() != nil {
+			// the request was aborted by the context, retrying makes no sense
+			return nil, r.ctx.Err()
+		}
 		// check if it can retry.
 		retryable, err := isRetryableError(req, res, err)
 		if !retryable {
@@ -334,12 +380,14 @@ func (r *retryHTTP) execute() (res *http.Response, err error) {
 				"failed http connection. HTTP Status: %v. retrying...\n", res.StatusCode)
 			res.Body.Close()
 		}
-		// uses exponential jitter backoff
+		// uses exponential jitter backoff unless the server asks for a specific wait time
 		retryCounter++
-		if isLoginRequest(req) {
+		if wait, ok := retryAfter(res); ok {
+			sleepTime = wait
+		} else if isLoginRequest(req) {
 			sleepTime = defaultWaitAlgo.calculateWaitBeforeRetryForAuthRequest(retryCounter, sleepTime)
 		} else {
-			sleepTime = defaultWaitAlgo.calculateWaitBeforeRetry(sleepTime)
+			sleepTime = r.waitAlgo().calculateWaitBeforeRetry(retryCounter)
 		}
 
 		if totalTimeout > 0 {
@@ -372,21 +420,34 @@ func (r *retryHTTP) execute() (res *http.Response, err error) {
 			retryReason = res.StatusCode
 		}
 		r.fullURL = retryReasonUpdater.replaceOrAdd(retryReason)
+		logStructured(r.ctx, r.cfg, slog.LevelWarn, "retrying request",
+			slog.String(slogRequestIDKey, r.fullURL.Query().Get(requestIDKey)),
+			slog.Int(slogAttemptKey, retryCounter),
+			slog.Int("retry_reason", retryReason),
+			slog.Duration("sleep", sleepTime))
 		r.fullURL = ensureClientStartTimeIsSet(r.fullURL, clientStartTime)
 		logger.WithContext(r.ctx).Infof("sleeping %v. to timeout: %v. retrying", sleepTime, totalTimeout)
 		logger.WithContext(r.ctx).Infof("retry count: %v, retry reason: %v", retryCounter, retryReason)
 
-		await := time.NewTimer(sleepTime)
-		select {
-		case <-await.C:
-			// retry the request
-		case <-r.ctx.Done():
-			await.Stop()
-			return res, r.ctx.Err()
+		if err = sleepWithContext(r.ctx, sleepTime); err != nil {
+			return nil, err
 		}
 	}
 }
 
+// sleepWithContext waits for the given duration. It returns the context error
+// as soon as the context is cancelled or its deadline passes.
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	await := time.NewTimer(d)
+	defer await.Stop()
+	select {
+	case <-await.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func isRetryableError(req *http.Request, res *http.Response, err error) (bool, error) {
 	if err != nil && res == nil { // Failed http connection. Most probably client timeout.
 		return true, err
@@ -3,9 +3,11 @@ package gosnowflake
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"strconv"
 	"strings"
@@ -607,39 +609,126 @@ func TestCalculateRetryWait(t *testing.T) {
 }
 
 func TestCalculateRetryWaitForNonAuthRequests(t *testing.T) {
-	// test for randomly selected currWaitTime values
-	// maxSleepTime is the limit value
+	// full jitter: the wait time is between 0 and min(cap, base * 2^(attempt-1))
 	tcs := []struct {
-		currWaitTime float64
+		attempt      int
 		maxSleepTime float64
 	}{
 		{
-			currWaitTime: 3.346609,
-			maxSleepTime: 10.039827,
+			attempt:      1,
+			maxSleepTime: 1,
 		},
 		{
-			currWaitTime: 4.260357,
-			maxSleepTime: 12.781071,
+			attempt:      2,
+			maxSleepTime: 2,
 		},
 		{
-			currWaitTime: 5.154231,
-			maxSleepTime: 15.462693,
+			attempt:      4,
+			maxSleepTime: 8,
 		},
 		{
-			currWaitTime: 7.249255,
+			attempt:      5,
 			maxSleepTime: 16,
 		},
 		{
-			currWaitTime: 23.598257,
+			attempt:      20,
 			maxSleepTime: 16,
 		},
 	}
 
 	for _, tc := range tcs {
-		defaultMinSleepTime := 1
-		t.Run(fmt.Sprintf("currWaitTime: %v", tc.currWaitTime), func(t *testing.T) {
-			result := defaultWaitAlgo.calculateWaitBeforeRetry(time.Duration(tc.currWaitTime) * time.Second)
-			assertBetweenInclusiveE(t, result.Seconds(), float64(defaultMinSleepTime), tc.maxSleepTime)
+		t.Run(fmt.Sprintf("attempt: %v", tc.attempt), func(t *testing.T) {
+			result := defaultWaitAlgo.calculateWaitBeforeRetry(tc.attempt)
+			assertBetweenInclusiveE(t, result.Seconds(), 0, tc.maxSleepTime)
 		})
 	}
 }
+
+func TestRetryBackoffWithJitter(t *testing.T) {
+	var requestTimes []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestTimes = append(requestTimes, time.Now())
+		if len(requestTimes) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	urlPtr, err := url.Parse(server.URL + "/session/heartbeat")
+	assertNilF(t, err)
+	cfg := &Config{RetryBackoffBase: 100 * time.Millisecond, RetryBackoffCap: 150 * time.Millisecond}
+
+	res, err := newRetryHTTP(context.Background(), server.Client(), http.NewRequest, urlPtr,
+		make(map[string]string), 10*time.Second, 5, defaultTimeProvider, cfg).execute()
+	assertNilF(t, err)
+	assertEqualE(t, res.StatusCode, http.StatusOK)
+	assertEqualF(t, len(requestTimes), 3)
+	// the first retry waits up to the base, the second one up to the cap instead of twice the base
+	slack := 100 * time.Millisecond
+	assertBetweenInclusiveE(t, requestTimes[1].Sub(requestTimes[0]).Seconds(), 0, (100*time.Millisecond + slack).Seconds())
+	assertBetweenInclusiveE(t, requestTimes[2].Sub(requestTimes[1]).Seconds(), 0, (150*time.Millisecond + slack).Seconds())
+}
+
+func TestRetryAfterHeaderIsHonored(t *testing.T) {
+	var requestTimes []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestTimes = append(requestTimes, time.Now())
+		if len(requestTimes) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	urlPtr, err := url.Parse(server.URL + "/session/heartbeat")
+	assertNilF(t, err)
+	cfg := &Config{RetryBackoffBase: time.Millisecond, RetryBackoffCap: time.Millisecond}
+
+	res, err := newRetryHTTP(context.Background(), server.Client(), http.NewRequest, urlPtr,
+		make(map[string]string), 10*time.Second, 5, defaultTimeProvider, cfg).execute()
+	assertNilF(t, err)
+	assertEqualE(t, res.StatusCode, http.StatusOK)
+	assertEqualF(t, len(requestTimes), 2)
+	assertTrueE(t, requestTimes[1].Sub(requestTimes[0]) >= time.Second, "Retry-After should take precedence over the backoff")
+}
+
+func TestRetryBackoffAbortsOnContextDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	urlPtr, err := url.Parse(server.URL + "/session/heartbeat")
+	assertNilF(t, err)
+	cfg := &Config{RetryBackoffBase: 5 * time.Second, RetryBackoffCap: 5 * time.Second}
+	deadline := 300 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), deadline)
+	defer cancel()
+
+	start := time.Now()
+	res, err := newRetryHTTP(ctx, server.Client(), http.NewRequest, urlPtr,
+		make(map[string]string), 60*time.Second, 10, defaultTimeProvider, cfg).execute()
+	elapsed := time.Since(start)
+	assertNilE(t, res)
+	assertTrueF(t, errors.Is(err, context.DeadlineExceeded), fmt.Sprintf("expected deadline exceeded, got: %v", err))
+	assertTrueE(t, elapsed < deadline+200*time.Millisecond, fmt.Sprintf("retry should stop at the deadline, took %v", elapsed))
+}
+
+func TestRetryAfter(t *testing.T) {
+	header := func(value string) http.Header {
+		return http.Header{"Retry-After": []string{value}}
+	}
+	wait, ok := retryAfter(&http.Response{StatusCode: http.StatusTooManyRequests, Header: header("3")})
+	assertTrueE(t, ok)
+	assertEqualE(t, wait, 3*time.Second)
+
+	wait, ok = retryAfter(&http.Response{StatusCode: http.StatusTooManyRequests, Header: header(time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))})
+	assertTrueE(t, ok)
+	assertEqualE(t, wait, time.Duration(0))
+
+	_, ok = retryAfter(&http.Response{StatusCode: http.StatusServiceUnavailable, Header: header("3")})
+	assertFalseE(t, ok)
+	_, ok = retryAfter(&http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}})
+	assertFalseE(t, ok)
+}
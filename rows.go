@@ -13,6 +13,11 @@ const (
 	headerSseCAlgorithm = "x-amz-server-side-encryption-customer-algorithm"
 	headerSseCKey       = "x-amz-server-side-encryption-customer-key"
 	headerSseCAes       = "AES256"
+
+	headerAcceptEncoding = "Accept-Encoding"
+	// chunkAcceptEncoding lists the compressions of result chunks the driver decodes,
+	// a server or storage that does not support zstd keeps serving gzip.
+	chunkAcceptEncoding = "gzip, zstd"
 )
 
 var (
@@ -32,6 +37,14 @@ type SnowflakeRows interface {
 	GetQueryID() string
 	GetStatus() queryStatus
 	GetArrowBatches() ([]*ArrowBatch, error)
+	GetArrowRecords() (*ArrowRecordIterator, error)
+	GetStatementResults() ([]StatementResult, error)
+	TotalRows() int64
+	Truncated() bool
+	ChunkProgress() (downloaded int, total int)
+	GetValue(name string) (driver.Value, error)
+	ResultFormat() string
+	GetFileTransferResults() []FileTransferResult
 }
 
 type snowflakeRows struct {
@@ -44,12 +57,16 @@ type snowflakeRows struct {
 	errChannel          chan error
 	location            *time.Location
 	ctx                 context.Context
-	format              resultFormat
+	statementResults    []StatementResult
+	returnedRows        int64
+	truncated           bool
+	currentRow          []driver.Value // values of the row last read by Next
+	fileTransferResults []FileTransferResult
 }
 
 func (rows *snowflakeRows) getLocation() *time.Location {
 	if rows.location == nil && rows.sc != nil && rows.sc.cfg != nil {
-		rows.location = getCurrentLocation(rows.sc.cfg.Params)
+		rows.location = rows.sc.getTimestampLTZLocation()
 	}
 	return rows.location
 }
@@ -140,6 +157,9 @@ func (rows *snowflakeRows) Columns() []string {
 	ret := make([]string, len(rows.ChunkDownloader.getRowType()))
 	for i, n := 0, len(rows.ChunkDownloader.getRowType()); i < n; i++ {
 		ret[i] = rows.ChunkDownloader.getRowType()[i].Name
+		if usesLowercaseColumnNames(rows.ctx) {
+			ret[i] = strings.ToLower(ret[i])
+		}
 	}
 	return ret
 }
@@ -148,7 +168,19 @@ func (rows *snowflakeRows) ColumnTypeScanType(index int) reflect.Type {
 	if err := rows.waitForAsyncQueryStatus(); err != nil {
 		return nil
 	}
-	return snowflakeTypeToGo(rows.ctx, getSnowflakeType(rows.ChunkDownloader.getRowType()[index].Type), rows.ChunkDownloader.getRowType()[index].Precision, rows.ChunkDownloader.getRowType()[index].Scale, rows.ChunkDownloader.getRowType()[index].Fields)
+	dbtype := getSnowflakeType(rows.ChunkDownloader.getRowType()[index].Type)
+	if (dbtype == geographyType || dbtype == geometryType) && usesGeospatialValues(rows.ctx) {
+		return reflect.TypeOf(Geospatial{})
+	}
+	if (dbtype == geographyType || dbtype == geometryType) && rows.sc != nil && geoOutputIsBinary(rows.ctx, dbtype, rows.sc.cfg.Params) {
+		return reflect.TypeOf([]byte{})
+	}
+	if rows.sc != nil {
+		if scanType := configuredScanType(rows.ctx, rows.sc.cfg, rows.ChunkDownloader.getRowType()[index]); scanType != nil {
+			return scanType
+		}
+	}
+	return snowflakeTypeToGo(rows.ctx, dbtype, rows.ChunkDownloader.getRowType()[index].Precision, rows.ChunkDownloader.getRowType()[index].Scale, rows.ChunkDownloader.getRowType()[index].Fields)
 }
 
 func (rows *snowflakeRows) GetQueryID() string {
@@ -167,17 +199,45 @@ func (rows *snowflakeRows) GetArrowBatches() ([]*ArrowBatch, error) {
 		return nil, err
 	}
 
-	if rows.format != arrowFormat {
+	// the format of a multi-statement response may differ per statement
+	if rows.ChunkDownloader.getQueryResultFormat() != arrowFormat {
 		return nil, errNonArrowResponseForArrowBatches(rows.queryID).exceptionTelemetry(rows.sc)
 	}
 
 	return rows.ChunkDownloader.getArrowBatches(), nil
 }
 
+// GetArrowRecords returns an iterator over the result in arrow.Record format.
+// The query must be run with a context created by WithArrowBatches.
+func (rows *snowflakeRows) GetArrowRecords() (*ArrowRecordIterator, error) {
+	if !usesArrowBatches(rows.ctx) {
+		return nil, (&SnowflakeError{
+			QueryID: rows.queryID,
+			Number:  ErrArrowBatchesNotEnabled,
+			Message: errMsgArrowBatchesNotEnabled,
+		}).exceptionTelemetry(rows.sc)
+	}
+	batches, err := rows.GetArrowBatches()
+	if err != nil {
+		return nil, err
+	}
+	return newArrowRecordIterator(rows.ctx, batches), nil
+}
+
 func (rows *snowflakeRows) Next(dest []driver.Value) (err error) {
 	if err = rows.waitForAsyncQueryStatus(); err != nil {
 		return err
 	}
+	rows.currentRow = nil
+	if limit := rowLimit(rows.ctx); limit > 0 && rows.returnedRows >= limit {
+		if _, err = rows.ChunkDownloader.next(); err == nil {
+			rows.truncated = true
+		} else if err != io.EOF {
+			return err
+		}
+		rows.ChunkDownloader.reset()
+		return io.EOF
+	}
 	row, err := rows.ChunkDownloader.next()
 	if err != nil {
 		// includes io.EOF
@@ -186,6 +246,7 @@ func (rows *snowflakeRows) Next(dest []driver.Value) (err error) {
 		}
 		return err
 	}
+	rows.returnedRows++
 
 	if rows.ChunkDownloader.getQueryResultFormat() == arrowFormat {
 		for i, n := 0, len(row.ArrowRow); i < n; i++ {
@@ -201,9 +262,141 @@ func (rows *snowflakeRows) Next(dest []driver.Value) (err error) {
 			}
 		}
 	}
+	if rows.sc != nil && (rows.sc.cfg.IntegerScanType != "" || rows.sc.cfg.RealScanType != "") {
+		for i, column := range rows.ChunkDownloader.getRowType() {
+			if i >= len(dest) {
+				break
+			}
+			if dest[i], err = convertToScanType(rows.ctx, rows.sc.cfg, column, dest[i]); err != nil {
+				return err
+			}
+		}
+	}
+	rows.currentRow = dest
 	return err
 }
 
+// GetValue returns the value of the named column in the row last read by Next.
+// An exact match of the name is preferred, otherwise the name is matched case-insensitively,
+// so that both "foo" and "FOO" find the column of the unquoted identifier foo.
+func (rows *snowflakeRows) GetValue(name string) (driver.Value, error) {
+	if rows.currentRow == nil {
+		return nil, &SnowflakeError{
+			QueryID: rows.queryID,
+			Number:  ErrNoCurrentRow,
+			Message: errMsgNoCurrentRow,
+		}
+	}
+	idx, err := rows.columnIndex(name)
+	if err != nil {
+		return nil, err
+	}
+	if idx >= len(rows.currentRow) {
+		return nil, nil
+	}
+	return rows.currentRow[idx], nil
+}
+
+func (rows *snowflakeRows) columnIndex(name string) (int, error) {
+	rowType := rows.ChunkDownloader.getRowType()
+	var matches []string
+	idx := -1
+	for i, column := range rowType {
+		if column.Name == name {
+			return i, nil
+		}
+		if strings.EqualFold(column.Name, name) {
+			matches = append(matches, column.Name)
+			idx = i
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return -1, &SnowflakeError{
+			QueryID:     rows.queryID,
+			Number:      ErrColumnNotFound,
+			Message:     errMsgColumnNotFound,
+			MessageArgs: []any{name},
+		}
+	case 1:
+		return idx, nil
+	default:
+		return -1, &SnowflakeError{
+			QueryID:     rows.queryID,
+			Number:      ErrAmbiguousColumnName,
+			Message:     errMsgAmbiguousColumnName,
+			MessageArgs: []any{name, strings.Join(matches, ", ")},
+		}
+	}
+}
+
+// GetStatementResults returns the query ID and the number of affected rows of every
+// statement of a multi-statement request, in the order of the result sets.
+// For a single statement it returns one entry describing the statement itself.
+func (rows *snowflakeRows) GetStatementResults() ([]StatementResult, error) {
+	if err := rows.waitForAsyncQueryStatus(); err != nil {
+		return nil, err
+	}
+	if rows.statementResults == nil {
+		return []StatementResult{{QueryID: rows.queryID, AffectedRows: -1}}, nil
+	}
+	return rows.statementResults, nil
+}
+
+// TotalRows returns the total number of rows in the current result set as reported
+// by Snowflake in the result metadata. It can be called before Next and returns -1
+// when the total is not known, e.g. for streamed results or failed queries.
+func (rows *snowflakeRows) TotalRows() int64 {
+	if err := rows.waitForAsyncQueryStatus(); err != nil {
+		return -1
+	}
+	if rows.ChunkDownloader == nil {
+		return -1
+	}
+	total := rows.ChunkDownloader.totalRows()
+	if limit := rowLimit(rows.ctx); limit > 0 && total > limit {
+		return limit
+	}
+	return total
+}
+
+// GetFileTransferResults returns the files transferred by a PUT or GET command, in the order of
+// the rows of its result, so that they don't have to be scanned by column name. It returns nil
+// for other statements.
+func (rows *snowflakeRows) GetFileTransferResults() []FileTransferResult {
+	return rows.fileTransferResults
+}
+
+// ResultFormat returns the format Snowflake returned the current result set in, "arrow" or "json".
+// Each statement of a multi-statement query may have its own format. It returns an empty string
+// when the format is not known, e.g. for failed queries.
+func (rows *snowflakeRows) ResultFormat() string {
+	if err := rows.waitForAsyncQueryStatus(); err != nil {
+		return ""
+	}
+	if rows.ChunkDownloader == nil {
+		return ""
+	}
+	return string(rows.ChunkDownloader.getQueryResultFormat())
+}
+
+// Truncated reports whether rows were dropped from the current result set because of
+// WithRowLimit. It is known once Next reported the end of the rows.
+func (rows *snowflakeRows) Truncated() bool {
+	return rows.truncated
+}
+
+// ChunkProgress returns how many of the result chunks of the current result set have
+// been downloaded so far and how many there are in total. The rows returned inline
+// with the query response are not counted as a chunk. It is safe to call it
+// concurrently with Next, e.g. to report the progress of reading a large result.
+func (rows *snowflakeRows) ChunkProgress() (downloaded int, total int) {
+	if rows.ChunkDownloader == nil {
+		return 0, 0
+	}
+	return rows.ChunkDownloader.chunkProgress()
+}
+
 func (rows *snowflakeRows) HasNextResultSet() bool {
 	if err := rows.waitForAsyncQueryStatus(); err != nil {
 		return false
@@ -215,6 +408,9 @@ func (rows *snowflakeRows) NextResultSet() error {
 	if err := rows.waitForAsyncQueryStatus(); err != nil {
 		return err
 	}
+	rows.returnedRows = 0
+	rows.truncated = false
+	rows.currentRow = nil
 	if len(rows.ChunkDownloader.getChunkMetas()) == 0 {
 		if rows.ChunkDownloader.getNextChunkDownloader() == nil {
 			return io.EOF
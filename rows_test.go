@@ -1,15 +1,26 @@
 package gosnowflake
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
 )
 
 type RowsExtended struct {
@@ -400,6 +411,61 @@ func getChunkTestInvalidResponseBody(_ context.Context, _ *snowflakeConn, _ stri
 	}, nil
 }
 
+func TestRowsChunkProgress(t *testing.T) {
+	numChunks := 3
+	backupMaxChunkDownloadWorkers := MaxChunkDownloadWorkers
+	MaxChunkDownloadWorkers = 1
+	defer func() { MaxChunkDownloadWorkers = backupMaxChunkDownloadWorkers }()
+
+	first := "0"
+	cm := make([]execResponseChunk, numChunks)
+	for i := range cm {
+		cm[i] = execResponseChunk{URL: fmt.Sprintf("dummyURL%v", i+1), RowCount: 2}
+	}
+	// every chunk download waits until the test lets it through
+	release := make(chan struct{}, 1)
+	downloadHelper := func(ctx context.Context, scd *snowflakeChunkDownloader, idx int) error {
+		<-release
+		body := fmt.Sprintf(`["%v"],["%v"]`, idx*2+1, idx*2+2)
+		return decodeChunk(ctx, scd, idx, bufio.NewReader(strings.NewReader(body)))
+	}
+	sc := &snowflakeConn{cfg: &Config{Params: make(map[string]*string)}}
+	rows := &snowflakeRows{sc: sc, ctx: context.Background()}
+	rows.ChunkDownloader = &snowflakeChunkDownloader{
+		sc:                 sc,
+		ctx:                context.Background(),
+		Total:              int64(1 + numChunks*2),
+		ChunkMetas:         cm,
+		TotalRowIndex:      int64(-1),
+		FuncDownload:       downloadChunk,
+		FuncDownloadHelper: downloadHelper,
+		RowSet: rowSetType{
+			RowType: []execResponseRowType{{Name: "c1", Type: "FIXED"}},
+			JSON:    [][]*string{{&first}},
+		},
+		QueryResultFormat: "json",
+	}
+	assertNilF(t, rows.ChunkDownloader.start())
+
+	dest := make([]driver.Value, 1)
+	assertNilF(t, rows.Next(dest))
+	downloaded, total := rows.ChunkProgress()
+	assertEqualE(t, downloaded, 0)
+	assertEqualE(t, total, numChunks)
+
+	for chunk := 1; chunk <= numChunks; chunk++ {
+		release <- struct{}{}
+		for i := 0; i < 2; i++ {
+			assertNilF(t, rows.Next(dest))
+		}
+		downloaded, total = rows.ChunkProgress()
+		assertEqualE(t, downloaded, chunk)
+		assertEqualE(t, total, numChunks)
+	}
+	assertEqualE(t, dest[0], "6")
+	assertErrIsE(t, rows.Next(dest), io.EOF)
+}
+
 func TestDownloadChunkInvalidResponseBody(t *testing.T) {
 	numChunks := 2
 	cm := make([]execResponseChunk, 0)
@@ -539,3 +605,265 @@ func TestLocationChangesAfterAlterSession(t *testing.T) {
 		}
 	})
 }
+
+func TestRowsTotalRowsBeforeNext(t *testing.T) {
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+		_ *url.Values, _ map[string]string, _ []byte, _ time.Duration,
+		_ UUID, _ *Config) (*execResponse, error) {
+		one, two, three := "1", "2", "3"
+		return &execResponse{
+			Data: execResponseData{
+				RowType:           []execResponseRowType{{Name: "C1", Type: "fixed"}},
+				RowSet:            [][]*string{{&one}, {&two}, {&three}},
+				Total:             3,
+				Returned:          3,
+				QueryResultFormat: "json",
+				StatementTypeID:   statementTypeIDSelect,
+			},
+			Code:    "0",
+			Success: true,
+		}, nil
+	}
+	sc := &snowflakeConn{
+		cfg:                 &Config{Params: map[string]*string{}},
+		rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+	}
+
+	rows, err := sc.QueryContext(context.Background(), "SELECT C1 FROM t", nil)
+	assertNilF(t, err)
+	defer rows.Close()
+	assertEqualE(t, rows.(SnowflakeRows).TotalRows(), int64(3))
+
+	streamedRows, err := sc.QueryContext(WithStreamDownloader(context.Background()), "SELECT C1 FROM t", nil)
+	assertNilF(t, err)
+	defer streamedRows.Close()
+	assertEqualE(t, streamedRows.(SnowflakeRows).TotalRows(), int64(-1))
+}
+
+func TestRowsWithRowLimit(t *testing.T) {
+	var rowsPerResultSetParam any
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+		_ *url.Values, _ map[string]string, body []byte, _ time.Duration,
+		_ UUID, _ *Config) (*execResponse, error) {
+		var req execRequest
+		assertNilF(t, json.Unmarshal(body, &req))
+		rowsPerResultSetParam = req.Parameters["ROWS_PER_RESULTSET"]
+		// the mock server applies ROWS_PER_RESULTSET to a table of 3 rows
+		rowSet := [][]*string{}
+		for i := 1; i <= 3; i++ {
+			if limit, ok := rowsPerResultSetParam.(float64); ok && len(rowSet) >= int(limit) {
+				break
+			}
+			v := fmt.Sprint(i)
+			rowSet = append(rowSet, []*string{&v})
+		}
+		return &execResponse{
+			Data: execResponseData{
+				RowType:           []execResponseRowType{{Name: "C1", Type: "fixed"}},
+				RowSet:            rowSet,
+				Total:             int64(len(rowSet)),
+				Returned:          int64(len(rowSet)),
+				QueryResultFormat: "json",
+				StatementTypeID:   statementTypeIDSelect,
+			},
+			Code:    "0",
+			Success: true,
+		}, nil
+	}
+	sc := &snowflakeConn{
+		cfg:                 &Config{Params: map[string]*string{}},
+		rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+	}
+	query := func(limit int64) (values []driver.Value, total int64, truncated bool) {
+		rows, err := sc.QueryContext(WithRowLimit(context.Background(), limit), "SELECT C1 FROM t ORDER BY C1", nil)
+		assertNilF(t, err)
+		defer rows.Close()
+		total = rows.(SnowflakeRows).TotalRows()
+		dest := make([]driver.Value, 1)
+		for rows.Next(dest) == nil {
+			values = append(values, dest[0])
+		}
+		return values, total, rows.(SnowflakeRows).Truncated()
+	}
+
+	values, total, truncated := query(2)
+	assertEqualE(t, rowsPerResultSetParam, float64(3))
+	assertDeepEqualE(t, values, []driver.Value{"1", "2"})
+	assertEqualE(t, total, int64(2))
+	assertTrueE(t, truncated)
+
+	values, total, truncated = query(3)
+	assertDeepEqualE(t, values, []driver.Value{"1", "2", "3"})
+	assertEqualE(t, total, int64(3))
+	assertFalseE(t, truncated)
+
+	values, _, truncated = query(0)
+	assertNilE(t, rowsPerResultSetParam)
+	assertEqualE(t, len(values), 3)
+	assertFalseE(t, truncated)
+}
+
+func TestRowsGetValueByCaseInsensitiveName(t *testing.T) {
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+		_ *url.Values, _ map[string]string, _ []byte, _ time.Duration,
+		_ UUID, _ *Config) (*execResponse, error) {
+		one := "1"
+		return &execResponse{
+			Data: execResponseData{
+				// Snowflake uppercases the unquoted alias of SELECT 1 AS Foo
+				RowType:           []execResponseRowType{{Name: "FOO", Type: "fixed"}},
+				RowSet:            [][]*string{{&one}},
+				Total:             1,
+				Returned:          1,
+				QueryResultFormat: "json",
+				StatementTypeID:   statementTypeIDSelect,
+			},
+			Code:    "0",
+			Success: true,
+		}, nil
+	}
+	sc := &snowflakeConn{
+		cfg:                 &Config{Params: map[string]*string{}},
+		rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+	}
+
+	rows, err := sc.QueryContext(context.Background(), "SELECT 1 AS Foo", nil)
+	assertNilF(t, err)
+	defer rows.Close()
+	assertDeepEqualE(t, rows.Columns(), []string{"FOO"})
+	_, err = rows.(SnowflakeRows).GetValue("foo")
+	assertEqualE(t, err.(*SnowflakeError).Number, ErrNoCurrentRow)
+
+	assertNilF(t, rows.Next(make([]driver.Value, 1)))
+	for _, name := range []string{"foo", "FOO", "Foo"} {
+		value, err := rows.(SnowflakeRows).GetValue(name)
+		assertNilF(t, err)
+		assertEqualE(t, value, "1", name)
+	}
+	_, err = rows.(SnowflakeRows).GetValue("bar")
+	assertEqualE(t, err.(*SnowflakeError).Number, ErrColumnNotFound)
+
+	lowercaseRows, err := sc.QueryContext(WithLowercaseColumnNames(context.Background()), "SELECT 1 AS Foo", nil)
+	assertNilF(t, err)
+	defer lowercaseRows.Close()
+	assertDeepEqualE(t, lowercaseRows.Columns(), []string{"foo"})
+}
+
+func TestColumnNamesKeepServerCasing(t *testing.T) {
+	names := []string{"MixedCase", "lower", "UPPER", "with space"}
+	rowType := make([]execResponseRowType, len(names))
+	for i, name := range names {
+		rowType[i] = execResponseRowType{Name: name, Type: "text", Nullable: true}
+	}
+
+	// the field names of the Arrow stream don't have to match the result metadata
+	fields := make([]arrow.Field, len(names))
+	for i := range names {
+		fields[i] = arrow.Field{Name: fmt.Sprintf("$%v", i+1), Type: arrow.BinaryTypes.String, Nullable: true}
+	}
+	schema := arrow.NewSchema(fields, nil)
+	builder := array.NewRecordBuilder(memory.NewGoAllocator(), schema)
+	defer builder.Release()
+	for i := range names {
+		builder.Field(i).(*array.StringBuilder).Append("v")
+	}
+	record := builder.NewRecord()
+	defer record.Release()
+	var buf bytes.Buffer
+	writer := ipc.NewWriter(&buf, ipc.WithSchema(schema))
+	assertNilF(t, writer.Write(record))
+	assertNilF(t, writer.Close())
+	arrowRowSet := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	for _, format := range []string{"json", "arrow"} {
+		t.Run(format, func(t *testing.T) {
+			postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+				_ *url.Values, _ map[string]string, _ []byte, _ time.Duration,
+				_ UUID, _ *Config) (*execResponse, error) {
+				data := execResponseData{
+					RowType:           rowType,
+					Total:             1,
+					Returned:          1,
+					QueryResultFormat: format,
+					StatementTypeID:   statementTypeIDSelect,
+				}
+				if format == "arrow" {
+					data.RowSetBase64 = arrowRowSet
+				} else {
+					v := "v"
+					data.RowSet = [][]*string{{&v, &v, &v, &v}}
+				}
+				return &execResponse{Data: data, Code: "0", Success: true}, nil
+			}
+			sc := &snowflakeConn{
+				cfg:                 &Config{Params: map[string]*string{}},
+				rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+				queryContextCache:   (&queryContextCache{}).init(),
+				currentTimeProvider: defaultTimeProvider,
+			}
+			rows, err := sc.QueryContext(context.Background(), `SELECT 'v' AS "MixedCase", 'v' AS "lower", 'v' AS upper, 'v' AS "with space"`, nil)
+			assertNilF(t, err)
+			defer rows.Close()
+			assertDeepEqualE(t, rows.Columns(), names)
+			dest := make([]driver.Value, len(names))
+			assertNilF(t, rows.Next(dest))
+			assertDeepEqualE(t, dest, []driver.Value{"v", "v", "v", "v"})
+		})
+	}
+}
+
+func TestResultFormat(t *testing.T) {
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+		_ *url.Values, _ map[string]string, body []byte, _ time.Duration,
+		_ UUID, _ *Config) (*execResponse, error) {
+		var req execRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			return nil, err
+		}
+		// Arrow unless the request asks for another format
+		format := "arrow"
+		if requested, ok := req.Parameters[string(queryResultFormat)].(string); ok {
+			format = strings.ToLower(requested)
+		}
+		return &execResponse{
+			Data: execResponseData{
+				RowType:           []execResponseRowType{{Name: "C1", Type: "text", Nullable: true}},
+				QueryResultFormat: format,
+				StatementTypeID:   statementTypeIDSelect,
+			},
+			Code:    "0",
+			Success: true,
+		}, nil
+	}
+	sc := &snowflakeConn{
+		cfg:                 &Config{Params: map[string]*string{}},
+		rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+	}
+	testcases := []struct {
+		name     string
+		ctx      context.Context
+		expected string
+	}{
+		{"session default", context.Background(), "arrow"},
+		{"JSON requested", WithResultFormat(context.Background(), ResultFormatJSON), "json"},
+		{"Arrow requested", WithResultFormat(context.Background(), ResultFormatArrow), "arrow"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			rows, err := sc.QueryContext(tc.ctx, "SELECT c1 FROM t", nil)
+			assertNilF(t, err)
+			defer rows.Close()
+			assertEqualE(t, rows.(SnowflakeRows).ResultFormat(), tc.expected)
+			assertErrIsE(t, rows.Next(make([]driver.Value, 1)), io.EOF)
+			assertEqualE(t, rows.(SnowflakeRows).ResultFormat(), tc.expected)
+		})
+	}
+}
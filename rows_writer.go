@@ -0,0 +1,208 @@
+package gosnowflake
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// RowsFormat is the format WriteRows writes the rows in.
+type RowsFormat int
+
+const (
+	// RowsFormatCSV writes a header record with the column names followed by one CSV record per row.
+	// NULL values are written as empty fields.
+	RowsFormatCSV RowsFormat = iota
+	// RowsFormatNDJSON writes one JSON object per line, keyed by the column names in column order.
+	// NULL values are written as JSON null.
+	RowsFormatNDJSON
+)
+
+// WriteRows writes the remaining rows to w in the given format without the caller scanning them.
+// The rows are not closed. Values are written the same way regardless of the result format:
+//   - BINARY values as uppercase hex, like the default BINARY_OUTPUT_FORMAT,
+//   - DATE values as 2006-01-02, TIME values as 15:04:05.999999999, TIMESTAMP_NTZ values
+//     without a time zone and other timestamps in RFC 3339 with their offset,
+//   - NUMBER values as JSON numbers and VARIANT, OBJECT and ARRAY values as embedded JSON in NDJSON.
+//
+// The Snowflake column types are used when the rows come from this driver. For other drivers
+// the values are written according to their Go type.
+func WriteRows(rows *sql.Rows, w io.Writer, format RowsFormat) error {
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+	names := make([]string, len(columnTypes))
+	types := make([]snowflakeType, len(columnTypes))
+	for i, ct := range columnTypes {
+		names[i] = ct.Name()
+		typ, ok := snowflakeToDriverType[strings.ToUpper(ct.DatabaseTypeName())]
+		if !ok {
+			typ = unSupportedType
+		}
+		types[i] = typ
+	}
+	values := make([]any, len(columnTypes))
+	dest := make([]any, len(columnTypes))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	var writeRow func() error
+	var flush func() error
+	switch format {
+	case RowsFormatCSV:
+		cw := csv.NewWriter(w)
+		if err = cw.Write(names); err != nil {
+			return err
+		}
+		record := make([]string, len(columnTypes))
+		writeRow = func() error {
+			for i, v := range values {
+				record[i] = exportText(types[i], v)
+			}
+			return cw.Write(record)
+		}
+		flush = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+	case RowsFormatNDJSON:
+		keys := make([][]byte, len(columnTypes))
+		for i, name := range names {
+			if keys[i], err = json.Marshal(name); err != nil {
+				return err
+			}
+		}
+		bw := bufio.NewWriter(w)
+		writeRow = func() error {
+			bw.WriteByte('{')
+			for i, v := range values {
+				if i > 0 {
+					bw.WriteByte(',')
+				}
+				bw.Write(keys[i])
+				bw.WriteByte(':')
+				b, err := json.Marshal(exportJSON(types[i], v))
+				if err != nil {
+					return err
+				}
+				bw.Write(b)
+			}
+			_, err := bw.WriteString("}\n")
+			return err
+		}
+		flush = bw.Flush
+	default:
+		return fmt.Errorf("unsupported rows format: %v", format)
+	}
+
+	for rows.Next() {
+		if err = rows.Scan(dest...); err != nil {
+			return err
+		}
+		if err = writeRow(); err != nil {
+			return err
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	return flush()
+}
+
+// exportText returns the text form of a value written by WriteRows. NULL is the empty string.
+func exportText(typ snowflakeType, v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		if typ == booleanType {
+			if b, err := strconv.ParseBool(val); err == nil {
+				return strconv.FormatBool(b)
+			}
+		}
+		return val
+	case []byte:
+		// other drivers may return text as []byte too
+		if typ == binaryType || typ == unSupportedType && !utf8.Valid(val) {
+			return strings.ToUpper(hex.EncodeToString(val))
+		}
+		return string(val)
+	case time.Time:
+		return exportTime(typ, val)
+	case bool:
+		return strconv.FormatBool(val)
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case float64:
+		return strconv.FormatFloat(val, 'g', -1, 64)
+	case *big.Int:
+		return val.String()
+	case *big.Float:
+		return val.Text('g', -1)
+	case fmt.Stringer:
+		return val.String()
+	}
+	if b, err := json.Marshal(v); err == nil {
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
+
+// exportJSON returns the value WriteRows marshals to JSON for a value. NULL is nil.
+func exportJSON(typ snowflakeType, v any) any {
+	switch val := v.(type) {
+	case nil, bool, int64:
+		return val
+	case float64:
+		if math.IsNaN(val) || math.IsInf(val, 0) {
+			return strconv.FormatFloat(val, 'g', -1, 64)
+		}
+		return val
+	case string:
+		switch typ {
+		case fixedType, realType:
+			// NaN and Inf are not JSON numbers
+			if _, err := strconv.ParseFloat(val, 64); err == nil && json.Valid([]byte(val)) {
+				return json.Number(val)
+			}
+		case booleanType:
+			if b, err := strconv.ParseBool(val); err == nil {
+				return b
+			}
+		case variantType, objectType, arrayType, mapType:
+			if json.Valid([]byte(val)) {
+				return json.RawMessage(val)
+			}
+		}
+		return val
+	case *big.Int, *big.Float:
+		return json.Number(exportText(typ, val))
+	case []byte, time.Time:
+		return exportText(typ, val)
+	}
+	return v
+}
+
+func exportTime(typ snowflakeType, t time.Time) string {
+	switch typ {
+	case dateType:
+		return t.Format(time.DateOnly)
+	case timeType:
+		return t.Format("15:04:05.999999999")
+	case timestampNtzType:
+		return t.Format("2006-01-02T15:04:05.999999999")
+	}
+	return t.Format(time.RFC3339Nano)
+}
@@ -0,0 +1,92 @@
+package gosnowflake
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"net/url"
+	"testing"
+	"time"
+)
+
+type mockSnowflakeConnector struct {
+	sc *snowflakeConn
+}
+
+func (c mockSnowflakeConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.sc, nil
+}
+
+func (c mockSnowflakeConnector) Driver() driver.Driver {
+	return SnowflakeDriver{}
+}
+
+func TestWriteRows(t *testing.T) {
+	str := func(s string) *string { return &s }
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+		_ *url.Values, _ map[string]string, _ []byte, _ time.Duration,
+		_ UUID, _ *Config) (*execResponse, error) {
+		return &execResponse{
+			Data: execResponseData{
+				RowType: []execResponseRowType{
+					{Name: "ID", Type: "fixed"},
+					{Name: "RATIO", Type: "real"},
+					{Name: "NAME", Type: "text"},
+					{Name: "DATA", Type: "binary"},
+					{Name: "DAY", Type: "date"},
+					{Name: "CREATED", Type: "timestamp_ntz", Scale: 9},
+					{Name: "ACTIVE", Type: "boolean"},
+					{Name: "ATTRS", Type: "variant"},
+				},
+				RowSet: [][]*string{
+					{str("1"), str("1.5"), str(`a, "b"`), str("CAFE"), str("19723"), str("1704067200.123000000"), str("1"), str(`{"k":[1,2]}`)},
+					{str("2"), nil, nil, nil, nil, nil, nil, nil},
+				},
+				Total:             2,
+				Returned:          2,
+				QueryResultFormat: "json",
+				StatementTypeID:   statementTypeIDSelect,
+			},
+			Code:    "0",
+			Success: true,
+		}, nil
+	}
+	sc := &snowflakeConn{
+		cfg:                 &Config{Params: map[string]*string{}, KeepSessionAlive: true},
+		rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+		telemetry:           testTelemetry,
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+	}
+	db := sql.OpenDB(mockSnowflakeConnector{sc: sc})
+	defer db.Close()
+
+	write := func(format RowsFormat) string {
+		rows, err := db.Query("SELECT * FROM t")
+		assertNilF(t, err)
+		defer rows.Close()
+		var buf bytes.Buffer
+		assertNilF(t, WriteRows(rows, &buf, format))
+		return buf.String()
+	}
+
+	t.Run("CSV", func(t *testing.T) {
+		assertEqualE(t, write(RowsFormatCSV), "ID,RATIO,NAME,DATA,DAY,CREATED,ACTIVE,ATTRS\n"+
+			`1,1.5,"a, ""b""",CAFE,2024-01-01,2024-01-01T00:00:00.123,true,"{""k"":[1,2]}"`+"\n"+
+			"2,,,,,,,\n")
+	})
+
+	t.Run("NDJSON", func(t *testing.T) {
+		assertEqualE(t, write(RowsFormatNDJSON),
+			`{"ID":1,"RATIO":1.5,"NAME":"a, \"b\"","DATA":"CAFE","DAY":"2024-01-01","CREATED":"2024-01-01T00:00:00.123","ACTIVE":true,"ATTRS":{"k":[1,2]}}`+"\n"+
+				`{"ID":2,"RATIO":null,"NAME":null,"DATA":null,"DAY":null,"CREATED":null,"ACTIVE":null,"ATTRS":null}`+"\n")
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		rows, err := db.Query("SELECT * FROM t")
+		assertNilF(t, err)
+		defer rows.Close()
+		assertNotNilE(t, WriteRows(rows, &bytes.Buffer{}, RowsFormat(-1)))
+	})
+}
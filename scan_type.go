@@ -0,0 +1,82 @@
+package gosnowflake
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"strconv"
+)
+
+const (
+	scanTypeInt64   = "int64"
+	scanTypeFloat64 = "float64"
+	scanTypeString  = "string"
+)
+
+func validateScanTypes(cfg *Config) error {
+	if cfg.IntegerScanType != "" && cfg.IntegerScanType != scanTypeInt64 && cfg.IntegerScanType != scanTypeString {
+		return errInvalidScanType("integerScanType", cfg.IntegerScanType, scanTypeInt64)
+	}
+	if cfg.RealScanType != "" && cfg.RealScanType != scanTypeFloat64 && cfg.RealScanType != scanTypeString {
+		return errInvalidScanType("realScanType", cfg.RealScanType, scanTypeFloat64)
+	}
+	return nil
+}
+
+func errInvalidScanType(name, value, nativeType string) *SnowflakeError {
+	return &SnowflakeError{
+		Number:      ErrCodeInvalidScanType,
+		Message:     errMsgInvalidScanType,
+		MessageArgs: []interface{}{name, value, nativeType, scanTypeString},
+	}
+}
+
+// isScannedAsInteger tells whether the column values fit into int64 and are
+// subject to Config.IntegerScanType.
+func isScannedAsInteger(ctx context.Context, column execResponseRowType) bool {
+	return getSnowflakeType(column.Type) == fixedType && column.Scale == 0 && column.Precision < 19 && !higherPrecisionEnabled(ctx)
+}
+
+// convertToScanType converts a decoded numeric value to the Go type configured
+// with Config.IntegerScanType and Config.RealScanType, regardless of the result format.
+func convertToScanType(ctx context.Context, cfg *Config, column execResponseRowType, value driver.Value) (driver.Value, error) {
+	switch {
+	case value == nil:
+		return nil, nil
+	case isScannedAsInteger(ctx, column):
+		switch v := value.(type) {
+		case string:
+			if cfg.IntegerScanType == scanTypeInt64 {
+				return strconv.ParseInt(v, 10, 64)
+			}
+		case int64:
+			if cfg.IntegerScanType == scanTypeString {
+				return strconv.FormatInt(v, 10), nil
+			}
+		}
+	case getSnowflakeType(column.Type) == realType:
+		switch v := value.(type) {
+		case string:
+			if cfg.RealScanType == scanTypeFloat64 {
+				return strconv.ParseFloat(v, 64)
+			}
+		case float64:
+			if cfg.RealScanType == scanTypeString {
+				return strconv.FormatFloat(v, 'g', -1, 64), nil
+			}
+		}
+	}
+	return value, nil
+}
+
+// configuredScanType returns the scan type of the column set with Config.IntegerScanType
+// or Config.RealScanType, or nil if the column type is not affected by the config.
+func configuredScanType(ctx context.Context, cfg *Config, column execResponseRowType) reflect.Type {
+	switch {
+	case isScannedAsInteger(ctx, column) && cfg.IntegerScanType == scanTypeString:
+		return reflect.TypeOf("")
+	case getSnowflakeType(column.Type) == realType && cfg.RealScanType == scanTypeString:
+		return reflect.TypeOf("")
+	}
+	return nil
+}
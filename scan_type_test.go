@@ -0,0 +1,86 @@
+package gosnowflake
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+func TestNumericScanTypes(t *testing.T) {
+	integer := "42"
+	real := "1.5"
+	rowType := []execResponseRowType{
+		{Name: "c1", Type: "fixed", Precision: 10, Scale: 0, Nullable: true},
+		{Name: "c2", Type: "real", Nullable: true},
+	}
+	for _, tc := range []struct {
+		integerScanType string
+		realScanType    string
+		expected        []driver.Value
+	}{
+		{integerScanType: "", realScanType: "", expected: []driver.Value{"42", "1.5"}},
+		{integerScanType: "int64", realScanType: "float64", expected: []driver.Value{int64(42), 1.5}},
+		{integerScanType: "string", realScanType: "string", expected: []driver.Value{"42", "1.5"}},
+	} {
+		t.Run(tc.integerScanType+"/"+tc.realScanType, func(t *testing.T) {
+			sc := &snowflakeConn{
+				cfg: &Config{
+					Params:          map[string]*string{},
+					IntegerScanType: tc.integerScanType,
+					RealScanType:    tc.realScanType,
+				},
+			}
+			rows := &snowflakeRows{sc: sc, ctx: context.Background()}
+			rows.ChunkDownloader = &snowflakeChunkDownloader{
+				sc:                sc,
+				ctx:               context.Background(),
+				Total:             1,
+				ChunkMetas:        []execResponseChunk{},
+				TotalRowIndex:     int64(-1),
+				RowSet:            rowSetType{RowType: rowType, JSON: [][]*string{{&integer, &real}}},
+				QueryResultFormat: "json",
+			}
+			assertNilF(t, rows.ChunkDownloader.start())
+
+			dest := make([]driver.Value, 2)
+			assertNilF(t, rows.Next(dest))
+			assertDeepEqualE(t, dest, tc.expected)
+			if tc.integerScanType != "" {
+				for i, value := range dest {
+					assertEqualE(t, rows.ColumnTypeScanType(i), reflect.TypeOf(value))
+				}
+			}
+		})
+	}
+}
+
+func TestNumericScanTypesOfArrowValues(t *testing.T) {
+	integerColumn := execResponseRowType{Type: "fixed", Precision: 10, Scale: 0}
+	realColumn := execResponseRowType{Type: "real"}
+	cfg := &Config{IntegerScanType: "int64", RealScanType: "string"}
+
+	value, err := convertToScanType(context.Background(), cfg, integerColumn, "42")
+	assertNilF(t, err)
+	assertEqualE(t, value, int64(42))
+	value, err = convertToScanType(context.Background(), cfg, realColumn, 1.5)
+	assertNilF(t, err)
+	assertEqualE(t, value, "1.5")
+
+	value, err = convertToScanType(WithHigherPrecision(context.Background()), &Config{IntegerScanType: "string"}, integerColumn, int64(42))
+	assertNilF(t, err)
+	assertEqualE(t, value, int64(42), "higher precision should take precedence")
+}
+
+func TestInvalidNumericScanType(t *testing.T) {
+	for _, cfg := range []*Config{
+		{IntegerScanType: "int32"},
+		{RealScanType: "float32"},
+	} {
+		err := validateScanTypes(cfg)
+		assertNotNilF(t, err)
+		driverErr, ok := err.(*SnowflakeError)
+		assertTrueF(t, ok)
+		assertEqualE(t, driverErr.Number, ErrCodeInvalidScanType)
+	}
+}
@@ -1,6 +1,12 @@
 package gosnowflake
 
-import "regexp"
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
 
 const (
 	awsKeyPattern          = `(?i)(aws_key_id|aws_secret_key|access_key_id|secret_access_key)\s*=\s*'([^']+)'`
@@ -67,3 +73,197 @@ func maskSecrets(text string) string {
 								maskClientSecret(
 									text))))))))
 }
+
+const (
+	redactedSecret = "****"
+	// secrets shorter than this can't be told apart from the rest of a message
+	minRedactedSecretLength = 4
+)
+
+// secretParameters are the DSN and URL query parameters whose values are redacted.
+// Names are lowercase. It covers the credentials of the DSN and the signatures of
+// presigned (SAS) storage URLs.
+var secretParameters = map[string]bool{
+	"password":             true,
+	"passcode":             true,
+	"token":                true,
+	"privatekey":           true,
+	"privatekeypwd":        true,
+	"oauthclientsecret":    true,
+	"sig":                  true,
+	"signature":            true,
+	"awsaccesskeyid":       true,
+	"x-amz-signature":      true,
+	"x-amz-credential":     true,
+	"x-amz-security-token": true,
+	"x-goog-signature":     true,
+	"x-goog-credential":    true,
+}
+
+// urlParameters are the DSN parameters whose values are URLs, whose user info may include a password. Names are lowercase.
+var urlParameters = map[string]bool{
+	"proxy": true,
+}
+
+// secretHeaders are the HTTP headers whose values are redacted, in canonical form.
+var secretHeaders = map[string]bool{
+	headerAuthorizationKey:                          true,
+	http.CanonicalHeaderKey(headerSseCKey):          true,
+	http.CanonicalHeaderKey("x-amz-security-token"): true,
+	"Set-Cookie": true,
+	"Cookie":     true,
+}
+
+// redactQueryParams replaces the values of secret parameters and the passwords
+// of URL parameters in a raw query string (name1=value1&name2=value2) with ****.
+func redactQueryParams(query string) string {
+	params := strings.Split(query, "&")
+	for i, param := range params {
+		name, value, found := strings.Cut(param, "=")
+		if !found {
+			continue
+		}
+		if secretParameters[strings.ToLower(name)] {
+			params[i] = name + "=" + redactedSecret
+		} else if urlParameters[strings.ToLower(name)] {
+			if _, ok := urlPassword(value); ok {
+				params[i] = name + "=" + redactURL(unescapedURL(value))
+			}
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// urlPassword returns the password of the user info of a URL query parameter value.
+func urlPassword(value string) (string, bool) {
+	u, err := url.Parse(unescapedURL(value))
+	if err != nil || u.User == nil {
+		return "", false
+	}
+	return u.User.Password()
+}
+
+func unescapedURL(value string) string {
+	if unescaped, err := url.QueryUnescape(value); err == nil {
+		return unescaped
+	}
+	return value
+}
+
+// redactURL returns the URL with the password and the values of secret query parameters replaced with ****.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return maskSecrets(rawURL)
+	}
+	u.RawQuery = redactQueryParams(u.RawQuery)
+	if _, ok := u.User.Password(); ok {
+		// the user info would escape the asterisks of a redacted password
+		u.User = url.User(u.User.Username())
+		return strings.Replace(u.String(), u.User.String()+"@", u.User.String()+":"+redactedSecret+"@", 1)
+	}
+	return u.String()
+}
+
+// redactHeaders returns a copy of the headers with the values of secret headers replaced with ****.
+func redactHeaders(headers http.Header) http.Header {
+	redacted := make(http.Header, len(headers))
+	for name, values := range headers {
+		if secretHeaders[http.CanonicalHeaderKey(name)] {
+			redacted[name] = []string{redactedSecret}
+		} else {
+			redacted[name] = values
+		}
+	}
+	return redacted
+}
+
+// redactHeaderValue returns **** for a secret header and the value otherwise.
+func redactHeaderValue(name, value string) string {
+	if secretHeaders[http.CanonicalHeaderKey(name)] {
+		return redactedSecret
+	}
+	return value
+}
+
+// dsnSecrets returns the secret values of the DSN, both as written and unescaped.
+func dsnSecrets(dsn string) []string {
+	var secrets []string
+	base, query, _ := strings.Cut(dsn, "?")
+	if posAt := strings.LastIndex(base, "@"); posAt >= 0 {
+		if _, password, found := strings.Cut(base[:posAt], ":"); found {
+			secrets = append(secrets, password)
+		}
+	}
+	for _, param := range strings.Split(query, "&") {
+		name, value, found := strings.Cut(param, "=")
+		if !found {
+			continue
+		}
+		if urlParameters[strings.ToLower(name)] {
+			if password, ok := urlPassword(value); ok {
+				secrets = append(secrets, password, url.QueryEscape(password))
+			}
+			continue
+		}
+		if !secretParameters[strings.ToLower(name)] {
+			continue
+		}
+		secrets = append(secrets, value)
+		if unescaped, err := url.QueryUnescape(value); err == nil && unescaped != value {
+			secrets = append(secrets, unescaped)
+		}
+	}
+	return secrets
+}
+
+func redactSecretValues(text string, secrets []string) string {
+	for _, secret := range secrets {
+		if len(secret) >= minRedactedSecretLength {
+			text = strings.ReplaceAll(text, secret, redactedSecret)
+		}
+	}
+	return text
+}
+
+// redactErrorSecrets replaces the secrets in the message and the message arguments of the error
+// with ****. The error is returned as is if its message doesn't reveal any of them.
+func redactErrorSecrets(err error, secrets []string) error {
+	if se, ok := err.(*SnowflakeError); ok {
+		redacted := *se
+		redacted.Message = redactSecretValues(se.Message, secrets)
+		changed := redacted.Message != se.Message
+		redacted.MessageArgs = make([]interface{}, len(se.MessageArgs))
+		for i, arg := range se.MessageArgs {
+			redacted.MessageArgs[i] = arg
+			if value := fmt.Sprint(arg); redactSecretValues(value, secrets) != value {
+				redacted.MessageArgs[i] = redactSecretValues(value, secrets)
+				changed = true
+			}
+		}
+		if !changed {
+			return err
+		}
+		return &redacted
+	}
+	message := err.Error()
+	if redactedMessage := redactSecretValues(message, secrets); redactedMessage != message {
+		return &redactedError{message: redactedMessage, err: err}
+	}
+	return err
+}
+
+// redactedError is an error whose message has its secrets replaced with ****. It wraps
+// the original error, so that errors.Is and errors.As keep working for the callers.
+type redactedError struct {
+	message string
+	err     error
+}
+
+func (e *redactedError) Error() string {
+	return e.message
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
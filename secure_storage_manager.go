@@ -85,6 +85,48 @@ func newOAuthRefreshTokenSpec(host, user string) *secureTokenSpec {
 	}
 }
 
+func (t *secureTokenSpec) identifier() CredentialIdentifier {
+	return CredentialIdentifier{
+		Host:      t.host,
+		User:      t.user,
+		TokenType: string(t.tokenType),
+	}
+}
+
+// CredentialIdentifier identifies a temporary credential kept in a CredentialStore.
+type CredentialIdentifier struct {
+	Host      string // host the credential was issued for (or the OAuth token request URL)
+	User      string // user the credential was issued for
+	TokenType string // one of ID_TOKEN, MFA_TOKEN, OAUTH_ACCESS_TOKEN, OAUTH_REFRESH_TOKEN
+}
+
+// Key returns a hashed key uniquely identifying the credential.
+// It fails if either host or user is empty.
+func (id CredentialIdentifier) Key() (string, error) {
+	return buildCredentialsKey(id.Host, id.User, tokenType(id.TokenType))
+}
+
+func (id CredentialIdentifier) tokenSpec() *secureTokenSpec {
+	return &secureTokenSpec{
+		host:      id.Host,
+		user:      id.User,
+		tokenType: tokenType(id.TokenType),
+	}
+}
+
+// CredentialStore is a storage for temporary credentials, like ID tokens, MFA tokens and OAuth tokens,
+// cached when ClientStoreTemporaryCredential or ClientRequestMfaToken is enabled.
+// By default, the driver uses a file on Linux and the system keyring on macOS and Windows.
+// A custom implementation can be injected with Config.CredentialStore. It must be safe for concurrent use.
+type CredentialStore interface {
+	// Get returns the credential or an empty string if it does not exist.
+	Get(id CredentialIdentifier) (string, error)
+	// Set stores the credential, replacing any existing value.
+	Set(id CredentialIdentifier, value string) error
+	// Delete removes the credential. Deleting a missing credential is not an error.
+	Delete(id CredentialIdentifier) error
+}
+
 type secureStorageManager interface {
 	setCredential(tokenSpec *secureTokenSpec, value string)
 	getCredential(tokenSpec *secureTokenSpec) string
@@ -93,6 +135,97 @@ type secureStorageManager interface {
 
 var credentialsStorage = newSecureStorageManager()
 
+// credentialCacheDirStorages caches the file based storages per credential cache directory.
+var credentialCacheDirStorages sync.Map
+
+// getCredentialsStorage returns the credential store configured in cfg or the default one.
+func getCredentialsStorage(cfg *Config) secureStorageManager {
+	if cfg != nil && cfg.CredentialStore != nil {
+		return &credentialStoreAdapter{cfg.CredentialStore}
+	}
+	if cfg != nil && cfg.CredentialCacheDir != "" && runtime.GOOS != "windows" {
+		return getCredentialCacheDirStorage(cfg.CredentialCacheDir)
+	}
+	return credentialsStorage
+}
+
+// getCredentialCacheDirStorage returns the file based storage of the credential cache directory,
+// creating the directory if it doesn't exist. Nothing is cached if it can't be created.
+func getCredentialCacheDirStorage(credDirPath string) secureStorageManager {
+	if cached, ok := credentialCacheDirStorages.Load(credDirPath); ok {
+		return cached.(secureStorageManager)
+	}
+	// We don't check if permissions are incorrect here if a directory exists, because we check it later.
+	if err := os.MkdirAll(credDirPath, os.FileMode(0700)); err != nil {
+		logger.Warnf("failed to create credentials cache dir %v. %v", credDirPath, err)
+		return newNoopSecureStorageManager()
+	}
+	ssm := &threadSafeSecureStorageManager{&sync.Mutex{}, &fileBasedSecureStorageManager{credDirPath: credDirPath}}
+	cached, _ := credentialCacheDirStorages.LoadOrStore(credDirPath, ssm)
+	return cached.(secureStorageManager)
+}
+
+// credentialStoreAdapter exposes a CredentialStore as a secureStorageManager.
+type credentialStoreAdapter struct {
+	store CredentialStore
+}
+
+func (csa *credentialStoreAdapter) setCredential(tokenSpec *secureTokenSpec, value string) {
+	if err := csa.store.Set(tokenSpec.identifier(), value); err != nil {
+		logger.Warnf("Failed to set credential in custom credential store. %v", err)
+	}
+}
+
+func (csa *credentialStoreAdapter) getCredential(tokenSpec *secureTokenSpec) string {
+	value, err := csa.store.Get(tokenSpec.identifier())
+	if err != nil {
+		logger.Warnf("Failed to get credential from custom credential store. %v", err)
+		return ""
+	}
+	return value
+}
+
+func (csa *credentialStoreAdapter) deleteCredential(tokenSpec *secureTokenSpec) {
+	if err := csa.store.Delete(tokenSpec.identifier()); err != nil {
+		logger.Warnf("Failed to delete credential from custom credential store. %v", err)
+	}
+}
+
+// managerCredentialStore exposes a secureStorageManager as a CredentialStore.
+// Errors are logged by the underlying manager, so only identifier validation errors are returned.
+type managerCredentialStore struct {
+	ssm secureStorageManager
+}
+
+func (mcs *managerCredentialStore) Get(id CredentialIdentifier) (string, error) {
+	if _, err := id.Key(); err != nil {
+		return "", err
+	}
+	return mcs.ssm.getCredential(id.tokenSpec()), nil
+}
+
+func (mcs *managerCredentialStore) Set(id CredentialIdentifier, value string) error {
+	if _, err := id.Key(); err != nil {
+		return err
+	}
+	mcs.ssm.setCredential(id.tokenSpec(), value)
+	return nil
+}
+
+func (mcs *managerCredentialStore) Delete(id CredentialIdentifier) error {
+	if _, err := id.Key(); err != nil {
+		return err
+	}
+	mcs.ssm.deleteCredential(id.tokenSpec())
+	return nil
+}
+
+// DefaultCredentialStore returns the credential store used when Config.CredentialStore is not set.
+// It may be used to wrap or decorate the default behaviour in a custom CredentialStore.
+func DefaultCredentialStore() CredentialStore {
+	return &managerCredentialStore{credentialsStorage}
+}
+
 func newSecureStorageManager() secureStorageManager {
 	switch runtime.GOOS {
 	case "linux":
@@ -1,9 +1,11 @@
 package gosnowflake
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 )
@@ -288,3 +290,105 @@ func TestBuildCredentialsKey(t *testing.T) {
 		}
 	}
 }
+
+type inMemoryCredentialStore struct {
+	mu     sync.Mutex
+	tokens map[CredentialIdentifier]string
+	gets   int
+	sets   int
+}
+
+func newInMemoryCredentialStore() *inMemoryCredentialStore {
+	return &inMemoryCredentialStore{tokens: make(map[CredentialIdentifier]string)}
+}
+
+func (s *inMemoryCredentialStore) Get(id CredentialIdentifier) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gets++
+	return s.tokens[id], nil
+}
+
+func (s *inMemoryCredentialStore) Set(id CredentialIdentifier, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sets++
+	s.tokens[id] = value
+	return nil
+}
+
+func (s *inMemoryCredentialStore) Delete(id CredentialIdentifier) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, id)
+	return nil
+}
+
+func TestCustomCredentialStore(t *testing.T) {
+	store := newInMemoryCredentialStore()
+	mfaID := CredentialIdentifier{Host: "testhost", User: "u", TokenType: string(mfaToken)}
+
+	sc := getDefaultSnowflakeConn()
+	sc.cfg.Host = "testhost"
+	sc.cfg.Authenticator = AuthTypeUsernamePasswordMFA
+	sc.cfg.ClientRequestMfaToken = ConfigBoolTrue
+	sc.cfg.CredentialStore = store
+	sc.rest.FuncPostAuth = postAuthCheckUsernamePasswordMfa
+	sc.ctx = context.Background()
+
+	t.Run("token is written to custom store", func(t *testing.T) {
+		_, err := authenticate(context.Background(), sc, []byte{}, []byte{})
+		assertNilF(t, err)
+		assertEqualE(t, store.sets, 1)
+		assertEqualE(t, store.tokens[mfaID], "mockedMfaToken")
+		assertEqualE(t, credentialsStorage.getCredential(mfaID.tokenSpec()), "")
+	})
+
+	t.Run("token is read from custom store", func(t *testing.T) {
+		sc.cfg.MfaToken = ""
+		sc.rest.FuncPostAuth = postAuthCheckUsernamePasswordMfaToken
+		assertNilF(t, authenticateWithConfig(sc))
+		assertEqualE(t, store.gets, 1)
+		assertEqualE(t, sc.cfg.MfaToken, "mockedMfaToken")
+	})
+
+	t.Run("token is deleted from custom store on failure", func(t *testing.T) {
+		sc.rest.FuncPostAuth = postAuthCheckUsernamePasswordMfaFailed
+		_, err := authenticate(context.Background(), sc, []byte{}, []byte{})
+		assertNotNilF(t, err)
+		_, ok := store.tokens[mfaID]
+		assertFalseE(t, ok)
+	})
+}
+
+func TestCredentialCacheDir(t *testing.T) {
+	skipOnWindows(t, "the credential cache dir is not supported on Windows")
+	credCacheDir := filepath.Join(t.TempDir(), "snowflake")
+	mfaSpec := newMfaTokenSpec("testhost", "u")
+
+	sc := getDefaultSnowflakeConn()
+	sc.cfg.Host = "testhost"
+	sc.cfg.Authenticator = AuthTypeUsernamePasswordMFA
+	sc.cfg.ClientRequestMfaToken = ConfigBoolTrue
+	sc.cfg.CredentialCacheDir = credCacheDir
+	sc.rest.FuncPostAuth = postAuthCheckUsernamePasswordMfa
+	_, err := authenticate(context.Background(), sc, []byte{}, []byte{})
+	assertNilF(t, err)
+
+	dirInfo, err := os.Stat(credCacheDir)
+	assertNilF(t, err)
+	assertEqualE(t, dirInfo.Mode().Perm(), os.FileMode(0700))
+	fileInfo, err := os.Stat(filepath.Join(credCacheDir, credCacheFileName))
+	assertNilF(t, err)
+	assertEqualE(t, fileInfo.Mode().Perm(), os.FileMode(0600))
+	assertEqualE(t, getCredentialsStorage(sc.cfg).getCredential(mfaSpec), "mockedMfaToken")
+	assertEqualE(t, credentialsStorage.getCredential(mfaSpec), "")
+}
+
+func TestDefaultCredentialStore(t *testing.T) {
+	store := DefaultCredentialStore()
+	_, err := store.Get(CredentialIdentifier{Host: "", User: "u", TokenType: string(mfaToken)})
+	assertNotNilE(t, err)
+	err = store.Set(CredentialIdentifier{Host: "h", User: "", TokenType: string(mfaToken)}, "value")
+	assertNotNilE(t, err)
+}
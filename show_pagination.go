@@ -0,0 +1,115 @@
+package gosnowflake
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// defaultShowPageSize is the maximum number of rows SHOW accepts in its LIMIT clause.
+const defaultShowPageSize = 10000
+
+var (
+	// showMultiContainerScope matches the scopes whose rows come from more than one container,
+	// so that their names are not unique.
+	showMultiContainerScope = regexp.MustCompile(`(?i)\bIN\s+(ACCOUNT|DATABASE)\b`)
+	// showSchemas matches SHOW SCHEMAS, whose names are unique within a database.
+	showSchemas = regexp.MustCompile(`(?i)^SHOW\s+(TERSE\s+)?SCHEMAS\b`)
+)
+
+// ShowResult holds all rows of a SHOW command fetched by ShowAll.
+type ShowResult struct {
+	Columns []string
+	Rows    [][]driver.Value
+}
+
+// ShowAll runs a SHOW command, e.g. SHOW TABLES IN SCHEMA my_db.my_schema, in pages of pageSize rows
+// and returns the rows of all pages. Each page after the first continues from the name of the last row
+// of the previous page with LIMIT <pageSize> FROM '<name>', so the command must not have a LIMIT
+// clause itself. A pageSize of 0 or less uses the maximum of 10000 rows.
+//
+// Names are only unique within a single container, so the command must list the objects of one
+// schema, or the schemas of one database. IN ACCOUNT and IN DATABASE scopes are rejected with
+// ErrShowNotPageable, except for SHOW SCHEMAS IN DATABASE.
+func (sc *snowflakeConn) ShowAll(ctx context.Context, command string, pageSize int) (*ShowResult, error) {
+	if pageSize <= 0 {
+		pageSize = defaultShowPageSize
+	}
+	command = strings.TrimRight(strings.TrimSpace(command), ";")
+	if m := showMultiContainerScope.FindStringSubmatch(command); m != nil &&
+		(strings.EqualFold(m[1], "ACCOUNT") || !showSchemas.MatchString(command)) {
+		return nil, (&SnowflakeError{
+			Number:      ErrShowNotPageable,
+			Message:     errMsgShowNotPageable,
+			MessageArgs: []any{command, "names are not unique across the containers of an IN " + strings.ToUpper(m[1]) + " scope"},
+		}).exceptionTelemetry(sc)
+	}
+	result := &ShowResult{Rows: make([][]driver.Value, 0)}
+	nameIdx := -1
+	var last []driver.Value
+	for {
+		query := fmt.Sprintf("%v LIMIT %v", command, pageSize)
+		if last != nil {
+			name, _ := last[nameIdx].(string)
+			query += " FROM " + quoteStringLiteral(name)
+		}
+		page, err := sc.queryShowPage(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		if last == nil {
+			result.Columns = page.Columns
+			for i, column := range page.Columns {
+				if strings.EqualFold(column, "name") {
+					nameIdx = i
+				}
+			}
+			if nameIdx < 0 {
+				return nil, (&SnowflakeError{
+					Number:      ErrShowNotPageable,
+					Message:     errMsgShowNotPageable,
+					MessageArgs: []any{command, "the result has no name column to continue from"},
+				}).exceptionTelemetry(sc)
+			}
+		}
+		rows := page.Rows
+		// the continuation may start with the row it continues from
+		if last != nil && len(rows) > 0 && reflect.DeepEqual(rows[0], last) {
+			rows = rows[1:]
+		}
+		result.Rows = append(result.Rows, rows...)
+		if len(page.Rows) < pageSize {
+			return result, nil
+		}
+		if len(rows) == 0 {
+			return nil, (&SnowflakeError{
+				Number:      ErrShowPaginationNoProgress,
+				Message:     errMsgShowPaginationNoProgress,
+				MessageArgs: []any{last[nameIdx]},
+			}).exceptionTelemetry(sc)
+		}
+		last = rows[len(rows)-1]
+	}
+}
+
+func (sc *snowflakeConn) queryShowPage(ctx context.Context, query string) (*ShowResult, error) {
+	rows, err := sc.QueryContext(ctx, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	page := &ShowResult{Columns: rows.Columns()}
+	for {
+		dest := make([]driver.Value, len(page.Columns))
+		if err = rows.Next(dest); err == io.EOF {
+			return page, nil
+		} else if err != nil {
+			return nil, err
+		}
+		page.Rows = append(page.Rows, dest)
+	}
+}
@@ -0,0 +1,157 @@
+package gosnowflake
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShowAll(t *testing.T) {
+	tables := []string{"A", "B", "C", "D", "E"}
+	// noName makes the mock return a result without a name column
+	var noName bool
+	limitFrom := regexp.MustCompile(`LIMIT (\d+)(?: FROM '(.*)')?$`)
+	var queries []string
+	// inclusive tells whether the mock continues at the row named in FROM or after it
+	var inclusive bool
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+		_ *url.Values, _ map[string]string, body []byte, _ time.Duration,
+		_ UUID, _ *Config) (*execResponse, error) {
+		var req execRequest
+		assertNilF(t, json.Unmarshal(body, &req))
+		queries = append(queries, req.SQLText)
+		m := limitFrom.FindStringSubmatch(req.SQLText)
+		assertNotNilF(t, m)
+		var limit int
+		assertNilF(t, json.Unmarshal([]byte(m[1]), &limit))
+		from := strings.NewReplacer(`\\`, `\`, `\'`, `'`).Replace(m[2])
+		rowSet := [][]*string{}
+		for _, name := range tables {
+			if from != "" && (name < from || name == from && !inclusive) {
+				continue
+			}
+			if len(rowSet) == limit {
+				break
+			}
+			kind := "TABLE"
+			rowSet = append(rowSet, []*string{&name, &kind})
+		}
+		firstColumn := "name"
+		if noName {
+			firstColumn = "key"
+		}
+		return &execResponse{
+			Data: execResponseData{
+				RowType: []execResponseRowType{
+					{Name: firstColumn, Type: "text"},
+					{Name: "kind", Type: "text"},
+				},
+				RowSet:            rowSet,
+				QueryResultFormat: "json",
+				StatementTypeID:   statementTypeIDSelect,
+			},
+			Code:    "0",
+			Success: true,
+		}, nil
+	}
+	sc := &snowflakeConn{
+		cfg:                 &Config{Params: map[string]*string{}},
+		rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+	}
+	names := func(result *ShowResult) []driver.Value {
+		var values []driver.Value
+		for _, row := range result.Rows {
+			values = append(values, row[0])
+		}
+		return values
+	}
+	all := []driver.Value{"A", "B", "C", "D", "E"}
+
+	t.Run("two pages", func(t *testing.T) {
+		queries = nil
+		result, err := sc.ShowAll(context.Background(), "SHOW TABLES IN SCHEMA db.sch;", 3)
+		assertNilF(t, err)
+		assertDeepEqualE(t, queries, []string{
+			"SHOW TABLES IN SCHEMA db.sch LIMIT 3",
+			"SHOW TABLES IN SCHEMA db.sch LIMIT 3 FROM 'C'",
+		})
+		assertDeepEqualE(t, result.Columns, []string{"name", "kind"})
+		assertDeepEqualE(t, names(result), all)
+	})
+
+	t.Run("continuation repeats the last row", func(t *testing.T) {
+		inclusive = true
+		defer func() { inclusive = false }()
+		result, err := sc.ShowAll(context.Background(), "SHOW TABLES", 3)
+		assertNilF(t, err)
+		assertDeepEqualE(t, names(result), all)
+	})
+
+	t.Run("single page", func(t *testing.T) {
+		queries = nil
+		result, err := sc.ShowAll(context.Background(), "SHOW TABLES", 0)
+		assertNilF(t, err)
+		assertDeepEqualE(t, queries, []string{"SHOW TABLES LIMIT 10000"})
+		assertDeepEqualE(t, names(result), all)
+	})
+
+	t.Run("names with quotes and backslashes", func(t *testing.T) {
+		defer func(original []string) { tables = original }(tables)
+		tables = []string{`A\`, `A\'B`, "C"}
+		queries = nil
+		result, err := sc.ShowAll(context.Background(), "SHOW TABLES", 1)
+		assertNilF(t, err)
+		assertDeepEqualE(t, queries, []string{
+			"SHOW TABLES LIMIT 1",
+			`SHOW TABLES LIMIT 1 FROM 'A\\'`,
+			`SHOW TABLES LIMIT 1 FROM 'A\\\'B'`,
+			"SHOW TABLES LIMIT 1 FROM 'C'",
+		})
+		assertDeepEqualE(t, names(result), []driver.Value{`A\`, `A\'B`, "C"})
+	})
+
+	t.Run("no progress", func(t *testing.T) {
+		inclusive = true
+		defer func() { inclusive = false }()
+		_, err := sc.ShowAll(context.Background(), "SHOW TABLES", 1)
+		var se *SnowflakeError
+		assertTrueF(t, errors.As(err, &se), fmt.Sprintf("unexpected error: %v", err))
+		assertEqualE(t, se.Number, ErrShowPaginationNoProgress)
+	})
+
+	t.Run("no name column", func(t *testing.T) {
+		noName = true
+		defer func() { noName = false }()
+		_, err := sc.ShowAll(context.Background(), "SHOW TABLES", 1)
+		var se *SnowflakeError
+		assertTrueF(t, errors.As(err, &se), fmt.Sprintf("unexpected error: %v", err))
+		assertEqualE(t, se.Number, ErrShowNotPageable)
+	})
+
+	t.Run("scopes of more than one container", func(t *testing.T) {
+		for _, command := range []string{
+			"SHOW TABLES IN DATABASE db",
+			"show terse tables in database",
+			"SHOW TABLES IN ACCOUNT",
+			"SHOW SCHEMAS IN ACCOUNT",
+		} {
+			queries = nil
+			_, err := sc.ShowAll(context.Background(), command, 0)
+			var se *SnowflakeError
+			assertTrueF(t, errors.As(err, &se), fmt.Sprintf("%v: unexpected error: %v", command, err))
+			assertEqualE(t, se.Number, ErrShowNotPageable)
+			assertEqualE(t, len(queries), 0)
+		}
+		_, err := sc.ShowAll(context.Background(), "SHOW SCHEMAS IN DATABASE db", 0)
+		assertNilE(t, err, "schemas are unique within a database")
+	})
+}
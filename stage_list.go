@@ -0,0 +1,64 @@
+package gosnowflake
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"strings"
+	"time"
+)
+
+// stagedFileTimeLayout is the format of the last_modified column returned by LIST.
+const stagedFileTimeLayout = "Mon, 2 Jan 2006 15:04:05 MST"
+
+// StagedFile describes a file returned by the LIST command.
+type StagedFile struct {
+	Name         string
+	Size         int64
+	MD5          string
+	LastModified time.Time
+}
+
+// ListStage runs LIST on the stage location, e.g. @my_stage/path/, and returns the staged files
+// without downloading them. It returns an empty slice if there are no files in the location.
+func (sc *snowflakeConn) ListStage(ctx context.Context, location string) ([]StagedFile, error) {
+	rows, err := sc.QueryContext(ctx, "LIST "+location, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return readStagedFiles(rows)
+}
+
+func readStagedFiles(rows driver.Rows) ([]StagedFile, error) {
+	columns := rows.Columns()
+	dest := make([]driver.Value, len(columns))
+	files := make([]StagedFile, 0)
+	for {
+		if err := rows.Next(dest); err == io.EOF {
+			return files, nil
+		} else if err != nil {
+			return nil, err
+		}
+		var file StagedFile
+		for i, column := range columns {
+			switch strings.ToLower(column) {
+			case "name":
+				file.Name, _ = dest[i].(string)
+			case "size":
+				file.Size = copyResultInt(dest[i])
+			case "md5":
+				file.MD5, _ = dest[i].(string)
+			case "last_modified":
+				if lastModified, ok := dest[i].(string); ok {
+					t, err := time.Parse(stagedFileTimeLayout, lastModified)
+					if err != nil {
+						return nil, err
+					}
+					file.LastModified = t
+				}
+			}
+		}
+		files = append(files, file)
+	}
+}
@@ -0,0 +1,68 @@
+package gosnowflake
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestListStage(t *testing.T) {
+	var listed [][]*string
+	var queries []string
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+		_ *url.Values, _ map[string]string, body []byte, _ time.Duration,
+		_ UUID, _ *Config) (*execResponse, error) {
+		var req execRequest
+		assertNilF(t, json.Unmarshal(body, &req))
+		queries = append(queries, req.SQLText)
+		return &execResponse{
+			Data: execResponseData{
+				RowType: []execResponseRowType{
+					{Name: "name", Type: "text"},
+					{Name: "size", Type: "fixed"},
+					{Name: "md5", Type: "text"},
+					{Name: "last_modified", Type: "text"},
+				},
+				RowSet:            listed,
+				QueryResultFormat: "json",
+				StatementTypeID:   statementTypeIDSelect,
+			},
+			Code:    "0",
+			Success: true,
+		}, nil
+	}
+	sc := &snowflakeConn{
+		cfg:                 &Config{Params: map[string]*string{}},
+		rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+	}
+	str := func(s string) *string { return &s }
+
+	t.Run("files", func(t *testing.T) {
+		listed = [][]*string{
+			{str("my_stage/path/data_0.csv.gz"), str("1024"), str("0ab1c2d3e4f5"), str("Tue, 1 Oct 2024 10:00:00 GMT")},
+			{str("my_stage/path/data_1.csv.gz"), str("16"), str("f5e4d3c2b1a0"), str("Wed, 02 Oct 2024 23:59:59 GMT")},
+		}
+		files, err := sc.ListStage(context.Background(), "@my_stage/path/")
+		assertNilF(t, err)
+		assertEqualE(t, queries[len(queries)-1], "LIST @my_stage/path/")
+		assertEqualF(t, len(files), 2)
+		assertEqualE(t, files[0].Name, "my_stage/path/data_0.csv.gz")
+		assertEqualE(t, files[0].Size, int64(1024))
+		assertEqualE(t, files[0].MD5, "0ab1c2d3e4f5")
+		assertTrueE(t, files[0].LastModified.Equal(time.Date(2024, 10, 1, 10, 0, 0, 0, time.UTC)))
+		assertEqualE(t, files[1].Size, int64(16))
+		assertTrueE(t, files[1].LastModified.Equal(time.Date(2024, 10, 2, 23, 59, 59, 0, time.UTC)))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		listed = nil
+		files, err := sc.ListStage(context.Background(), "@my_stage/empty/")
+		assertNilF(t, err)
+		assertNotNilE(t, files)
+		assertEqualE(t, len(files), 0)
+	})
+}
@@ -16,6 +16,7 @@ type snowflakeStmt struct {
 	sc          *snowflakeConn
 	query       string
 	lastQueryID string
+	metadata    *preparedStatementMetadata // describe result, set only when the prepared statement cache is enabled
 }
 
 func (stmt *snowflakeStmt) Close() error {
@@ -26,7 +27,10 @@ func (stmt *snowflakeStmt) Close() error {
 
 func (stmt *snowflakeStmt) NumInput() int {
 	logger.WithContext(stmt.sc.ctx).Infoln("Stmt.NumInput")
-	// Go Snowflake doesn't know the number of binding parameters.
+	if stmt.metadata != nil {
+		return stmt.metadata.numberOfBinds
+	}
+	// Go Snowflake doesn't know the number of binding parameters unless the statement was described.
 	return -1
 }
 
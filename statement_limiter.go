@@ -0,0 +1,54 @@
+package gosnowflake
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// statementLimiter paces the statements of a connection with a token bucket holding
+// a single token, so statements start at least 1/rate seconds apart.
+// A nil limiter is valid and never blocks.
+type statementLimiter struct {
+	mutex    sync.Mutex
+	interval time.Duration
+	next     time.Time // when the next token is available
+}
+
+func newStatementLimiter(perSecond int) *statementLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &statementLimiter{interval: time.Second / time.Duration(perSecond)}
+}
+
+// wait blocks until the next statement may start or the context is done.
+func (sl *statementLimiter) wait(ctx context.Context) error {
+	if sl == nil {
+		return nil
+	}
+	sl.mutex.Lock()
+	now := time.Now()
+	start := sl.next
+	if start.Before(now) {
+		start = now
+	}
+	sl.next = start.Add(sl.interval)
+	sl.mutex.Unlock()
+
+	delay := start.Sub(now)
+	if delay <= 0 {
+		return nil
+	}
+	logger.WithContext(ctx).Debugf("statement rate limit reached, waiting %v", delay)
+	if err := sleepWithContext(ctx, delay); err != nil {
+		sl.mutex.Lock()
+		// give the token back unless a later statement already reserved the next one
+		if sl.next.Equal(start.Add(sl.interval)) {
+			sl.next = start
+		}
+		sl.mutex.Unlock()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package gosnowflake
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestMaxStatementsPerSecond(t *testing.T) {
+	var sentAt []time.Time
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+		_ *url.Values, _ map[string]string, _ []byte, _ time.Duration,
+		_ UUID, _ *Config) (*execResponse, error) {
+		sentAt = append(sentAt, time.Now())
+		return &execResponse{Code: "0", Success: true}, nil
+	}
+	sc := &snowflakeConn{
+		cfg:                 &Config{Params: map[string]*string{}, MaxStatementsPerSecond: 20},
+		rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+		statementLimiter:    newStatementLimiter(20),
+	}
+
+	start := time.Now()
+	for range 11 {
+		_, err := sc.exec(context.Background(), "SELECT 1", false, false, false, nil)
+		assertNilF(t, err)
+	}
+	// the first statement starts immediately, the other 10 are 50ms apart
+	assertTrueE(t, time.Since(start) >= 500*time.Millisecond, "statements were not paced")
+	for i := 1; i < len(sentAt); i++ {
+		assertTrueE(t, sentAt[i].Sub(sentAt[i-1]) >= 45*time.Millisecond, "statements were sent too quickly")
+	}
+
+	t.Run("context done while waiting", func(t *testing.T) {
+		sent := len(sentAt)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := sc.exec(ctx, "SELECT 1", false, false, false, nil)
+		assertTrueE(t, errors.Is(err, context.Canceled))
+		assertEqualE(t, len(sentAt), sent)
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		assertNilE(t, newStatementLimiter(0))
+		assertNilE(t, (*statementLimiter)(nil).wait(context.Background()))
+	})
+}
@@ -0,0 +1,50 @@
+package gosnowflake
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// field names of the records emitted to Config.Logger
+const (
+	slogQueryIDKey   = "query_id"
+	slogRequestIDKey = "request_id"
+	slogSessionIDKey = "session_id"
+	slogAttemptKey   = "attempt"
+	slogDurationKey  = "duration"
+	slogErrorKey     = "error"
+)
+
+// logStructured emits a record to the structured logger of the configuration.
+// It is a no-op when Config.Logger is not set, so the driver behaves as before.
+func logStructured(ctx context.Context, cfg *Config, level slog.Level, msg string, attrs ...slog.Attr) {
+	if cfg == nil || cfg.Logger == nil {
+		return
+	}
+	cfg.Logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+func logQueryStarted(ctx context.Context, cfg *Config, requestID UUID) {
+	logStructured(ctx, cfg, slog.LevelInfo, "query started",
+		slog.String(slogRequestIDKey, requestID.String()))
+}
+
+func logQueryFinished(ctx context.Context, cfg *Config, requestID UUID, start time.Time, data *execResponse, err error) {
+	attrs := []slog.Attr{
+		slog.String(slogRequestIDKey, requestID.String()),
+		slog.Duration(slogDurationKey, time.Since(start)),
+	}
+	if data != nil && data.Data.QueryID != "" {
+		attrs = append(attrs, slog.String(slogQueryIDKey, data.Data.QueryID))
+	}
+	level := slog.LevelInfo
+	if err != nil {
+		level = slog.LevelError
+		attrs = append(attrs, slog.String(slogErrorKey, err.Error()))
+	} else if data != nil && !data.Success {
+		level = slog.LevelError
+		attrs = append(attrs, slog.String("code", data.Code), slog.String(slogErrorKey, data.Message))
+	}
+	logStructured(ctx, cfg, level, "query finished", attrs...)
+}
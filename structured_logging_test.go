@@ -0,0 +1,74 @@
+package gosnowflake
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingSlogHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (h *recordingSlogHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+func (h *recordingSlogHandler) Handle(_ context.Context, record slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, record.Clone())
+	return nil
+}
+
+func (h *recordingSlogHandler) WithAttrs([]slog.Attr) slog.Handler {
+	return h
+}
+
+func (h *recordingSlogHandler) WithGroup(string) slog.Handler {
+	return h
+}
+
+func slogRecordAttrs(record slog.Record) map[string]string {
+	attrs := make(map[string]string)
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs[attr.Key] = attr.Value.String()
+		return true
+	})
+	return attrs
+}
+
+func TestStructuredLoggingOfQuery(t *testing.T) {
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+		_ *url.Values, _ map[string]string, _ []byte, _ time.Duration,
+		_ UUID, _ *Config) (*execResponse, error) {
+		return &execResponse{
+			Data:    execResponseData{QueryID: "01b2c3d4-0000-1111-0000-000000000001"},
+			Code:    "0",
+			Success: true,
+		}, nil
+	}
+	handler := &recordingSlogHandler{}
+	sc := &snowflakeConn{
+		cfg:                 &Config{Params: map[string]*string{}, Logger: slog.New(handler)},
+		rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+	}
+	requestID := NewUUID()
+	_, err := sc.ExecContext(WithRequestID(context.Background(), requestID), "INSERT INTO t VALUES (1)", nil)
+	assertNilF(t, err)
+
+	assertEqualF(t, len(handler.records), 2)
+	start, end := handler.records[0], handler.records[1]
+	assertEqualE(t, start.Message, "query started")
+	assertEqualE(t, slogRecordAttrs(start)[slogRequestIDKey], requestID.String())
+	assertEqualE(t, end.Message, "query finished")
+	assertEqualE(t, end.Level, slog.LevelInfo)
+	assertEqualE(t, slogRecordAttrs(end)[slogQueryIDKey], "01b2c3d4-0000-1111-0000-000000000001")
+	assertEqualE(t, slogRecordAttrs(end)[slogRequestIDKey], requestID.String())
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"math/big"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -636,6 +637,106 @@ func (sowc *structuredObjectWriterContext) WriteAll(sow StructuredObjectWriter)
 	return nil
 }
 
+var anyMapType = reflect.TypeOf(map[string]any{})
+
+// writeMap writes the entries of a map[string]any ordered by key. The type of every field
+// is inferred from its value, nested maps are written as objects and slices as arrays.
+func (sowc *structuredObjectWriterContext) writeMap(m map[string]any) error {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := sowc.writeAny(key, m[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sowc *structuredObjectWriterContext) writeAny(fieldName string, value any) error {
+	switch v := value.(type) {
+	case nil:
+		return sowc.writeString(fieldName, nil)
+	case []byte:
+		return sowc.WriteBytes(fieldName, v)
+	case time.Time:
+		return sowc.WriteTime(fieldName, v, DataTypeTimestampNtz)
+	}
+	val := reflect.ValueOf(value)
+	switch val.Kind() {
+	case reflect.String:
+		return sowc.WriteString(fieldName, val.String())
+	case reflect.Bool:
+		return sowc.WriteBool(fieldName, val.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return sowc.writeFixed(fieldName, val.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return sowc.writeFixed(fieldName, val.Uint())
+	case reflect.Float32, reflect.Float64:
+		return sowc.writeFloat(fieldName, val.Float())
+	case reflect.Map:
+		if !val.Type().ConvertibleTo(anyMapType) {
+			break
+		}
+		childSowc := structuredObjectWriterContext{}
+		childSowc.init(sowc.params)
+		if err := childSowc.writeMap(val.Convert(anyMapType).Interface().(map[string]any)); err != nil {
+			return err
+		}
+		return sowc.write(childSowc.values, structuredObjectWriterEntry{
+			name:     fieldName,
+			typ:      "object",
+			nullable: true,
+			fields:   childSowc.toFields(),
+		})
+	case reflect.Slice, reflect.Array:
+		return sowc.writeAnySlice(fieldName, val)
+	}
+	return fmt.Errorf("unsupported type %T of field %v", value, fieldName)
+}
+
+// writeAnySlice writes a slice as an array whose elements must all be of the same Snowflake type.
+func (sowc *structuredObjectWriterContext) writeAnySlice(fieldName string, val reflect.Value) error {
+	values := make([]any, val.Len())
+	var elementMetadata *fieldMetadata
+	for i := range values {
+		elementSowc := structuredObjectWriterContext{}
+		elementSowc.init(sowc.params)
+		if err := elementSowc.writeAny(fieldName, val.Index(i).Interface()); err != nil {
+			return err
+		}
+		values[i] = elementSowc.values[fieldName]
+		if values[i] == nil {
+			continue
+		}
+		metadata := elementSowc.entries[0].toFieldMetadata()
+		metadata.Name = ""
+		if elementMetadata == nil {
+			elementMetadata = &metadata
+		} else if !strings.EqualFold(elementMetadata.Type, metadata.Type) {
+			return fmt.Errorf("array %v mixes elements of type %v and %v", fieldName, elementMetadata.Type, metadata.Type)
+		}
+	}
+	if elementMetadata == nil {
+		elementMetadata = &fieldMetadata{Type: "text", Nullable: true, Length: 134217728}
+		if elemType := val.Type().Elem(); elemType.Kind() != reflect.Interface {
+			metadata, err := goTypeToFieldMetadata(elemType, textType, sowc.params)
+			if err != nil {
+				return err
+			}
+			elementMetadata = &metadata
+		}
+	}
+	return sowc.write(values, structuredObjectWriterEntry{
+		name:     fieldName,
+		typ:      "array",
+		nullable: true,
+		fields:   []fieldMetadata{*elementMetadata},
+	})
+}
+
 func (sowc *structuredObjectWriterContext) toFields() []fieldMetadata {
 	fieldMetadatas := make([]fieldMetadata, len(sowc.entries))
 	for i, entry := range sowc.entries {
@@ -3,6 +3,7 @@ package gosnowflake
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"testing"
@@ -574,6 +575,33 @@ func TestBindingNullStructuredObjects(t *testing.T) {
 	})
 }
 
+func TestBindingObjectFromMap(t *testing.T) {
+	runDBTest(t, func(dbt *DBTest) {
+		dbt.enableStructuredTypesBinding()
+		dbt.mustExec("CREATE OR REPLACE TABLE test_object_binding (obj OBJECT(name VARCHAR, count DOUBLE, active BOOLEAN, address OBJECT(city VARCHAR, zip VARCHAR), tags ARRAY(VARCHAR)))")
+		defer func() {
+			dbt.mustExec("DROP TABLE IF EXISTS test_object_binding")
+		}()
+		o := map[string]any{
+			"name":    "snowflake",
+			"count":   float64(3),
+			"active":  true,
+			"address": map[string]any{"city": "Warsaw", "zip": nil},
+			"tags":    []any{"a", "b"},
+		}
+		dbt.mustExec("INSERT INTO test_object_binding SELECT (?)", o)
+
+		rows := dbt.mustQuery("SELECT obj::VARIANT FROM test_object_binding")
+		defer rows.Close()
+		assertTrueF(t, rows.Next())
+		var res string
+		assertNilF(t, rows.Scan(&res))
+		var decoded map[string]any
+		assertNilF(t, json.Unmarshal([]byte(res), &decoded))
+		assertDeepEqualE(t, decoded, o)
+	})
+}
+
 func TestBindingArrayWithSchema(t *testing.T) {
 	ctx := WithStructuredTypesEnabled(context.Background())
 	runDBTest(t, func(dbt *DBTest) {
@@ -3,10 +3,14 @@ package gosnowflake
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
+	"unicode"
 )
 
 const (
@@ -26,11 +30,16 @@ const (
 	reasonKey        = "reason"
 	errorNumberKey   = "ErrorNumber"
 	stacktraceKey    = "Stacktrace"
+	queryTagKey      = "QueryTag"
 )
 
+// maxTelemetryQueryTagLength is the maximum number of characters of a query tag sent with a telemetry event.
+const maxTelemetryQueryTagLength = 256
+
 const (
 	telemetrySource      = "golang_driver"
 	sqlException         = "client_sql_exception"
+	clientError          = "client_driver_error"
 	connectionParameters = "client_connection_parameters"
 )
 
@@ -47,6 +56,79 @@ type snowflakeTelemetry struct {
 	enabled   bool
 }
 
+// generateClientErrorData builds a telemetry event for an error raised by the driver itself.
+// Only the error code and the message template are reported, never the formatted
+// message arguments, so that no query text, bind values or URLs leave the client.
+func generateClientErrorData(err error) *telemetryData {
+	data := &telemetryData{
+		Message: map[string]string{
+			typeKey:          clientError,
+			sourceKey:        telemetrySource,
+			driverTypeKey:    "Go",
+			driverVersionKey: SnowflakeGoDriverVersion,
+		},
+		Timestamp: time.Now().UnixNano() / int64(time.Millisecond),
+	}
+	var se *SnowflakeError
+	if errors.As(err, &se) {
+		data.Message[errorNumberKey] = strconv.Itoa(se.Number)
+		data.Message[reasonKey] = maskSecrets(se.Message)
+		if se.SQLState != "" {
+			data.Message[sqlStateKey] = se.SQLState
+		}
+		if se.QueryID != "" {
+			data.Message[queryIDKey] = se.QueryID
+		}
+	} else {
+		data.Message[reasonKey] = fmt.Sprintf("%T", err)
+	}
+	return data
+}
+
+// reportClientError enqueues a client error event of the statement run with the context.
+// The events are sent in batches and the remaining ones are flushed when the connection is closed.
+func (sc *snowflakeConn) reportClientError(ctx context.Context, err error) {
+	if err == nil || sc == nil || sc.telemetry == nil || !sc.telemetry.enabled {
+		return
+	}
+	data := generateClientErrorData(err)
+	sc.addQueryTag(ctx, data)
+	if err := sc.telemetry.addLog(data); err != nil {
+		logger.WithContext(sc.ctx).Debugf("failed to log client error to telemetry: %v", err)
+	}
+}
+
+// addQueryTag adds the query tag a statement runs with to its telemetry event: the tag of
+// the context if it was set with WithQueryTag, otherwise the QUERY_TAG of the session.
+func (sc *snowflakeConn) addQueryTag(ctx context.Context, data *telemetryData) {
+	tag, _ := ctx.Value(queryTag).(string)
+	if tag == "" && sc.cfg != nil {
+		paramsMutex.Lock()
+		if current, ok := sc.cfg.Params[strings.ToLower(string(queryTag))]; ok && current != nil {
+			tag = *current
+		}
+		paramsMutex.Unlock()
+	}
+	if tag = sanitizeQueryTag(tag); tag != "" {
+		data.Message[queryTagKey] = tag
+	}
+}
+
+// sanitizeQueryTag masks secrets in the query tag, replaces its control characters with
+// spaces and truncates it to maxTelemetryQueryTagLength characters.
+func sanitizeQueryTag(tag string) string {
+	tag = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, maskSecrets(tag))
+	if runes := []rune(tag); len(runes) > maxTelemetryQueryTagLength {
+		tag = string(runes[:maxTelemetryQueryTagLength])
+	}
+	return strings.TrimSpace(tag)
+}
+
 func (st *snowflakeTelemetry) addLog(data *telemetryData) error {
 	if !st.enabled {
 		return fmt.Errorf("telemetry disabled; not adding log")
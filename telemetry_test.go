@@ -6,6 +6,8 @@ import (
 	"math/rand"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -269,3 +271,84 @@ func TestAddLogError(t *testing.T) {
 		}
 	})
 }
+
+func TestTelemetryClientErrorOnChunkDownloadFailure(t *testing.T) {
+	sc := &snowflakeConn{
+		cfg:  &Config{Params: map[string]*string{}},
+		rest: &snowflakeRestful{RequestTimeout: time.Second},
+	}
+	sc.telemetry = &snowflakeTelemetry{
+		flushSize: defaultFlushSize,
+		sr:        sc.rest,
+		mutex:     &sync.Mutex{},
+		enabled:   true,
+	}
+	scd := &snowflakeChunkDownloader{
+		sc:                 sc,
+		ctx:                context.Background(),
+		ChunkMetas:         []execResponseChunk{{URL: "https://sfc-stage.s3.amazonaws.com/results/0_0?X-Amz-Signature=secret"}},
+		ChunksError:        make(chan *chunkError, 1),
+		ChunksErrorCounter: maxChunkDownloaderErrorCounter,
+		DoneDownloadCond:   sync.NewCond(&sync.Mutex{}),
+		FuncDownloadHelper: downloadChunkHelper,
+		FuncGet: func(context.Context, *snowflakeConn, string, map[string]string, time.Duration) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: http.NoBody}, nil
+		},
+	}
+
+	downloadChunk(context.Background(), scd, 0)
+	err := scd.checkErrorRetry()
+	assertNotNilF(t, err)
+
+	assertEqualF(t, len(sc.telemetry.logs), 1)
+	message := sc.telemetry.logs[0].Message
+	assertEqualE(t, message[typeKey], clientError)
+	assertEqualE(t, message[errorNumberKey], strconv.Itoa(ErrFailedToGetChunk))
+	assertEqualE(t, message[reasonKey], errMsgFailedToGetChunk)
+	for _, value := range message {
+		assertFalseE(t, strings.Contains(value, "secret"), "telemetry must not contain the chunk URL")
+	}
+}
+
+func TestTelemetryClientErrorDisabled(t *testing.T) {
+	sc := &snowflakeConn{
+		cfg:       &Config{Params: map[string]*string{}, DisableTelemetry: true},
+		telemetry: &snowflakeTelemetry{enabled: false},
+	}
+	sc.reportClientError(context.Background(), errUnknownError())
+	assertEqualE(t, len(sc.telemetry.logs), 0)
+}
+
+func TestTelemetryQueryTag(t *testing.T) {
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful, _ *url.Values, _ map[string]string, _ []byte, _ time.Duration, _ UUID, _ *Config) (*execResponse, error) {
+		return &execResponse{Success: false, Code: "2003", Message: "Object does not exist", Data: execResponseData{QueryID: "01aa3265-0405-ab7c-0000-53b106343aba", SQLState: "02000"}}, nil
+	}
+	sessionTag := "nightly-etl"
+	sc := &snowflakeConn{
+		cfg:                 &Config{Params: map[string]*string{"query_tag": &sessionTag}},
+		rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+	}
+	testcases := []struct {
+		name     string
+		ctx      context.Context
+		expected string
+	}{
+		{"session tag", context.Background(), "nightly-etl"},
+		{"query tag", WithQueryTag(context.Background(), "report\n42"), "report 42"},
+		{"long query tag", WithQueryTag(context.Background(), strings.Repeat("x", 2000)), strings.Repeat("x", maxTelemetryQueryTagLength)},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			sc.telemetry = &snowflakeTelemetry{flushSize: defaultFlushSize, mutex: &sync.Mutex{}, enabled: true}
+			_, err := sc.QueryContext(tc.ctx, "SELECT * FROM missing", nil)
+			assertNotNilF(t, err)
+			assertTrueF(t, len(sc.telemetry.logs) > 0)
+			for _, log := range sc.telemetry.logs {
+				assertEqualE(t, log.Message[typeKey], sqlException)
+				assertEqualE(t, log.Message[queryTagKey], tc.expected)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql/driver"
 	"errors"
+	"strings"
 )
 
 type snowflakeTx struct {
@@ -46,9 +47,45 @@ func (tx *snowflakeTx) execTxCommand(command txCommand) (err error) {
 	}
 	isInternal := isInternal(tx.ctx)
 	_, err = tx.sc.exec(tx.ctx, txStr, false /* noResult */, isInternal, false /* describeOnly */, nil)
+	// the transaction is over for database/sql even if the command failed
+	tx.sc.readOnlyTransaction = false
 	if err != nil {
 		return
 	}
 	tx.sc = nil
 	return
 }
+
+// readOnlyStatements are the statements allowed in a read-only transaction.
+// Snowflake has no read-only transaction mode, so the driver rejects any other
+// statement before it is sent. Only the first keyword is checked, which makes
+// it a best-effort guard rather than an enforcement of the read-only mode.
+var readOnlyStatements = map[string]bool{
+	"SELECT":   true,
+	"WITH":     true,
+	"SHOW":     true,
+	"DESC":     true,
+	"DESCRIBE": true,
+	"EXPLAIN":  true,
+	"LIST":     true,
+	"LS":       true,
+	"COMMIT":   true,
+	"ROLLBACK": true,
+}
+
+func checkReadOnlyStatement(query string) error {
+	fields := strings.Fields(strings.TrimLeft(query, " \t\r\n("))
+	keyword := ""
+	if len(fields) > 0 {
+		keyword = strings.ToUpper(strings.TrimRight(fields[0], ";("))
+	}
+	if readOnlyStatements[keyword] {
+		return nil
+	}
+	return &SnowflakeError{
+		Number:      ErrNoReadOnlyTransaction,
+		SQLState:    SQLStateFeatureNotSupported,
+		Message:     errMsgNoReadOnlyTransaction,
+		MessageArgs: []interface{}{keyword},
+	}
+}
@@ -3,8 +3,11 @@ package gosnowflake
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"testing"
 	"time"
 )
@@ -25,11 +28,12 @@ func TestTransactionOptions(t *testing.T) {
 	if err = tx.Rollback(); err != nil {
 		t.Fatal("failed to rollback")
 	}
-	if _, err = conn.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true}); err == nil {
-		t.Fatal("should have failed.")
+	tx, err = conn.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true, Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		t.Fatal("failed to start read-only transaction.")
 	}
-	if driverErr, ok := err.(*SnowflakeError); !ok || driverErr.Number != ErrNoReadOnlyTransaction {
-		t.Fatalf("should have returned Snowflake Error: %v", errMsgNoReadOnlyTransaction)
+	if err = tx.Rollback(); err != nil {
+		t.Fatal("failed to rollback")
 	}
 	if _, err = conn.BeginTx(context.Background(), &sql.TxOptions{Isolation: 100}); err == nil {
 		t.Fatal("should have failed.")
@@ -126,3 +130,42 @@ func TestTransactionError(t *testing.T) {
 	assertNotNilF(t, err, "")
 	assertEqualE(t, err.Error(), "driver: bad connection")
 }
+
+func TestTransactionIsolationAndReadOnly(t *testing.T) {
+	var statements []string
+	postQueryMock := func(_ context.Context, _ *snowflakeRestful,
+		_ *url.Values, _ map[string]string, body []byte, _ time.Duration,
+		_ UUID, _ *Config) (*execResponse, error) {
+		var req execRequest
+		assertNilF(t, json.Unmarshal(body, &req))
+		statements = append(statements, req.SQLText)
+		return &execResponse{Code: "0", Success: true}, nil
+	}
+	sc := &snowflakeConn{
+		cfg:                 &Config{Params: map[string]*string{}},
+		rest:                &snowflakeRestful{FuncPostQuery: postQueryMock},
+		queryContextCache:   (&queryContextCache{}).init(),
+		currentTimeProvider: defaultTimeProvider,
+	}
+
+	_, err := sc.BeginTx(context.Background(), driver.TxOptions{Isolation: driver.IsolationLevel(sql.LevelSerializable)})
+	assertNotNilF(t, err)
+	var driverErr *SnowflakeError
+	assertTrueF(t, errors.As(err, &driverErr))
+	assertEqualE(t, driverErr.Number, ErrNoDefaultTransactionIsolationLevel)
+	assertStringContainsE(t, err.Error(), "isolation level Serializable is not supported")
+	assertEqualE(t, len(statements), 0)
+
+	tx, err := sc.BeginTx(context.Background(), driver.TxOptions{Isolation: driver.IsolationLevel(sql.LevelReadCommitted), ReadOnly: true})
+	assertNilF(t, err)
+	_, err = sc.QueryContext(context.Background(), "  select 1", nil)
+	assertNilF(t, err)
+	_, err = sc.ExecContext(context.Background(), "INSERT INTO t VALUES (1)", nil)
+	assertTrueF(t, errors.As(err, &driverErr))
+	assertEqualE(t, driverErr.Number, ErrNoReadOnlyTransaction)
+	assertNilF(t, tx.Commit())
+	assertDeepEqualE(t, statements, []string{"BEGIN", "  select 1", "COMMIT"})
+
+	_, err = sc.ExecContext(context.Background(), "INSERT INTO t VALUES (1)", nil)
+	assertNilE(t, err, "read-only mode should end with the transaction")
+}
@@ -0,0 +1,64 @@
+package gosnowflake
+
+import (
+	"container/list"
+	"crypto/x509"
+	"net/http"
+	"sync"
+)
+
+// maxCachedTransports bounds each cache of the transports derived from the shared ones.
+// The connections keep using the transports dropped from the cache, and the configs
+// with the same settings get a new one.
+const maxCachedTransports = 64
+
+type transportCacheEntry struct {
+	base      *http.Transport
+	settings  any
+	rootCAs   *x509.CertPool
+	transport *http.Transport
+}
+
+// transportCache is an LRU cache of the transports derived from a transport with the settings
+// of a config, so that the connections with the same settings share the idle connections.
+// The settings are compared with ==, so they are kept alive along with the base transport
+// as long as the derived transport is cached. The root CA pools are compared by content,
+// as each config loading a rootCAsFile gets a new pool.
+type transportCache struct {
+	mutex    sync.Mutex
+	capacity int
+	order    *list.List // of *transportCacheEntry, the most recently used first
+}
+
+func newTransportCache(capacity int) *transportCache {
+	return &transportCache{
+		capacity: capacity,
+		order:    list.New(),
+	}
+}
+
+// getOrCreate returns the cached transport derived from the base with the settings and root CAs,
+// or caches and returns the one created by create.
+func (tc *transportCache) getOrCreate(base *http.Transport, settings any, rootCAs *x509.CertPool, create func() *http.Transport) *http.Transport {
+	tc.mutex.Lock()
+	defer tc.mutex.Unlock()
+	for elem := tc.order.Front(); elem != nil; elem = elem.Next() {
+		entry := elem.Value.(*transportCacheEntry)
+		if entry.base == base && entry.settings == settings && entry.rootCAs.Equal(rootCAs) {
+			tc.order.MoveToFront(elem)
+			return entry.transport
+		}
+	}
+	transport := create()
+	tc.order.PushFront(&transportCacheEntry{base: base, settings: settings, rootCAs: rootCAs, transport: transport})
+	for tc.order.Len() > tc.capacity {
+		tc.order.Remove(tc.order.Back())
+	}
+	return transport
+}
+
+func (tc *transportCache) len() int {
+	tc.mutex.Lock()
+	defer tc.mutex.Unlock()
+	return tc.order.Len()
+}
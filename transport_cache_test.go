@@ -0,0 +1,50 @@
+package gosnowflake
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"testing"
+)
+
+func TestTransportCache(t *testing.T) {
+	tc := newTransportCache(2)
+	base := &http.Transport{}
+	created := 0
+	create := func() *http.Transport {
+		created++
+		return base.Clone()
+	}
+
+	first := tc.getOrCreate(base, "first", nil, create)
+	assertTrueE(t, tc.getOrCreate(base, "first", nil, create) == first, "transports with the same settings should be shared")
+	assertFalseE(t, tc.getOrCreate(&http.Transport{}, "first", nil, create) == first, "transports derived from another one should not be shared")
+	assertEqualE(t, created, 2)
+	assertEqualE(t, tc.len(), 2)
+
+	second := tc.getOrCreate(base, "second", nil, create)
+	assertFalseE(t, second == first)
+	assertEqualE(t, tc.len(), 2, "the cache should be bounded")
+	assertFalseE(t, tc.getOrCreate(base, "first", nil, create) == first, "the least recently used transport should have been dropped")
+	assertTrueE(t, tc.getOrCreate(base, "second", nil, create) == second)
+}
+
+func TestTransportCacheComparesRootCAsByContent(t *testing.T) {
+	tc := newTransportCache(maxCachedTransports)
+	base := &http.Transport{}
+	create := func() *http.Transport {
+		return base.Clone()
+	}
+	_, caCert := createCa(t, nil, nil, "root CA", "")
+	_, otherCaCert := createCa(t, nil, nil, "other root CA", "")
+	newPool := func(cert *x509.Certificate) *x509.CertPool {
+		pool := x509.NewCertPool()
+		pool.AddCert(cert)
+		return pool
+	}
+
+	transport := tc.getOrCreate(base, tls.VersionTLS12, newPool(caCert), create)
+	assertTrueE(t, tc.getOrCreate(base, tls.VersionTLS12, newPool(caCert), create) == transport, "pools with the same certificates should share the transport")
+	assertFalseE(t, tc.getOrCreate(base, tls.VersionTLS12, newPool(otherCaCert), create) == transport)
+	assertFalseE(t, tc.getOrCreate(base, tls.VersionTLS12, nil, create) == transport)
+}
@@ -23,6 +23,7 @@ const (
 	snowflakeRequestIDKey            contextKey = "SNOWFLAKE_REQUEST_ID"
 	fetchResultByID                  contextKey = "SF_FETCH_RESULT_BY_ID"
 	fileStreamFile                   contextKey = "STREAMING_PUT_FILE"
+	fileStreamLength                 contextKey = "STREAMING_PUT_FILE_LENGTH"
 	fileGetStream                    contextKey = "STREAMING_GET_FILE"
 	fileTransferOptions              contextKey = "FILE_TRANSFER_OPTIONS"
 	enableHigherPrecision            contextKey = "ENABLE_HIGHER_PRECISION"
@@ -34,6 +35,23 @@ const (
 	enableStructuredTypes            contextKey = "ENABLE_STRUCTURED_TYPES"
 	mapValuesNullable                contextKey = "MAP_VALUES_NULLABLE"
 	arrayValuesNullable              contextKey = "ARRAY_VALUES_NULLABLE"
+	queryResultFormat                contextKey = "GO_QUERY_RESULT_FORMAT"
+	queryWarehouse                   contextKey = "WAREHOUSE"
+	queryRole                        contextKey = "ROLE"
+	queryDatabase                    contextKey = "DATABASE"
+	querySchema                      contextKey = "SCHEMA"
+	geographyOutputFormat            contextKey = "GEOGRAPHY_OUTPUT_FORMAT"
+	geometryOutputFormat             contextKey = "GEOMETRY_OUTPUT_FORMAT"
+	useCachedResult                  contextKey = "USE_CACHED_RESULT"
+	binaryOutputEncoding             contextKey = "BINARY_OUTPUT_ENCODING"
+	binaryOutputFormat               contextKey = "BINARY_OUTPUT_FORMAT"
+	rowsPerResultSet                 contextKey = "ROWS_PER_RESULTSET"
+	statementTimeoutInSeconds        contextKey = "STATEMENT_TIMEOUT_IN_SECONDS"
+	lowercaseColumnNames             contextKey = "LOWERCASE_COLUMN_NAMES"
+	geospatialValues                 contextKey = "GEOSPATIAL_VALUES"
+	retryOnErrorNumbers              contextKey = "RETRY_ON_ERROR_NUMBERS"
+	returnGeneratedKey               contextKey = "RETURN_GENERATED_KEY"
+	queryCanceller                   contextKey = "QUERY_CANCELLER"
 )
 
 const (
@@ -41,6 +59,8 @@ const (
 	internalQuery       contextKey = "INTERNAL_QUERY"
 	cancelRetry         contextKey = "CANCEL_RETRY"
 	streamChunkDownload contextKey = "STREAM_CHUNK_DOWNLOAD"
+	ocspCacheOptionsKey contextKey = "OCSP_CACHE_OPTIONS"
+	ocspTransportKey    contextKey = "OCSP_TRANSPORT"
 )
 
 var (
@@ -62,9 +82,41 @@ func WithQueryIDChan(ctx context.Context, c chan<- string) context.Context {
 	return context.WithValue(ctx, queryIDChannel, c)
 }
 
-// WithRequestID returns a new context with the specified snowflake request id
+// WithRequestID returns a new context with the specified snowflake request id.
+// The request id is sent in the requestId query parameter of the query requests.
+// If the request id is the zero UUID, the driver generates one for every query and
+// the one used by the latest query can be read with RequestIDFromContext.
 func WithRequestID(ctx context.Context, requestID UUID) context.Context {
-	return context.WithValue(ctx, snowflakeRequestIDKey, requestID)
+	return context.WithValue(ctx, snowflakeRequestIDKey, &requestIDHolder{requested: requestID})
+}
+
+// RequestIDFromContext returns the request id set in the context with WithRequestID or,
+// if that one is the zero UUID, the request id generated for the latest query run with the context.
+// The second return value is false if there is no request id to report.
+func RequestIDFromContext(ctx context.Context) (UUID, bool) {
+	holder, ok := ctx.Value(snowflakeRequestIDKey).(*requestIDHolder)
+	if !ok {
+		return nilUUID, false
+	}
+	requestID := holder.get()
+	return requestID, requestID != nilUUID
+}
+
+// requestIDHolder keeps the request id requested by the caller and the one generated
+// for the latest query when the caller didn't request any.
+type requestIDHolder struct {
+	mu        sync.Mutex
+	requested UUID
+	generated UUID
+}
+
+func (h *requestIDHolder) get() UUID {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.requested != nilUUID {
+		return h.requested
+	}
+	return h.generated
 }
 
 // WithStreamDownloader returns a context that allows the use of a stream based chunk downloader
@@ -82,6 +134,14 @@ func WithFileStream(ctx context.Context, reader io.Reader) context.Context {
 	return context.WithValue(ctx, fileStreamFile, reader)
 }
 
+// WithFileStreamLength returns a context that contains the file stream to be PUT
+// and the number of bytes to upload from it. Exactly length bytes are read, so the stream
+// doesn't need to end, e.g. a pipe the producer keeps open. The PUT fails if the stream
+// ends earlier, or with ErrInvalidFileStreamLength if the length is negative.
+func WithFileStreamLength(ctx context.Context, reader io.Reader, length int64) context.Context {
+	return context.WithValue(WithFileStream(ctx, reader), fileStreamLength, length)
+}
+
 // WithFileGetStream returns a context that contains the address of the file stream to be GET
 func WithFileGetStream(ctx context.Context, writer io.Writer) context.Context {
 	return context.WithValue(ctx, fileGetStream, writer)
@@ -153,6 +213,163 @@ func WithQueryTag(ctx context.Context, tag string) context.Context {
 	return context.WithValue(ctx, queryTag, tag)
 }
 
+// WithResultFormat returns a context that requests the results of the queries
+// in the given format, e.g. ResultFormatJSON to receive JSON rows instead of Arrow.
+// The format of the session is not changed.
+func WithResultFormat(ctx context.Context, format resultFormat) context.Context {
+	return context.WithValue(ctx, queryResultFormat, format)
+}
+
+// WithWarehouse returns a context that runs the queries on the given warehouse.
+// The warehouse of the session is not changed.
+func WithWarehouse(ctx context.Context, warehouse string) context.Context {
+	return context.WithValue(ctx, queryWarehouse, warehouse)
+}
+
+// WithRole returns a context that runs the queries with the given role.
+// The role of the session is not changed.
+func WithRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, queryRole, role)
+}
+
+// WithDatabase returns a context that runs the queries in the given database.
+// The database of the session is not changed.
+func WithDatabase(ctx context.Context, database string) context.Context {
+	return context.WithValue(ctx, queryDatabase, database)
+}
+
+// WithSchema returns a context that runs the queries in the given schema.
+// The schema of the session is not changed.
+func WithSchema(ctx context.Context, schema string) context.Context {
+	return context.WithValue(ctx, querySchema, schema)
+}
+
+// WithGeographyOutputFormat returns a context that returns GEOGRAPHY values of the queries
+// in the given format: GeoJSON, WKT, EWKT, WKB or EWKB. The format of the session is not changed.
+func WithGeographyOutputFormat(ctx context.Context, format string) context.Context {
+	return context.WithValue(ctx, geographyOutputFormat, format)
+}
+
+// WithGeometryOutputFormat returns a context that returns GEOMETRY values of the queries
+// in the given format: GeoJSON, WKT, EWKT, WKB or EWKB. The format of the session is not changed.
+func WithGeometryOutputFormat(ctx context.Context, format string) context.Context {
+	return context.WithValue(ctx, geometryOutputFormat, format)
+}
+
+// WithGeospatialValues returns a context that returns GEOGRAPHY and GEOMETRY values of the queries
+// as Geospatial, which keeps the SRID of the value next to its WKB. The values are fetched as EWKB
+// for these queries only; WithGeographyOutputFormat and WithGeometryOutputFormat are ignored.
+func WithGeospatialValues(ctx context.Context) context.Context {
+	return context.WithValue(ctx, geospatialValues, true)
+}
+
+func usesGeospatialValues(ctx context.Context) bool {
+	v, _ := ctx.Value(geospatialValues).(bool)
+	return v
+}
+
+// WithBinaryOutputEncoding returns a context that returns BINARY values of the queries
+// as hex or base64 encoded strings instead of raw bytes.
+func WithBinaryOutputEncoding(ctx context.Context, encoding BinaryEncoding) context.Context {
+	return context.WithValue(ctx, binaryOutputEncoding, encoding)
+}
+
+// WithBinaryOutputFormat returns a context that sets the BINARY_OUTPUT_FORMAT of the queries to
+// HEX or BASE64 and returns their BINARY values as strings in this format, like Snowflake renders them.
+// The format of the session is not changed. WithBinaryOutputEncoding takes precedence for the values.
+func WithBinaryOutputFormat(ctx context.Context, format string) context.Context {
+	return context.WithValue(ctx, binaryOutputFormat, format)
+}
+
+// WithRowLimit returns a context whose queries return at most n rows. The server stops producing
+// rows after n+1 rows, so the rows are those the query returns first, in ORDER BY order if the query
+// has one, and no further chunks are downloaded. SnowflakeRows.Truncated reports whether the query
+// had more rows. A limit of 0 or less returns all rows.
+func WithRowLimit(ctx context.Context, n int64) context.Context {
+	return context.WithValue(ctx, rowsPerResultSet, n)
+}
+
+// rowLimit returns the row limit set with WithRowLimit, 0 if there is none.
+func rowLimit(ctx context.Context) int64 {
+	if ctx == nil {
+		return 0
+	}
+	n, _ := ctx.Value(rowsPerResultSet).(int64)
+	return max(n, 0)
+}
+
+// WithStatementTimeout returns a context whose statements are canceled by the server when they
+// run longer than d, by setting STATEMENT_TIMEOUT_IN_SECONDS for them only. The server expects
+// whole seconds, so d is rounded up to the next second. The timeout of the session is not changed.
+// A duration of 0 or less doesn't override the timeout of the session.
+func WithStatementTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, statementTimeoutInSeconds, d)
+}
+
+// statementTimeoutSeconds returns the timeout set with WithStatementTimeout in whole seconds,
+// 0 if there is none.
+func statementTimeoutSeconds(ctx context.Context) int64 {
+	d, _ := ctx.Value(statementTimeoutInSeconds).(time.Duration)
+	if d <= 0 {
+		return 0
+	}
+	return int64((d + time.Second - 1) / time.Second)
+}
+
+// WithLowercaseColumnNames returns a context whose query results report the column names
+// in lowercase in Columns, e.g. to scan rows into maps keyed by the unquoted identifiers
+// which Snowflake reports in uppercase.
+func WithLowercaseColumnNames(ctx context.Context) context.Context {
+	return context.WithValue(ctx, lowercaseColumnNames, true)
+}
+
+func usesLowercaseColumnNames(ctx context.Context) bool {
+	v, _ := ctx.Value(lowercaseColumnNames).(bool)
+	return v
+}
+
+// WithRetryOnError returns a context whose statements are run again when they fail with one of
+// the given Snowflake error numbers, e.g. 630 for a statement that reached its timeout or 625
+// for a statement aborted because of lock contention. A statement is retried up to
+// Config.MaxRetryCount times with the same backoff as retried HTTP requests.
+// By using this context the caller asserts that the statements are idempotent: a failed
+// statement may have been partially applied and is run again as a whole.
+func WithRetryOnError(ctx context.Context, numbers ...int) context.Context {
+	return context.WithValue(ctx, retryOnErrorNumbers, numbers)
+}
+
+func retryOnErrors(ctx context.Context) []int {
+	numbers, _ := ctx.Value(retryOnErrorNumbers).([]int)
+	return numbers
+}
+
+// WithGeneratedKey returns a context whose Exec results report the value returned by the statement
+// as LastInsertId. Snowflake has no INSERT ... RETURNING, so the statement has to return the generated
+// key itself as a single row with a single integer column, e.g. a Snowflake Scripting block that inserts
+// the row and returns the value of the identity column, or a stored procedure that does the same.
+// Exec fails if the statement returns anything else.
+func WithGeneratedKey(ctx context.Context) context.Context {
+	return context.WithValue(ctx, returnGeneratedKey, true)
+}
+
+func returnsGeneratedKey(ctx context.Context) bool {
+	v, _ := ctx.Value(returnGeneratedKey).(bool)
+	return v
+}
+
+// WithQueryCanceller returns a context whose queries can be aborted together with the CancelAll
+// method of the canceller, whichever connection of the pool they run on.
+func WithQueryCanceller(ctx context.Context, canceller *QueryCanceller) context.Context {
+	return context.WithValue(ctx, queryCanceller, canceller)
+}
+
+// WithoutResultCache returns a context whose queries don't reuse results of earlier queries
+// from the result cache, by setting USE_CACHED_RESULT to FALSE for them only.
+// The USE_CACHED_RESULT value of the session is not changed.
+func WithoutResultCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, useCachedResult, false)
+}
+
 // WithStructuredTypesEnabled changes how structured types are returned.
 // Without this context structured types are returned as strings.
 // With this context enabled, structured types are returned as native Go types.
@@ -179,13 +396,24 @@ func WithInternal(ctx context.Context) context.Context {
 
 // Get the request ID from the context if specified, otherwise generate one
 func getOrGenerateRequestIDFromContext(ctx context.Context) UUID {
-	requestID, ok := ctx.Value(snowflakeRequestIDKey).(UUID)
-	if ok && requestID != nilUUID {
-		return requestID
+	holder, ok := ctx.Value(snowflakeRequestIDKey).(*requestIDHolder)
+	if ok && holder.requested != nilUUID {
+		return holder.requested
 	}
 	return NewUUID()
 }
 
+// recordRequestID makes the request ID of a query available through RequestIDFromContext.
+func recordRequestID(ctx context.Context, requestID UUID) {
+	holder, ok := ctx.Value(snowflakeRequestIDKey).(*requestIDHolder)
+	if !ok || holder.requested != nilUUID {
+		return
+	}
+	holder.mu.Lock()
+	defer holder.mu.Unlock()
+	holder.generated = requestID
+}
+
 // integer min
 func intMin(a, b int) int {
 	if a < b {
@@ -301,6 +529,15 @@ func escapeForCSV(value string) string {
 	return value
 }
 
+// stringLiteralEscaper escapes the backslashes before the quotes, so a value ending
+// with a backslash cannot escape the closing quote of the literal.
+var stringLiteralEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
+// quoteStringLiteral returns the value as a single-quoted SQL string literal.
+func quoteStringLiteral(value string) string {
+	return "'" + stringLiteralEscaper.Replace(value) + "'"
+}
+
 // GetFromEnv is used to get the value of an environment variable from the system
 func GetFromEnv(name string, failOnMissing bool) (string, error) {
 	if value := os.Getenv(name); value != "" {
@@ -344,6 +344,24 @@ func TestEscapeForCSV(t *testing.T) {
 	}
 }
 
+func TestQuoteStringLiteral(t *testing.T) {
+	testcases := []struct {
+		in  string
+		out string
+	}{
+		{"", "''"},
+		{"tag", "'tag'"},
+		{"it's", `'it\'s'`},
+		{`ends with \`, `'ends with \\'`},
+		{`\' OR 1=1 --`, `'\\\' OR 1=1 --'`},
+	}
+	for _, test := range testcases {
+		t.Run(test.in, func(t *testing.T) {
+			assertEqualE(t, quoteStringLiteral(test.in), test.out)
+		})
+	}
+}
+
 func TestGetFromEnv(t *testing.T) {
 	os.Setenv("SF_TEST", "test")
 	defer os.Unsetenv("SF_TEST")